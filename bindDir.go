@@ -0,0 +1,168 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"strings"
+	"unicode"
+)
+
+// BindDir is the direction of a bind placeholder.
+type BindDir uint8
+
+const (
+	// BindDirUnknown is returned when the direction cannot be determined,
+	// i.e. for the arguments of a packaged PL/SQL procedure call.
+	BindDirUnknown = BindDir(0)
+	// BindIn is an input placeholder: pass a value.
+	BindIn = BindDir(1)
+	// BindOut is an output placeholder: pass a pointer.
+	BindOut = BindDir(2)
+	// BindInOut is an input and output placeholder: pass a pointer.
+	BindInOut = BindDir(3)
+)
+
+func (d BindDir) String() string {
+	switch d {
+	case BindIn:
+		return "IN"
+	case BindOut:
+		return "OUT"
+	case BindInOut:
+		return "IN OUT"
+	}
+	return "UNKNOWN"
+}
+
+// bindCall is a procedure call argument a placeholder is passed as.
+type bindCall struct {
+	proc string // name of the called procedure, as written
+	pos  int    // 1-based position of the argument
+	arg  string // upper-case name of the argument in named notation (arg => :x)
+}
+
+// bindDirections returns the direction of each of the bind names,
+// as used in the sql text.
+//
+// Assignment targets (:x := ...) and INTO lists (RETURNING ... INTO :x, :y)
+// are outputs, every other use is an input; a placeholder used both ways
+// is BindInOut. The direction of a placeholder passed as an argument of a
+// procedure call in a PL/SQL block is asked from argDir; it is
+// BindDirUnknown if argDir is nil.
+func bindDirections(sql string, bindNames []string, isPLSQL bool, argDir func(bindCall) BindDir) []BindDir {
+	dirs := make(map[string]BindDir, len(bindNames))
+	var inInto, dotted bool
+	var prevPunct byte
+	var ident string     // the last (dotted) identifier
+	var calls []bindCall // the enclosing parentheses
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			// skip string literals
+			for i++; i < len(sql) && sql[i] != '\''; i++ {
+			}
+			prevPunct, inInto = c, false
+			ident, dotted = "", false
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(sql)
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+				i += j + 3
+			} else {
+				i = len(sql)
+			}
+		case unicode.IsSpace(rune(c)):
+		case c == '=' && strings.HasPrefix(sql[i:], "=>"):
+			// named notation: an argument follows, as after a comma
+			prevPunct, inInto = ',', false
+			ident, dotted = "", false
+			i++
+		case c == ':' && i+1 < len(sql) && isBindNameChar(sql[i+1]):
+			j := i + 1
+			for j < len(sql) && isBindNameChar(sql[j]) {
+				j++
+			}
+			name := strings.ToUpper(sql[i+1 : j])
+			rest := strings.TrimLeftFunc(sql[j:], unicode.IsSpace)
+			dir := BindIn
+			if inInto || strings.HasPrefix(rest, ":=") {
+				dir = BindOut
+			} else if isPLSQL && (prevPunct == '(' || prevPunct == ',') &&
+				rest != "" && (rest[0] == ',' || rest[0] == ')') {
+				dir = BindDirUnknown
+				if argDir != nil && len(calls) != 0 && calls[len(calls)-1].proc != "" {
+					dir = argDir(calls[len(calls)-1])
+				}
+			}
+			if old, ok := dirs[name]; ok && old != dir {
+				if old == BindDirUnknown || dir == BindDirUnknown {
+					dir = BindDirUnknown
+				} else {
+					dir = BindInOut
+				}
+			}
+			dirs[name] = dir
+			prevPunct = 0
+			ident, dotted = "", false
+			i = j - 1
+		case isBindNameChar(c):
+			j := i
+			for j < len(sql) && isBindNameChar(sql[j]) {
+				j++
+			}
+			word := sql[i:j]
+			inInto = strings.EqualFold(word, "INTO")
+			if dotted {
+				ident += "." + word
+			} else {
+				ident = word
+			}
+			dotted = false
+			if len(calls) != 0 && strings.HasPrefix(strings.TrimLeftFunc(sql[j:], unicode.IsSpace), "=>") {
+				calls[len(calls)-1].arg = strings.ToUpper(word)
+			}
+			prevPunct = 0
+			i = j - 1
+		default:
+			if c != ',' {
+				inInto = false
+			}
+			switch c {
+			case '(':
+				calls = append(calls, bindCall{proc: ident, pos: 1})
+			case ',':
+				if len(calls) != 0 {
+					calls[len(calls)-1].pos++
+					calls[len(calls)-1].arg = ""
+				}
+			case ')':
+				if len(calls) != 0 {
+					calls = calls[:len(calls)-1]
+				}
+			}
+			if c == '.' && ident != "" {
+				dotted = true
+			} else {
+				ident, dotted = "", false
+			}
+			prevPunct = c
+		}
+	}
+	result := make([]BindDir, len(bindNames))
+	for i, name := range bindNames {
+		result[i] = dirs[strings.ToUpper(name)]
+	}
+	return result
+}
+
+func isBindNameChar(c byte) bool {
+	return c == '_' || c == '$' || c == '#' ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
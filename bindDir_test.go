@@ -0,0 +1,61 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBindDirections(t *testing.T) {
+	for i, tc := range []struct {
+		sql     string
+		names   []string
+		isPLSQL bool
+		want    []BindDir
+	}{
+		{"SELECT * FROM T WHERE A = :1 AND B = ':2'", []string{"1"}, false,
+			[]BindDir{BindIn}},
+		{"INSERT INTO T (A) VALUES (:a) RETURNING id, b INTO :id, :b", []string{"A", "ID", "B"}, false,
+			[]BindDir{BindIn, BindOut, BindOut}},
+		{"BEGIN :ret := f(:x + 1); :cnt := :cnt + 1; END;", []string{"RET", "X", "CNT"}, true,
+			[]BindDir{BindOut, BindIn, BindInOut}},
+		{"BEGIN /* :c */ proc(:a, :b); -- :d\nEND;", []string{"A", "B"}, true,
+			[]BindDir{BindDirUnknown, BindDirUnknown}},
+		{"BEGIN SELECT COUNT(0) INTO :n FROM T WHERE A = :a; END;", []string{"N", "A"}, true,
+			[]BindDir{BindOut, BindIn}},
+	} {
+		got := bindDirections(tc.sql, tc.names, tc.isPLSQL, nil)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%d. %q: got %v, want %v.", i, tc.sql, got, tc.want)
+		}
+	}
+}
+
+func TestBindDirectionsArgDir(t *testing.T) {
+	var calls []bindCall
+	argDir := func(call bindCall) BindDir {
+		calls = append(calls, call)
+		if call.proc == "pkg.proc" {
+			return BindDirUnknown
+		}
+		return BindOut
+	}
+	got := bindDirections(
+		"BEGIN proc(:a, f(:b), p_c => :c); pkg.proc(:d); END;",
+		[]string{"A", "B", "C", "D"}, true, argDir)
+	if want := []BindDir{BindOut, BindOut, BindOut, BindDirUnknown}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v.", got, want)
+	}
+	wantCalls := []bindCall{
+		{proc: "proc", pos: 1},
+		{proc: "f", pos: 1},
+		{proc: "proc", pos: 3, arg: "P_C"},
+		{proc: "pkg.proc", pos: 1},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("got calls %+v, want %+v.", calls, wantCalls)
+	}
+}
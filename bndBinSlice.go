@@ -13,6 +13,10 @@ import (
 	"unsafe"
 )
 
+// maxRawBindLen is the maximum length of a RAW bind:
+// RAW columns hold 2000 bytes, or 32767 with MAX_STRING_SIZE=EXTENDED.
+const maxRawBindLen = 32767
+
 type bndBinSlice struct {
 	stmt   *Stmt
 	ocibnd *C.OCIBind
@@ -21,21 +25,42 @@ type bndBinSlice struct {
 }
 
 func (bnd *bndBinSlice) bindOra(values []Raw, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
-	binValues := make([][]byte, len(values))
-	nullInds := make([]C.sb2, len(values))
+	binValues := make([][]byte, len(values), cap(values))
+	nullInds := bnd.presetNullInds(len(values), cap(values))
 	for i := range values {
 		if values[i].IsNull {
 			nullInds[i] = C.sb2(-1)
 		} else {
+			nullInds[i] = 0
 			binValues[i] = values[i].Value
 		}
 	}
 	return bnd.bind(binValues, nullInds, position, lobBufferSize, stmt, isAssocArray)
 }
 
+// bindBytes binds the byte slices as RAWs, the nil ones as NULL.
+// The elements may be maxRawBindLen bytes long at most.
+func (bnd *bndBinSlice) bindBytes(values [][]byte, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
+	nullInds := bnd.presetNullInds(len(values), cap(values))
+	for i, b := range values {
+		if len(b) > maxRawBindLen {
+			return 0, errF("element %d of %d bytes exceeds the RAW bind limit of %d bytes; use []BlobBytes for BLOBs", i, len(b), maxRawBindLen)
+		}
+		if b == nil {
+			nullInds[i] = C.sb2(-1)
+		} else {
+			nullInds[i] = 0
+		}
+	}
+	return bnd.bind(values, nullInds, position, lobBufferSize, stmt, isAssocArray)
+}
+
 func (bnd *bndBinSlice) bind(values [][]byte, nullInds []C.sb2, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
 	bnd.stmt = stmt
 	L, C := len(values), cap(values)
+	if nullInds != nil {
+		bnd.nullInds = nullInds
+	}
 	iterations, curlenp, needAppend := bnd.ensureBindArrLength(&L, &C, isAssocArray)
 	if needAppend {
 		values = append(values, []byte{})
@@ -49,6 +74,11 @@ func (bnd *bndBinSlice) bind(values [][]byte, nullInds []C.sb2, position namedPo
 	if maxLen == 0 {
 		maxLen = 1
 	}
+	// RAW, or LONG RAW over the RAW bind limit
+	dty := C.ub2(C.SQLT_BIN)
+	if maxLen > maxRawBindLen {
+		dty = C.SQLT_LBI
+	}
 	n := maxLen * L
 	if cap(bnd.buf) < n {
 		//bnd.buf = make([]byte, n)
@@ -77,7 +107,7 @@ func (bnd *bndBinSlice) bind(values [][]byte, nullInds []C.sb2, position namedPo
 		phLen,
 		unsafe.Pointer(&bnd.buf[0]),      //void         *valuep,
 		C.LENGTH_TYPE(maxLen),            //sb8          value_sz,
-		C.SQLT_LBI,                       //ub2          dty,
+		dty,                              //ub2          dty,
 		unsafe.Pointer(&bnd.nullInds[0]), //void         *indp,
 		&bnd.alen[0],                     //ub4          *alenp,
 		&bnd.rcode[0],                    //ub2          *rcodep,
@@ -34,6 +34,19 @@ func (bnd *bndBoolSlice) bindOra(values []Bool, position namedPos, falseRune run
 	return bnd.bind(boolValues, nullInds, position, falseRune, trueRune, stmt)
 }
 
+func (bnd *bndBoolSlice) bindPtr(values []*bool, position namedPos, falseRune rune, trueRune rune, stmt *Stmt) error {
+	boolValues := make([]bool, len(values))
+	nullInds := make([]C.sb2, len(values))
+	for n, value := range values {
+		if value == nil {
+			nullInds[n] = C.sb2(-1)
+		} else {
+			boolValues[n] = *value
+		}
+	}
+	return bnd.bind(boolValues, nullInds, position, falseRune, trueRune, stmt)
+}
+
 func (bnd *bndBoolSlice) bind(values []bool, nullInds []C.sb2, position namedPos, falseRune rune, trueRune rune, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
 	if nullInds == nil {
@@ -49,16 +49,12 @@ func (bnd *bndDateSlice) bindOra(values *[]Date, position namedPos, stmt *Stmt,
 	} else {
 		T = T[:len(V)]
 	}
-	if cap(bnd.nullInds) < cap(V) {
-		bnd.nullInds = make([]C.sb2, len(V), cap(V))
-	} else {
-		bnd.nullInds = bnd.nullInds[:len(V)]
-	}
+	bnd.presetNullInds(len(V), cap(V))
 	for n := range V {
 		if V[n].IsNull() {
 			bnd.nullInds[n] = C.sb2(-1)
 		} else {
-			bnd.nullInds[0] = 0
+			bnd.nullInds[n] = 0
 			T[n] = V[n].Date.Get()
 		}
 	}
@@ -17,39 +17,47 @@ type bndFloat64 struct {
 	stmt      *Stmt
 	ocibnd    *C.OCIBind
 	ociNumber [1]C.OCINumber
+	binary    [1]float64
 }
 
 func (bnd *bndFloat64) bind(value float64, position namedPos, stmt *Stmt) error {
 	bnd.stmt = stmt
-	r := C.OCINumberFromReal(
-		bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
-		unsafe.Pointer(&value),      //const void          *rnum,
-		byteWidth64,                 //uword               rnum_length,
-		&bnd.ociNumber[0])           //OCINumber           *number );
-	if r == C.OCI_ERROR {
-		return bnd.stmt.ses.srv.env.ociError()
+	valuep, valueSz, dty := unsafe.Pointer(&bnd.ociNumber[0]), C.LENGTH_TYPE(C.sizeof_OCINumber), C.ub2(C.SQLT_VNU)
+	if isNonFinite(value) {
+		// OCINumber can't hold Inf and NaN, BINARY_DOUBLE can
+		bnd.binary[0] = value
+		valuep, valueSz, dty = unsafe.Pointer(&bnd.binary[0]), byteWidth64, C.SQLT_BDOUBLE
+	} else {
+		r := C.OCINumberFromReal(
+			bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
+			unsafe.Pointer(&value),      //const void          *rnum,
+			byteWidth64,                 //uword               rnum_length,
+			&bnd.ociNumber[0])           //OCINumber           *number );
+		if r == C.OCI_ERROR {
+			return bnd.stmt.ses.srv.env.ociError()
+		}
 	}
 
 	ph, phLen, phFree := position.CString()
 	if ph != nil {
 		defer phFree()
 	}
-	r = C.bindByNameOrPos(
+	r := C.bindByNameOrPos(
 		bnd.stmt.ocistmt, //OCIStmt      *stmtp,
 		&bnd.ocibnd,
 		bnd.stmt.ses.srv.env.ocierr, //OCIError     *errhp,
 		C.ub4(position.Ordinal),     //ub4          position,
 		ph,
 		phLen,
-		unsafe.Pointer(&bnd.ociNumber[0]), //void         *valuep,
-		C.LENGTH_TYPE(C.sizeof_OCINumber), //sb8          value_sz,
-		C.SQLT_VNU,                        //ub2          dty,
-		nil,                               //void         *indp,
-		nil,                               //ub2          *alenp,
-		nil,                               //ub2          *rcodep,
-		0,                                 //ub4          maxarr_len,
-		nil,                               //ub4          *curelep,
-		C.OCI_DEFAULT)                     //ub4          mode );
+		valuep,        //void         *valuep,
+		valueSz,       //sb8          value_sz,
+		dty,           //ub2          dty,
+		nil,           //void         *indp,
+		nil,           //ub2          *alenp,
+		nil,           //ub2          *rcodep,
+		0,             //ub4          maxarr_len,
+		nil,           //ub4          *curelep,
+		C.OCI_DEFAULT) //ub4          mode );
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
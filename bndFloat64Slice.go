@@ -22,6 +22,9 @@ type bndFloat64Slice struct {
 	ociNumbers []C.OCINumber
 	values     *[]Float64
 	floats     *[]float64
+	// binary is set when the floats are bound as BINARY_DOUBLE,
+	// as some of them can't be converted to OCINumber (Inf, NaN)
+	binary bool
 	arrHlp
 }
 
@@ -38,11 +41,7 @@ func (bnd *bndFloat64Slice) bindOra(values *[]Float64, position namedPos, stmt *
 	} else {
 		V = V[:L]
 	}
-	if cap(bnd.nullInds) < C {
-		bnd.nullInds = make([]C.sb2, L, C)
-	} else {
-		bnd.nullInds = bnd.nullInds[:L]
-	}
+	bnd.presetNullInds(L, C)
 	bnd.values = values
 	for n, v := range *values {
 		if v.IsNull {
@@ -77,11 +76,22 @@ func (bnd *bndFloat64Slice) bind(values *[]float64, position namedPos, stmt *Stm
 	} else {
 		bnd.ociNumbers = bnd.ociNumbers[:L]
 	}
-	alen := C.ACTUAL_LENGTH_TYPE(C.sizeof_OCINumber)
+	bnd.binary = false
+	for _, f := range V {
+		if isNonFinite(f) {
+			bnd.binary = true
+			break
+		}
+	}
+	valuep, valueSz, dty := unsafe.Pointer(&bnd.ociNumbers[0]), C.ub4(C.sizeof_OCINumber), C.ub2(C.SQLT_VNU)
+	if bnd.binary {
+		valuep, valueSz, dty = unsafe.Pointer(&V[0]), byteWidth64, C.SQLT_BDOUBLE
+	}
+	alen := C.ACTUAL_LENGTH_TYPE(valueSz)
 	for n := range V {
 		bnd.alen[n] = alen
 	}
-	if len(V) > 0 {
+	if len(V) > 0 && !bnd.binary {
 		if r := C.numberFromFloatSlice(
 			bnd.stmt.ses.srv.env.ocierr, //OCIError            *err,
 			unsafe.Pointer(&V[0]),       //const void          *rnum,
@@ -105,9 +115,9 @@ func (bnd *bndFloat64Slice) bind(values *[]float64, position namedPos, stmt *Stm
 		C.ub4(position.Ordinal),     //ub4          position,
 		ph,
 		phLen,
-		unsafe.Pointer(&bnd.ociNumbers[0]), //void         *valuep,
-		C.LENGTH_TYPE(C.sizeof_OCINumber),  //sb8          value_sz,
-		C.SQLT_VNU,                         //ub2          dty,
+		valuep,                             //void         *valuep,
+		C.LENGTH_TYPE(valueSz),             //sb8          value_sz,
+		dty,                                //ub2          dty,
 		unsafe.Pointer(&bnd.nullInds[0]),   //void         *indp,
 		&bnd.alen[0],                       //ub4          *alenp,
 		&bnd.rcode[0],                      //ub2          *rcodep,
@@ -120,7 +130,7 @@ func (bnd *bndFloat64Slice) bind(values *[]float64, position namedPos, stmt *Stm
 	r = C.OCIBindArrayOfStruct(
 		bnd.ocibnd,
 		bnd.stmt.ses.srv.env.ocierr,
-		C.ub4(C.sizeof_OCINumber),          //ub4         pvskip,
+		C.ub4(valueSz),                     //ub4         pvskip,
 		C.ub4(C.sizeof_sb2),                //ub4         indskip,
 		C.ub4(C.sizeof_ACTUAL_LENGTH_TYPE), //ub4         alskip,
 		C.ub4(C.sizeof_ub2))                //ub4         rcskip
@@ -155,7 +165,13 @@ func (bnd *bndFloat64Slice) setPtr() error {
 		*bnd.values = V
 	}
 	for i, number := range bnd.ociNumbers[:n] {
-		if bnd.nullInds[i] > C.sb2(-1) {
+		if bnd.binary && bnd.nullInds[i] > C.sb2(-1) {
+			// OCI has written the value into F
+			if bnd.values != nil {
+				V[i].IsNull = false
+				V[i].Value = F[i]
+			}
+		} else if bnd.nullInds[i] > C.sb2(-1) {
 			arr := F[i : i+1 : i+1]
 			r := C.OCINumberToReal(
 				bnd.stmt.ses.srv.env.ocierr, //OCIError              *err,
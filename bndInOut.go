@@ -0,0 +1,60 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import "reflect"
+
+// InOut binds Slice as an IN OUT associative array:
+// the elements are sent to the server, and after Stmt.ExeP
+// the same slice holds the values modified by the procedure.
+//
+// Slice may be a slice or a pointer to a slice.
+// With a slice, the elements are copied back in place, so the length
+// stays the same. With a pointer to a slice, the slice is resliced
+// to the number of elements returned by the server.
+type InOut struct {
+	Slice interface{}
+}
+
+// bindValue returns the pointer to slice to be bound, and the bndInOut
+// wrapper which copies the returned elements back, if needed.
+func (v InOut) bindValue() (interface{}, *bndInOut, error) {
+	rv := reflect.ValueOf(v.Slice)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.Elem().Kind() == reflect.Slice {
+			return v.Slice, nil, nil
+		}
+	case reflect.Slice:
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		return p.Interface(), &bndInOut{dst: rv, src: p}, nil
+	}
+	return nil, nil, errF("InOut needs a slice or a pointer to a slice, got %T", v.Slice)
+}
+
+// bndInOut wraps the bnd of a slice bound through InOut,
+// to copy the returned elements back to the original slice.
+type bndInOut struct {
+	bnd
+	dst, src reflect.Value
+}
+
+func (bnd *bndInOut) setPtr() error {
+	if err := bnd.bnd.setPtr(); err != nil {
+		return err
+	}
+	reflect.Copy(bnd.dst, bnd.src.Elem())
+	return nil
+}
+
+func (bnd *bndInOut) close() error {
+	b := bnd.bnd
+	bnd.bnd, bnd.dst, bnd.src = nil, reflect.Value{}, reflect.Value{}
+	if b == nil {
+		return nil
+	}
+	return b.close()
+}
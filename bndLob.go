@@ -35,7 +35,7 @@ type bndLob struct {
 //
 // None of the chunks can be empty, so we have to pre-read the next chunk,
 // before sending the actual, to know whether this is the last or not.
-func (bnd *bndLob) bindReader(rdr io.Reader, position namedPos, lobBufferSize int, sqlt C.ub2, stmt *Stmt) (err error) {
+func (bnd *bndLob) bindReader(rdr io.Reader, position namedPos, lobBufferSize int, sqlt C.ub2, csid C.ub2, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
 	bnd.sqlt = sqlt
 	if lobBufferSize <= 0 {
@@ -47,7 +47,7 @@ func (bnd *bndLob) bindReader(rdr io.Reader, position namedPos, lobBufferSize in
 		return err
 	}
 
-	if err = writeLob(bnd.lobLocatorp.Value(), bnd.stmt, rdr, lobBufferSize); err != nil {
+	if err = writeLob(bnd.lobLocatorp.Value(), bnd.stmt, rdr, lobBufferSize, csid); err != nil {
 		bnd.stmt.ses.Break()
 		finish()
 		return err
@@ -96,7 +96,7 @@ func (bnd *bndLob) close() (err error) {
 
 func (bnd *bndLob) allocTempLob() (finish func(), err error) {
 	var lob *C.OCILobLocator
-	lob, finish, err = allocTempLob(bnd.stmt)
+	lob, finish, err = allocTempLob(bnd.stmt, tempLobType(bnd.sqlt))
 	if err == nil {
 		*(bnd.lobLocatorp.Pointer()) = lob
 	}
@@ -131,7 +131,13 @@ func (bnd *bndLob) bindByPos(position namedPos) error {
 	return nil
 }
 
-func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBufferSize int) error {
+// writeLob writes the bytes of r to the LOB. For a CLOB, csid is the
+// character set of the bytes (0 means the client character set).
+func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBufferSize int, csid C.ub2) error {
+	// write in multiples of the chunk size, if possible
+	if chunkSize, err := lobGetChunkSize(stmt.ses, ociLobLocator); err == nil {
+		lobBufferSize = alignToChunk(lobBufferSize, chunkSize)
+	}
 	var actBuf, nextBuf []byte
 	if lobChunkSize >= lobBufferSize {
 		arr := lobChunkPool.Get().([lobChunkSize]byte)
@@ -208,7 +214,7 @@ func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBuffer
 			actPiece,         //ub1             piece,
 			nil,              //void            *ctxp,
 			nil,              //OCICallbackLobWrite2 (cbfp)
-			C.ub2(0),         //ub2             csid,
+			csid,             //ub2             csid,
 			C.SQLCS_IMPLICIT, //ub1             csfrm );
 		//fmt.Printf("r %v, current %v, buffer %v\n", r, current, buffer)
 		//fmt.Printf("C.OCI_NEED_DATA %v, C.OCI_SUCCESS %v\n", C.OCI_NEED_DATA, C.OCI_SUCCESS)
@@ -225,7 +231,16 @@ func writeLob(ociLobLocator *C.OCILobLocator, stmt *Stmt, r io.Reader, lobBuffer
 	return nil
 }
 
-func allocTempLob(stmt *Stmt) (ociLobLocator *C.OCILobLocator, finish func(), err error) {
+// tempLobType returns the temporary LOB type (OCI_TEMP_BLOB or OCI_TEMP_CLOB)
+// of the bind type, so a CLOB is converted between the character sets.
+func tempLobType(sqlt C.ub2) C.ub1 {
+	if sqlt == C.SQLT_CLOB {
+		return C.OCI_TEMP_CLOB
+	}
+	return C.OCI_TEMP_BLOB
+}
+
+func allocTempLob(stmt *Stmt, lobType C.ub1) (ociLobLocator *C.OCILobLocator, finish func(), err error) {
 	locatorp := (**C.OCILobLocator)(C.malloc(C.sof_LobLocatorp))
 	defer C.free(unsafe.Pointer(locatorp))
 	// Allocate lob locator handle
@@ -244,6 +259,10 @@ func allocTempLob(stmt *Stmt) (ociLobLocator *C.OCILobLocator, finish func(), er
 		return nil, nil, errNew("unable to allocate oci lob handle during bind")
 	}
 
+	cache := C.boolean(C.FALSE)
+	if stmt.Cfg().TempLobCache {
+		cache = C.TRUE
+	}
 	// Create temporary lob
 	r = C.OCILobCreateTemporary(
 		stmt.ses.ocisvcctx,      //OCISvcCtx          *svchp,
@@ -251,8 +270,8 @@ func allocTempLob(stmt *Stmt) (ociLobLocator *C.OCILobLocator, finish func(), er
 		ociLobLocator,           //OCILobLocator      *locp,
 		C.OCI_DEFAULT,           //ub2                csid,
 		C.SQLCS_IMPLICIT,        //ub1                csfrm,
-		C.OCI_TEMP_BLOB,         //ub1                lobtype,
-		C.TRUE,                  //boolean            cache,
+		lobType,                 //ub1                lobtype,
+		cache,                   //boolean            cache,
 		C.OCI_DURATION_SESSION)  //OCIDuration        duration);
 	if r == C.OCI_ERROR {
 		// free lob locator handle
@@ -19,7 +19,7 @@ type bndLobPtr struct {
 	lobLocatorp
 }
 
-func (bnd *bndLobPtr) bindLob(lob *Lob, position namedPos, lobBufferSize int, sqlt C.ub2, stmt *Stmt) (err error) {
+func (bnd *bndLobPtr) bindLob(lob *Lob, position namedPos, lobBufferSize int, sqlt C.ub2, csid C.ub2, stmt *Stmt) (err error) {
 	bnd.stmt = stmt
 	bnd.value = lob
 	bnd.sqlt = sqlt
@@ -33,7 +33,7 @@ func (bnd *bndLobPtr) bindLob(lob *Lob, position namedPos, lobBufferSize int, sq
 	}
 
 	if lob != nil && lob.Reader != nil {
-		if err = writeLob(bnd.lobLocatorp.Value(), bnd.stmt, lob.Reader, lobBufferSize); err != nil {
+		if err = writeLob(bnd.lobLocatorp.Value(), bnd.stmt, lob.Reader, lobBufferSize, csid); err != nil {
 			bnd.stmt.ses.Break()
 			finish()
 			return err
@@ -66,6 +66,7 @@ func (bnd *bndLobPtr) setPtr() error {
 		csid:          csid,
 		csfrm:         csfrm,
 		Length:        lobLength,
+		opened:        true,
 	}
 	bnd.value.Reader, bnd.value.Closer = lr, lr
 	return nil
@@ -100,7 +101,7 @@ func (bnd *bndLobPtr) close() (err error) {
 
 func (bnd *bndLobPtr) allocTempLob() (finish func(), err error) {
 	var lob *C.OCILobLocator
-	lob, finish, err = allocTempLob(bnd.stmt)
+	lob, finish, err = allocTempLob(bnd.stmt, tempLobType(bnd.sqlt))
 	if err == nil {
 		*(bnd.lobLocatorp.Pointer()) = lob
 	}
@@ -10,7 +10,9 @@ package ora
 */
 import "C"
 import (
+	"bytes"
 	"io"
+	"strings"
 	"unsafe"
 )
 
@@ -20,6 +22,7 @@ type bndLobSlice struct {
 	ociLobLocators []*C.OCILobLocator
 	buf            []byte
 	readers        []io.Reader
+	values         []*Lob
 	arrHlp
 }
 
@@ -30,22 +33,122 @@ func (bnd *bndLobSlice) bindOra(values []Lob, position namedPos, lobBufferSize i
 	} else {
 		bnd.readers = bnd.readers[:L]
 	}
-	if cap(bnd.nullInds) < C {
-		bnd.nullInds = make([]C.sb2, L, C)
-	} else {
-		bnd.nullInds = bnd.nullInds[:L]
-	}
+	bnd.presetNullInds(L, C)
+	var kind lobSliceKind
 	for n := range values {
 		if values[n].Reader == nil {
 			bnd.nullInds[n] = C.sb2(-1)
+			bnd.readers[n] = nil
 		} else {
+			if err = kind.add(n, &values[n]); err != nil {
+				return 0, err
+			}
+			bnd.nullInds[n] = 0
 			bnd.readers[n] = values[n].Reader
 		}
 	}
-	return bnd.bindReaders(bnd.readers, position, lobBufferSize, stmt, isAssocArray)
+	sqlt, csid, err := kind.bindType(stmt.ses.srv.env)
+	if err != nil {
+		return 0, err
+	}
+	return bnd.bindReaders(bnd.readers, position, lobBufferSize, sqlt, csid, stmt, isAssocArray)
+}
+
+// bindBytes binds the byte slices as BLOBs, the nil ones as NULL.
+func (bnd *bndLobSlice) bindBytes(values []BlobBytes, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
+	L, C := len(values), cap(values)
+	if cap(bnd.readers) < C {
+		bnd.readers = make([]io.Reader, L, C)
+	} else {
+		bnd.readers = bnd.readers[:L]
+	}
+	bnd.presetNullInds(L, C)
+	for n, v := range values {
+		if v == nil {
+			bnd.nullInds[n] = C.sb2(-1)
+			bnd.readers[n] = nil
+		} else {
+			bnd.nullInds[n] = 0
+			bnd.readers[n] = bytes.NewReader(v)
+		}
+	}
+	return bnd.bindReaders(bnd.readers, position, lobBufferSize, C.SQLT_BLOB, 0, stmt, isAssocArray)
+}
+
+// bindLobPtrs binds the LOBs, and sets the Reader of each non-nil *Lob
+// to read the returned LOB in setPtr.
+//
+// The non-nil elements must agree in C and Charset, as they're bound as
+// an array of one type.
+func (bnd *bndLobSlice) bindLobPtrs(values []*Lob, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
+	L, C := len(values), cap(values)
+	if cap(bnd.readers) < C {
+		bnd.readers = make([]io.Reader, L, C)
+	} else {
+		bnd.readers = bnd.readers[:L]
+	}
+	bnd.presetNullInds(L, C)
+	var kind lobSliceKind
+	for n, v := range values {
+		if v != nil {
+			if err = kind.add(n, v); err != nil {
+				return 0, err
+			}
+		}
+		if v == nil || v.Reader == nil {
+			bnd.nullInds[n] = C.sb2(-1)
+			bnd.readers[n] = nil
+		} else {
+			bnd.nullInds[n] = 0
+			bnd.readers[n] = v.Reader
+		}
+	}
+	sqlt, csid, err := kind.bindType(stmt.ses.srv.env)
+	if err != nil {
+		return 0, err
+	}
+	bnd.values = values
+	return bnd.bindReaders(bnd.readers, position, lobBufferSize, sqlt, csid, stmt, isAssocArray)
+}
+
+// lobSliceKind collects the kind (C and Charset) of the elements of a Lob
+// slice, which must agree.
+type lobSliceKind struct {
+	set     bool
+	c       bool
+	charset string
+}
+
+// add adds the i-th element of the slice.
+func (k *lobSliceKind) add(i int, lob *Lob) error {
+	if !k.set {
+		k.set, k.c, k.charset = true, lob.C, lob.Charset
+		return nil
+	}
+	if lob.C != k.c {
+		return errF("Lob slice mixes CLOBs and BLOBs: element %d has C=%t, unlike the ones before.", i, lob.C)
+	}
+	if lob.C && !strings.EqualFold(lob.Charset, k.charset) {
+		return errF("Lob slice mixes character sets: element %d has Charset %q, not %q.", i, lob.Charset, k.charset)
+	}
+	return nil
+}
+
+// bindType returns the SQL type (SQLT_BLOB or SQLT_CLOB) and the character
+// set ID of the slice.
+func (k lobSliceKind) bindType(env *Env) (sqlt C.ub2, csid C.ub2, err error) {
+	if !k.c {
+		return C.SQLT_BLOB, 0, nil
+	}
+	if csid, err = env.charsetID(k.charset); err != nil {
+		return 0, 0, err
+	}
+	return C.SQLT_CLOB, csid, nil
 }
 
-func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBufferSize int, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
+// bindReaders binds the readers as temporary LOBs of the sqlt type
+// (SQLT_BLOB or SQLT_CLOB); csid is the character set of a CLOB's bytes.
+func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBufferSize int, sqlt C.ub2, csid C.ub2, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
 	bnd.stmt = stmt
 	// ensure we have at least 1 slot in the slice
 	L, C := len(values), cap(values)
@@ -77,7 +180,7 @@ func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBu
 	}()
 
 	for i, r := range values {
-		bnd.ociLobLocators[i], finishers[i], err = allocTempLob(bnd.stmt)
+		bnd.ociLobLocators[i], finishers[i], err = allocTempLob(bnd.stmt, tempLobType(sqlt))
 		if err != nil {
 			return iterations, err
 		}
@@ -86,7 +189,7 @@ func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBu
 		if bnd.nullInds[i] <= C.sb2(-1) {
 			continue
 		}
-		if err = writeLob(bnd.ociLobLocators[i], bnd.stmt, r, lobBufferSize); err != nil {
+		if err = writeLob(bnd.ociLobLocators[i], bnd.stmt, r, lobBufferSize, csid); err != nil {
 			bnd.stmt.ses.Break()
 			return iterations, err
 		}
@@ -105,7 +208,7 @@ func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBu
 		phLen,
 		unsafe.Pointer(&bnd.ociLobLocators[0]),              //void         *valuep,
 		C.LENGTH_TYPE(unsafe.Sizeof(bnd.ociLobLocators[0])), //sb8          value_sz,
-		C.SQLT_BLOB,                      //ub2          dty,
+		sqlt,                             //ub2          dty,
 		unsafe.Pointer(&bnd.nullInds[0]), //void         *indp,
 		&bnd.alen[0],                     //ub4          *alenp,
 		&bnd.rcode[0],                    //ub2          *rcodep,
@@ -131,6 +234,41 @@ func (bnd *bndLobSlice) bindReaders(values []io.Reader, position namedPos, lobBu
 }
 
 func (bnd *bndLobSlice) setPtr() error {
+	if bnd.values == nil {
+		return nil
+	}
+	n := len(bnd.values)
+	if bnd.IsAssocArr() && int(bnd.curlen) < n {
+		n = int(bnd.curlen)
+	}
+	for i, v := range bnd.values[:n] {
+		if v == nil {
+			continue
+		}
+		if bnd.nullInds[i] <= C.sb2(-1) {
+			v.Reader, v.Closer = nil, nil
+			continue
+		}
+		lob := bnd.ociLobLocators[i]
+		lobLength, csid, csfrm, err := lobOpen(bnd.stmt.ses, lob, C.OCI_LOB_READONLY)
+		if err != nil {
+			return err
+		}
+		chunkSize, _ := lobGetChunkSize(bnd.stmt.ses, lob)
+		// the lobReader owns the locator from now on
+		bnd.ociLobLocators[i] = nil
+		lr := &lobReader{
+			ses:           bnd.stmt.ses,
+			ociLobLocator: lob,
+			piece:         C.OCI_FIRST_PIECE,
+			csid:          csid,
+			csfrm:         csfrm,
+			Length:        lobLength,
+			opened:        true,
+			chunkSize:     chunkSize,
+		}
+		v.Reader, v.Closer = lr, lr
+	}
 	return nil
 }
 
@@ -142,6 +280,9 @@ func (bnd *bndLobSlice) close() (err error) {
 	}()
 
 	for n := 0; n < len(bnd.ociLobLocators); n++ {
+		if bnd.ociLobLocators[n] == nil {
+			continue
+		}
 		// free temporary lob
 		C.OCILobFreeTemporary(
 			bnd.stmt.ses.ocisvcctx,      //OCISvcCtx          *svchp,
@@ -159,7 +300,8 @@ func (bnd *bndLobSlice) close() (err error) {
 	bytesPool.Put(bnd.buf)
 	bnd.buf = nil
 	bnd.readers = nil
+	bnd.values = nil
 	bnd.arrHlp.close()
-	stmt.putBnd(bndIdxBinSlice, bnd)
+	stmt.putBnd(bndIdxLobSlice, bnd)
 	return nil
 }
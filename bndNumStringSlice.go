@@ -23,15 +23,12 @@ type bndNumStringSlice struct {
 
 func (bnd *bndNumStringSlice) bindOra(values []OraNum, position namedPos, stmt *Stmt, isAssocArray bool) (iterations uint32, err error) {
 	stringValues := make([]Num, len(values))
-	if cap(bnd.nullInds) < len(values) {
-		bnd.nullInds = make([]C.sb2, len(values))
-	} else {
-		bnd.nullInds = bnd.nullInds[:len(values)]
-	}
+	bnd.presetNullInds(len(values), cap(values))
 	for n := range values {
 		if values[n].IsNull {
 			bnd.nullInds[n] = C.sb2(-1)
 		} else {
+			bnd.nullInds[n] = 0
 			stringValues[n] = Num(values[n].Value)
 		}
 	}
@@ -10,14 +10,28 @@ package ora
 */
 import "C"
 import (
+	"context"
 	"unsafe"
 )
 
+// InCursor binds the cursor of an open Rset as an IN SYS_REFCURSOR
+// parameter, i.e. to pass a cursor received as an OUT *Rset from one
+// procedure call to another.
+//
+// The rows already fetched (or prefetched) by Rset are not seen by the
+// receiver, which continues fetching from the cursor; Rset remains
+// owned, and must be closed, by its statement.
+type InCursor struct {
+	Rset *Rset
+}
+
 type bndRset struct {
 	stmt    *Stmt
 	ocibnd  *C.OCIBind
 	ocistmt [1]*C.OCIStmt
 	value   *Rset
+	// in is set for an InCursor, whose handle is not ours to open
+	in bool
 	nullp
 }
 
@@ -32,6 +46,22 @@ func (bnd *bndRset) bind(value *Rset, position namedPos, stmt *Stmt) error {
 	if err != nil {
 		return err
 	}
+	return bnd.bindHandle(position)
+}
+
+// bindIn binds the statement handle of the open value as an IN cursor.
+func (bnd *bndRset) bindIn(value *Rset, position namedPos, stmt *Stmt) error {
+	bnd.stmt = stmt
+	bnd.stmt.logF(_drv.Cfg().Log.Stmt.Bind, "%p pos=%v in", bnd, position)
+	value.RLock()
+	bnd.ocistmt[0] = value.ocistmt
+	value.RUnlock()
+	bnd.in = true
+	return bnd.bindHandle(position)
+}
+
+func (bnd *bndRset) bindHandle(position namedPos) error {
+	stmt := bnd.stmt
 	ph, phLen, phFree := position.CString()
 	if ph != nil {
 		defer phFree()
@@ -60,7 +90,7 @@ func (bnd *bndRset) bind(value *Rset, position namedPos, stmt *Stmt) error {
 }
 
 func (bnd *bndRset) setPtr() error {
-	if bnd.IsNull() || bnd.ocistmt[0] == nil {
+	if bnd.in || bnd.IsNull() || bnd.ocistmt[0] == nil {
 		return nil
 	}
 	err := bnd.value.open(bnd.stmt, bnd.ocistmt[0])
@@ -76,7 +106,7 @@ func (bnd *bndRset) setPtr() error {
 	}
 	// open result set is successful; will be freed by Rset
 	bnd.stmt.openRsets.add(bnd.value)
-	return bnd.stmt.setPrefetchSize()
+	return bnd.stmt.setPrefetchSize(context.Background())
 }
 
 func (bnd *bndRset) close() (err error) {
@@ -90,6 +120,7 @@ func (bnd *bndRset) close() (err error) {
 	bnd.ocibnd = nil
 	bnd.ocistmt[0] = nil
 	bnd.value = nil
+	bnd.in = false
 	bnd.nullp.Free()
 	stmt.putBnd(bndIdxRset, bnd)
 	return nil
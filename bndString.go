@@ -58,7 +58,7 @@ func (bnd *bndString) bind(value string, position namedPos, stmt *Stmt) error {
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
-	return nil
+	return bnd.stmt.setBindMaxDataSize(bnd.ocibnd, len(value))
 }
 
 func (bnd *bndString) setPtr() error {
@@ -20,6 +20,7 @@ type bndStringPtr struct {
 	valueIsNull *bool
 	buf         []byte
 	alen        [1]C.ACTUAL_LENGTH_TYPE
+	rcode       [1]C.ub2
 	nullp
 }
 
@@ -92,14 +93,14 @@ func (bnd *bndStringPtr) bind(value *string, valueIsNull *bool, position namedPo
 		C.SQLT_CHR,                          //ub2          dty,
 		unsafe.Pointer(bnd.nullp.Pointer()), //void         *indp,
 		&bnd.alen[0],                        //ub2          *alenp,
-		nil,                                 //ub2          *rcodep,
+		&bnd.rcode[0],                       //ub2          *rcodep,
 		0,                                   //ub4          maxarr_len,
 		nil,                                 //ub4          *curelep,
 		C.OCI_DEFAULT)                       //ub4          mode );
 	if r == C.OCI_ERROR {
 		return bnd.stmt.ses.srv.env.ociError()
 	}
-	return nil
+	return bnd.stmt.setBindMaxDataSize(bnd.ocibnd, cap(bnd.buf))
 }
 
 func (bnd *bndStringPtr) setPtr() error {
@@ -110,14 +111,22 @@ func (bnd *bndStringPtr) setPtr() error {
 		return nil
 	}
 	bnd.stmt.logF(_drv.Cfg().Log.Stmt.Bind,
-		"StringPtr.setPtr isNull=%t alen=%d", bnd.nullp.IsNull(), bnd.alen[0])
+		"StringPtr.setPtr isNull=%t alen=%d rcode=%d", bnd.nullp.IsNull(), bnd.alen[0], bnd.rcode[0])
 
-	if !bnd.nullp.IsNull() {
-		*bnd.value = string(bnd.buf[:bnd.alen[0]])
-	} else {
+	if bnd.nullp.IsNull() {
 		*bnd.value = ""
+		return nil
+	}
+	// alen is the returned length, which is less than the buffer size
+	// for shorter values; more, if the value has been truncated (ORA-01406)
+	n := int(bnd.alen[0])
+	if n > cap(bnd.buf) {
+		n = cap(bnd.buf)
+	}
+	*bnd.value = string(bnd.buf[:n])
+	if bnd.rcode[0] == 1406 {
+		return errF("value of %d bytes truncated to %d (StmtCfg.StringPtrBufferSize)", bnd.alen[0], n)
 	}
-
 	return nil
 }
 
@@ -135,6 +144,7 @@ func (bnd *bndStringPtr) close() (err error) {
 	bnd.value = nil
 	bnd.valueIsNull = nil
 	bnd.alen[0] = 0
+	bnd.rcode[0] = 0
 	bytesPool.Put(bnd.buf)
 	bnd.buf = nil
 	bnd.nullp.Free()
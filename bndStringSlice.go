@@ -31,11 +31,7 @@ func (bnd *bndStringSlice) bindOra(values *[]String, position namedPos, stmt *St
 	} else {
 		*bnd.strings = (*bnd.strings)[:L]
 	}
-	if cap(bnd.nullInds) < C {
-		bnd.nullInds = make([]C.sb2, L, C)
-	} else {
-		bnd.nullInds = bnd.nullInds[:L]
-	}
+	bnd.presetNullInds(L, C)
 	bnd.values = values
 	for n, v := range *values {
 		if v.IsNull {
@@ -112,7 +108,7 @@ func (bnd *bndStringSlice) bind(values *[]string, position namedPos, stmt *Stmt,
 	if r == C.OCI_ERROR {
 		return iterations, bnd.stmt.ses.srv.env.ociError()
 	}
-	return iterations, nil
+	return iterations, bnd.stmt.setBindMaxDataSize(bnd.ocibnd, bnd.maxLen)
 }
 
 func (bnd *bndStringSlice) setPtr() error {
@@ -22,7 +22,17 @@ type bndTime struct {
 }
 
 func (bnd *bndTime) bind(value time.Time, position namedPos, stmt *Stmt) error {
+	return bnd.bindAs(value, C.SQLT_TIMESTAMP_TZ, position, stmt)
+}
+
+// bindAs binds the value as sqlt: SQLT_TIMESTAMP or SQLT_TIMESTAMP_TZ.
+func (bnd *bndTime) bindAs(value time.Time, sqlt C.ub2, position namedPos, stmt *Stmt) error {
 	bnd.stmt = stmt
+	if sqlt == C.SQLT_TIMESTAMP {
+		bnd.dateTimep.setDescType(C.OCI_DTYPE_TIMESTAMP)
+	} else {
+		bnd.dateTimep.setDescType(C.OCI_DTYPE_TIMESTAMP_TZ)
+	}
 	if err := bnd.dateTimep.Set(bnd.stmt.ses.srv.env, value); err != nil {
 		return err
 	}
@@ -39,7 +49,7 @@ func (bnd *bndTime) bind(value time.Time, position namedPos, stmt *Stmt) error {
 		phLen,
 		unsafe.Pointer(bnd.dateTimep.Pointer()), //void         *valuep,
 		C.LENGTH_TYPE(bnd.dateTimep.Size()),     //sb8          value_sz,
-		C.SQLT_TIMESTAMP_TZ,                     //ub2          dty,
+		sqlt,                                    //ub2          dty,
 		nil,                                     //void         *indp,
 		nil,                                     //ub2          *alenp,
 		nil,                                     //ub2          *rcodep,
@@ -69,7 +69,7 @@ func (bnd *bndTimePtr) setPtr() (err error) {
 		*bnd.value = time.Time{} // zero time
 		return nil
 	}
-	*bnd.value, err = getTime(bnd.stmt.ses.srv.env, bnd.dateTimep.Value())
+	*bnd.value, err = getTime(bnd.stmt.ses.srv.env, bnd.dateTimep.Value(), bnd.stmt.Cfg().TimeZoneRegion)
 	return err
 }
 
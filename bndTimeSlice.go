@@ -38,16 +38,12 @@ func (bnd *bndTimeSlice) bindOra(values []Time, position namedPos, stmt *Stmt, i
 	} else {
 		bnd.times = bnd.times[:len(values)]
 	}
-	if cap(bnd.nullInds) < cap(values) {
-		bnd.nullInds = make([]C.sb2, len(values), cap(values))
-	} else {
-		bnd.nullInds = bnd.nullInds[:len(values)]
-	}
+	bnd.presetNullInds(len(values), cap(values))
 	for n := range values {
 		if values[n].IsNull {
 			bnd.nullInds[n] = C.sb2(-1)
 		} else {
-			bnd.nullInds[0] = 0
+			bnd.nullInds[n] = 0
 			bnd.times[n] = values[n].Value
 		}
 	}
@@ -68,6 +64,7 @@ func (bnd *bndTimeSlice) bind(values []time.Time, position namedPos, stmt *Stmt,
 		bnd.ociDateTimes = bnd.ociDateTimes[:L]
 	}
 	valueSz := C.ACTUAL_LENGTH_TYPE(C.sof_OCIDateTime)
+	// each element keeps its own zone offset
 	timezones := make(map[int][]byte, 2)
 	for n, timeValue := range values {
 		_, off := timeValue.Zone()
@@ -77,7 +74,7 @@ func (bnd *bndTimeSlice) bind(values []time.Time, position namedPos, stmt *Stmt,
 			timezones[off] = tz
 		}
 		arr := bnd.ociDateTimes[n : n+1 : n+1]
-		if err := (&dateTimep{p: arr}).Set(bnd.stmt.ses.srv.env, timeValue); err != nil {
+		if err := (&dateTimep{p: arr}).setZone(bnd.stmt.ses.srv.env, timeValue, tz); err != nil {
 			return iterations, err
 		}
 		bnd.alen[n] = valueSz
@@ -146,7 +143,7 @@ func (bnd *bndTimeSlice) setPtr() error {
 	var err error
 	for i, dt := range bnd.ociDateTimes[:n] {
 		if bnd.nullInds[i] > C.sb2(-1) {
-			if bnd.times[i], err = getTime(bnd.stmt.ses.srv.env, dt); err != nil {
+			if bnd.times[i], err = getTime(bnd.stmt.ses.srv.env, dt, bnd.stmt.Cfg().TimeZoneRegion); err != nil {
 				return err
 			}
 			if bnd.values != nil {
@@ -33,11 +33,7 @@ func (bnd *bndUint16Slice) bindOra(values *[]Uint16, position namedPos, stmt *St
 	} else {
 		ints = ints[:L]
 	}
-	if cap(bnd.nullInds) < C {
-		bnd.nullInds = make([]C.sb2, L, C)
-	} else {
-		bnd.nullInds = bnd.nullInds[:L]
-	}
+	bnd.presetNullInds(L, C)
 	bnd.values = values
 	for n, v := range *values {
 		if v.IsNull {
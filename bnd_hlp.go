@@ -88,6 +88,25 @@ func (ll *lobLocatorp) Free() {
 type dateTimep struct {
 	p    []*C.OCIDateTime
 	zone []byte
+	// dtype is the descriptor type, OCI_DTYPE_TIMESTAMP_TZ if 0
+	dtype C.ub4
+}
+
+// descType returns the descriptor type of the OCIDateTime.
+func (dt *dateTimep) descType() C.ub4 {
+	if dt.dtype == 0 {
+		return C.OCI_DTYPE_TIMESTAMP_TZ
+	}
+	return dt.dtype
+}
+
+// setDescType sets the descriptor type, freeing the descriptor
+// allocated with another type.
+func (dt *dateTimep) setDescType(dtype C.ub4) {
+	if dt.descType() != dtype {
+		dt.Free()
+	}
+	dt.dtype = dtype
 }
 
 func (dt *dateTimep) Pointer() **C.OCIDateTime {
@@ -107,8 +126,8 @@ func (dt *dateTimep) Free() {
 	if dt.p != nil {
 		if dt.p[0] != nil {
 			C.OCIDescriptorFree(
-				unsafe.Pointer(dt.p[0]),  //void     *descp,
-				C.OCI_DTYPE_TIMESTAMP_TZ) //ub4      type );
+				unsafe.Pointer(dt.p[0]), //void     *descp,
+				dt.descType())           //ub4      type );
 			dt.p[0] = nil
 		}
 		C.free(unsafe.Pointer(&dt.p[0]))
@@ -119,7 +138,7 @@ func (dt *dateTimep) Alloc(env *Env) error {
 	r := C.OCIDescriptorAlloc(
 		unsafe.Pointer(env.ocienv),                      //CONST dvoid   *parenth,
 		(*unsafe.Pointer)(unsafe.Pointer(dt.Pointer())), //dvoid         **descpp,
-		C.OCI_DTYPE_TIMESTAMP_TZ,                        //ub4           type,
+		dt.descType(),                                   //ub4           type,
 		0,   //size_t        xtramem_sz,
 		nil) //dvoid         **usrmempp);
 	if r == C.OCI_ERROR {
@@ -130,25 +149,30 @@ func (dt *dateTimep) Alloc(env *Env) error {
 	return nil
 }
 func (dt *dateTimep) Set(env *Env, value time.Time) error {
+	dt.zone = zoneOffset(dt.zone[:0], value)
+	return dt.setZone(env, value, dt.zone)
+}
+
+// setZone sets the value, in the time zone given as "+HH:MM".
+func (dt *dateTimep) setZone(env *Env, value time.Time, zone []byte) error {
 	if dt.Value() == nil {
 		if err := dt.Alloc(env); err != nil {
 			return err
 		}
 	}
-	dt.zone = zoneOffset(dt.zone[:0], value)
 	r := C.OCIDateTimeConstruct(
-		unsafe.Pointer(env.ocienv),                //dvoid         *hndl,
-		env.ocierr,                                //OCIError      *err,
-		dt.Value(),                                //OCIDateTime   *datetime,
-		C.sb2(value.Year()),                       //sb2           year,
-		C.ub1(int32(value.Month())),               //ub1           month,
-		C.ub1(value.Day()),                        //ub1           day,
-		C.ub1(value.Hour()),                       //ub1           hour,
-		C.ub1(value.Minute()),                     //ub1           min,
-		C.ub1(value.Second()),                     //ub1           sec,
-		C.ub4(value.Nanosecond()),                 //ub4           fsec,
-		(*C.OraText)(unsafe.Pointer(&dt.zone[0])), //OraText       *timezone,
-		C.size_t(len(dt.zone)))                    //size_t        timezone_length );
+		unsafe.Pointer(env.ocienv),             //dvoid         *hndl,
+		env.ocierr,                             //OCIError      *err,
+		dt.Value(),                             //OCIDateTime   *datetime,
+		C.sb2(value.Year()),                    //sb2           year,
+		C.ub1(int32(value.Month())),            //ub1           month,
+		C.ub1(value.Day()),                     //ub1           day,
+		C.ub1(value.Hour()),                    //ub1           hour,
+		C.ub1(value.Minute()),                  //ub1           min,
+		C.ub1(value.Second()),                  //ub1           sec,
+		C.ub4(value.Nanosecond()),              //ub4           fsec,
+		(*C.OraText)(unsafe.Pointer(&zone[0])), //OraText       *timezone,
+		C.size_t(len(zone)))                    //size_t        timezone_length );
 	if r == C.OCI_ERROR {
 		return env.ociError()
 	}
@@ -161,6 +185,11 @@ func zoneOffset(buf []byte, value time.Time) []byte {
 		buf = append(buf, make([]byte, 6)...)[:n]
 	}
 	_, zoneOffsetInSeconds := value.Zone()
+	return appendZoneOffset(buf, zoneOffsetInSeconds)
+}
+
+// appendZoneOffset appends the offset given in seconds as +HH:MM to buf.
+func appendZoneOffset(buf []byte, zoneOffsetInSeconds int) []byte {
 	if zoneOffsetInSeconds < 0 {
 		buf = append(buf, '-')
 		zoneOffsetInSeconds *= -1
@@ -77,7 +77,7 @@ func (con *Con) checkIsOpen() error {
 // Once closed, a connection cannot be re-opened.
 // To open a new connection call Open on a driver.
 func (con *Con) IsOpen() bool {
-	return con.env != nil
+	return con.env != nil && con.ses.IsOpen()
 }
 
 // Close ends a session and disconnects from an Oracle server.
@@ -165,13 +165,11 @@ func (con *Con) sysName() string {
 
 // log writes a message with an Con system name and caller info.
 func (con *Con) log(enabled bool, v ...interface{}) {
-	if enabled {
-		if len(v) == 0 {
-			_drv.Cfg().Log.Logger.Infof("%v %v", con.sysName(), callInfo(1))
-		} else {
-			_drv.Cfg().Log.Logger.Infof("%v %v %v", con.sysName(), callInfo(1), fmt.Sprint(v...))
-		}
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
+		return
 	}
+	Log.emit(LogEvent{Component: "Con", Operation: callOp(1), SysName: con.sysName(), Msg: fmt.Sprint(v...)})
 }
 
 func isCanceled(err error) bool {
@@ -182,13 +180,14 @@ func maybeBadConn(err error) error {
 		return nil
 	}
 	// database/sql API expect driver.ErrBadConn to reconnect to the database
+	if IsConnLost(err) {
+		return driver.ErrBadConn
+	}
 	if cd, ok := err.(interface {
 		Code() int
 	}); ok {
 		switch cd.Code() {
-		case 3113, 3114, 12528, 12545:
-			// ORA-03113: end-of-file on communication channel
-			// ORA-03114: not connected to ORACLE
+		case 12528, 12545:
 			// ORA-12528: TNS:listener: all appropriate instances are blocking new connections
 			// ORA-12545: Connect failed because target host or object does not exist
 			return driver.ErrBadConn
@@ -0,0 +1,34 @@
+// +build go1.10
+
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// Ensure that Con implements driver.SessionResetter.
+var _ = driver.SessionResetter((*Con)(nil))
+
+// ResetSession is called by database/sql before reusing the connection:
+// it rolls back the transaction left open on the session, so it does not
+// bleed into the next user's requests.
+//
+// If the transaction state cannot be read (HasOpenTransaction needs
+// Oracle Client 12.1), the session is rolled back unconditionally.
+func (con *Con) ResetSession(ctx context.Context) error {
+	if err := con.checkIsOpen(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if open, err := con.ses.HasOpenTransaction(); err == nil && !open {
+		return nil
+	}
+	return maybeBadConn(con.ses.rollback())
+}
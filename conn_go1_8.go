@@ -13,11 +13,6 @@ import (
 	"fmt"
 )
 
-/*
-#include <oci.h>
-*/
-import "C"
-
 var (
 	// Ensure that Con implements the needed ...Context interfaces.
 	_ = driver.Conn((*Con)(nil))
@@ -68,15 +63,12 @@ func (con *Con) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx,
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-	var flags C.ub4
-	if opts.ReadOnly {
-		flags |= C.OCI_TRANS_READONLY
-	}
+	cfg := TxCfg{ReadOnly: opts.ReadOnly}
 	switch level := sql.IsolationLevel(opts.Isolation); level {
 	case sql.LevelDefault, sql.LevelReadCommitted:
 		// this is the default level
 	case sql.LevelSerializable:
-		flags |= C.OCI_TRANS_SERIALIZABLE
+		cfg.Serializable = true
 	default:
 		return nil, fmt.Errorf("Isolation level %v not supported.", level)
 	}
@@ -84,24 +76,11 @@ func (con *Con) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx,
 	if err := con.checkIsOpen(); err != nil {
 		return nil, err
 	}
-	var tx *Tx
-	done := make(chan error)
-	go func() {
-		defer close(done)
-		var err error
-		tx, err = con.ses.StartTx(TxFlags(uint32(flags)))
-		done <- err
-	}()
-	var err error
-	select {
-	case <-ctx.Done():
-		if err = ctx.Err(); isCanceled(err) {
-			con.ses.Break()
-		}
-	case err = <-done:
-		return tx, err
+	tx, err := con.ses.StartTxContext(ctx, cfg)
+	if err != nil {
+		return nil, maybeBadConn(err)
 	}
-	return nil, maybeBadConn(err)
+	return tx, nil
 }
 
 // vim: set fileencoding=utf-8 noet:
@@ -73,6 +73,25 @@ const (
 	OraN
 	// L defins an sql select column as an ora.Lob.
 	L
+	// TUnix defines a sql select column as a Go int64 of seconds elapsed
+	// since 1970-01-01 00:00:00 UTC (Unix epoch). NULL is returned as nil.
+	// DATE and TIMESTAMP values are taken to be in the session time zone.
+	TUnix
+	// TUnixMs defines a sql select column as a Go int64 of milliseconds
+	// elapsed since 1970-01-01 00:00:00 UTC (Unix epoch). NULL is returned as nil.
+	// DATE and TIMESTAMP values are taken to be in the session time zone.
+	TUnixMs
+	// TNls defines a DATE or TIMESTAMP sql select column as a Go string,
+	// formatted by Oracle as the session's NLS_DATE_FORMAT
+	// (NLS_TIMESTAMP_FORMAT, NLS_TIMESTAMP_TZ_FORMAT) renders it, as in
+	// SQL*Plus. NULL is returned as an empty string.
+	TNls
+	// Dur defines an INTERVAL DAY TO SECOND sql select column as a
+	// Go time.Duration. NULL is returned as nil.
+	Dur
+	// Months defines an INTERVAL YEAR TO MONTH sql select column as a
+	// Go int of the total months. NULL is returned as nil.
+	Months
 )
 
 func GctName(gct GoColumnType) string {
@@ -141,6 +160,16 @@ func GctName(gct GoColumnType) string {
 		return "OraN"
 	case L:
 		return "L"
+	case TUnix:
+		return "TUnix"
+	case TUnixMs:
+		return "TUnixMs"
+	case TNls:
+		return "TNls"
+	case Dur:
+		return "Dur"
+	case Months:
+		return "Months"
 	}
 	return ""
 }
@@ -251,4 +280,5 @@ const (
 	defIdxBfile
 	defIdxRowid
 	defIdxRset
+	defIdxOpaque
 )
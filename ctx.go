@@ -4,10 +4,17 @@
 
 package ora
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 const (
-	stmtCfgKey = "stmtCfg"
+	stmtCfgKey          = "stmtCfg"
+	prefetchRowCountKey = "prefetchRowCount"
+	progressKey         = "progress"
+	charsetKey          = "charset"
+	noCommitKey         = "noCommit"
 )
 
 // ctxStmtCfg returns the StmtCfg from the context, and
@@ -25,3 +32,92 @@ func ctxStmtCfg(ctx context.Context) (StmtCfg, bool) {
 func WithStmtCfg(ctx context.Context, cfg StmtCfg) context.Context {
 	return context.WithValue(ctx, stmtCfgKey, cfg)
 }
+
+// ctxPrefetchRowCount returns the prefetch row count from the context, and
+// whether it exist at all.
+func ctxPrefetchRowCount(ctx context.Context) (uint32, bool) {
+	n, ok := ctx.Value(prefetchRowCountKey).(uint32)
+	return n, ok
+}
+
+// WithPrefetch returns a new context, with the given number of rows to
+// prefetch during a select query.
+//
+// It overrides StmtCfg.PrefetchRowCount for the queries executed with
+// the returned context only, without changing the statement's StmtCfg,
+// so it is usable with database/sql's QueryContext.
+// Zero means using only StmtCfg.PrefetchMemorySize.
+func WithPrefetch(ctx context.Context, rows uint32) context.Context {
+	return context.WithValue(ctx, prefetchRowCountKey, rows)
+}
+
+// progressCfg is the configuration set by WithProgress.
+type progressCfg struct {
+	every    uint32
+	progress func(done, total int)
+}
+
+// ctxProgress returns the chunk size and the progress callback from the
+// context, set by WithProgress.
+func ctxProgress(ctx context.Context) (uint32, func(done, total int)) {
+	cfg, _ := ctx.Value(progressKey).(progressCfg)
+	return cfg.every, cfg.progress
+}
+
+// WithProgress returns a new context, which makes array DML (INSERT,
+// UPDATE, DELETE with slice binds) executed with it run in chunks of every
+// iterations (rows), calling progress after each chunk with the number of
+// rows done, and the total.
+//
+// The chunks execute successive parts of the bound arrays, with one bind.
+// The cancellation of ctx is checked between the chunks. As with a single
+// execution, an auto-committing statement commits only with the last chunk,
+// and rolls back if a chunk fails, or ctx is cancelled.
+//
+// Statements with RETURNING (or other pointer) binds are executed at once.
+func WithProgress(ctx context.Context, every int, progress func(done, total int)) context.Context {
+	if every < 0 {
+		every = 0
+	}
+	return context.WithValue(ctx, progressKey, progressCfg{every: uint32(every), progress: progress})
+}
+
+// ctxNoCommit reports whether the auto-commit is suppressed for the
+// execution with the context, by Stmt.ExeNoCommit.
+func ctxNoCommit(ctx context.Context) bool {
+	noCommit, _ := ctx.Value(noCommitKey).(bool)
+	return noCommit
+}
+
+// ctxCharset returns the character set name from the context, set by
+// WithCharset.
+func ctxCharset(ctx context.Context) string {
+	name, _ := ctx.Value(charsetKey).(string)
+	return name
+}
+
+// WithCharset returns a new context, which makes the queries executed with
+// it (Stmt.QryContext, database/sql's QueryContext) fetch the character
+// columns converted to the named character set, i.e. "WE8ISO8859P1", by
+// setting OCI_ATTR_CHARSET_ID on their defines.
+//
+// The strings hold the bytes in that character set, independently of the
+// client character set (AL32UTF8) of the Env. "" means the client character
+// set.
+func WithCharset(ctx context.Context, charset string) context.Context {
+	return context.WithValue(ctx, charsetKey, charset)
+}
+
+// ctxReader is an io.Reader which returns the error of ctx,
+// once ctx is done.
+type ctxReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.Reader.Read(p)
+}
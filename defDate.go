@@ -21,16 +21,21 @@ type defDate struct {
 	ociDef
 	ociDate    []date.Date
 	isNullable bool
+	epoch      GoColumnType
 	timezone   *time.Location
 }
 
-func (def *defDate) define(position int, isNullable bool, rset *Rset) error {
+func (def *defDate) define(position int, gct GoColumnType, rset *Rset) error {
 	var err error
 	if def.timezone, err = rset.stmt.ses.Timezone(); err != nil {
 		return err
 	}
 	def.rset = rset
-	def.isNullable = isNullable
+	def.isNullable = gct == OraT
+	def.epoch = 0
+	if isEpochColumn(gct) {
+		def.epoch = gct
+	}
 	if def.ociDate != nil {
 		C.free(unsafe.Pointer(&def.ociDate[0]))
 	}
@@ -46,6 +51,9 @@ func (def *defDate) value(offset int) (value interface{}, err error) {
 		}
 		return nil, nil
 	}
+	if def.epoch != 0 {
+		return epochValue(def.ociDate[offset].GetIn(def.timezone), def.epoch), nil
+	}
 	if def.isNullable {
 		return Time{Value: def.ociDate[offset].GetIn(def.timezone)}, nil
 	}
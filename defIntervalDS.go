@@ -18,10 +18,12 @@ import (
 type defIntervalDS struct {
 	ociDef
 	intervals []*C.OCIInterval
+	gct       GoColumnType
 }
 
-func (def *defIntervalDS) define(position int, rset *Rset) error {
+func (def *defIntervalDS) define(position int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
+	def.gct = gct
 	if def.intervals != nil {
 		C.free(unsafe.Pointer(&def.intervals[0]))
 	}
@@ -55,7 +57,16 @@ func (def *defIntervalDS) value(offset int) (value interface{}, err error) {
 		intervalDS.Second = int32(second)
 		intervalDS.Nanosecond = int32(nanosecond)
 	}
-	return intervalDS, err
+	if err != nil {
+		return nil, err
+	}
+	if def.gct == Dur {
+		if intervalDS.IsNull {
+			return nil, nil
+		}
+		return intervalDS.Duration()
+	}
+	return intervalDS, nil
 }
 
 func (def *defIntervalDS) alloc() error {
@@ -17,10 +17,12 @@ import (
 type defIntervalYM struct {
 	ociDef
 	intervals []*C.OCIInterval
+	gct       GoColumnType
 }
 
-func (def *defIntervalYM) define(position int, rset *Rset) error {
+func (def *defIntervalYM) define(position int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
+	def.gct = gct
 	if def.intervals != nil {
 		C.free(unsafe.Pointer(&def.intervals[0]))
 	}
@@ -45,7 +47,16 @@ func (def *defIntervalYM) value(offset int) (value interface{}, err error) {
 		intervalYM.Year = int32(year)
 		intervalYM.Month = int32(month)
 	}
-	return intervalYM, err
+	if err != nil {
+		return nil, err
+	}
+	if def.gct == Months {
+		if intervalYM.IsNull {
+			return nil, nil
+		}
+		return intervalYM.Months(), nil
+	}
+	return intervalYM, nil
 }
 
 func (def *defIntervalYM) alloc() error {
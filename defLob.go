@@ -35,10 +35,12 @@ type defLob struct {
 	gct  GoColumnType
 	sqlt C.ub2
 	lobs []*C.OCILobLocator
+	// prefetchSize is the StmtCfg.LobPrefetchSize of the define.
+	prefetchSize uint32
 	sync.Mutex
 }
 
-func (def *defLob) define(position int, sqlt C.ub2, gct GoColumnType, rset *Rset) error {
+func (def *defLob) define(position int, sqlt C.ub2, csfrm C.ub1, gct GoColumnType, rset *Rset) error {
 	def.Lock()
 	defer def.Unlock()
 	def.rset = rset
@@ -55,12 +57,105 @@ func (def *defLob) define(position int, sqlt C.ub2, gct GoColumnType, rset *Rset
 	if err := def.ociDef.defineByPos(position, unsafe.Pointer(&def.lobs[0]), int(C.sof_LobLocatorp), int(sqlt)); err != nil {
 		return err
 	}
+	if csfrm == C.SQLCS_NCHAR {
+		// NCLOB
+		if err := env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE,
+			unsafe.Pointer(&csfrm), 0, C.OCI_ATTR_CHARSET_FORM); err != nil {
+			return err
+		}
+	}
 	prefetchLength := C.boolean(C.TRUE)
+	if err := env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE,
+		unsafe.Pointer(&prefetchLength), 0, C.OCI_ATTR_LOBPREFETCH_LENGTH); err != nil {
+		return err
+	}
+	def.prefetchSize = rset.stmt.Cfg().LobPrefetchSize
+	if def.prefetchSize == 0 {
+		return nil
+	}
+	// the first prefetchSize bytes (characters for CLOBs) of the LOBs
+	// are fetched with the rows, into the locators.
+	prefetchSize := C.ub4(def.prefetchSize)
 	return env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE,
-		unsafe.Pointer(&prefetchLength), 0, C.OCI_ATTR_LOBPREFETCH_LENGTH)
+		unsafe.Pointer(&prefetchSize), 0, C.OCI_ATTR_LOBPREFETCH_SIZE)
+}
+
+// inline returns the whole LOB at offset, read from the prefetch cache
+// of its locator, without opening it, and frees the locator.
+//
+// ok is false, and the locator is kept, if the LOB is longer than the
+// prefetch size: those are read by streaming.
+func (def *defLob) inline(offset int) (value []byte, ok bool, err error) {
+	def.Lock()
+	defer def.Unlock()
+	lob, ses := def.lobs[offset], def.rset.stmt.ses
+	if lob == nil || def.prefetchSize == 0 {
+		return nil, false, nil
+	}
+	env := ses.srv.env
+	// served from the prefetch cache (OCI_ATTR_LOBPREFETCH_LENGTH)
+	var length C.oraub8
+	if C.OCILobGetLength2(
+		ses.ocisvcctx, //OCISvcCtx          *svchp,
+		env.ocierr,    //OCIError           *errhp,
+		lob,           //OCILobLocator      *locp,
+		&length,       //oraub8 *lenp)
+	) == C.OCI_ERROR {
+		return nil, false, env.ociError("OCILobGetLength2")
+	}
+	if length > C.oraub8(def.prefetchSize) {
+		return nil, false, nil
+	}
+	def.lobs[offset] = nil
+	defer C.OCIDescriptorFree(unsafe.Pointer(lob), //void     *descp,
+		C.OCI_DTYPE_LOB) //ub4      type );
+	if length == 0 {
+		return nil, true, nil
+	}
+	csid, csfrm, err := lobCharset(env, lob)
+	if err != nil {
+		return nil, true, err
+	}
+	byteAmt, charAmt := length, C.oraub8(0)
+	bufLen := length
+	if def.sqlt == C.SQLT_CLOB {
+		byteAmt, charAmt = 0, length
+		bufLen *= 4
+	}
+	buf := make([]byte, int(bufLen))
+	ses.logF(_drv.Cfg().Log.Ses.Close, "OCILobRead2(%p) inline length=%d\n", lob, length)
+	if C.OCILobRead2(
+		ses.ocisvcctx,           //OCISvcCtx          *svchp,
+		env.ocierr,              //OCIError           *errhp,
+		lob,                     //OCILobLocator      *locp,
+		&byteAmt,                //oraub8             *byteAmtp,
+		&charAmt,                //oraub8             *char_amtp,
+		1,                       //oraub8             offset, offset is 1-based
+		unsafe.Pointer(&buf[0]), //void               *bufp,
+		C.oraub8(len(buf)),      //oraub8             bufl,
+		C.OCI_ONE_PIECE,         //ub1                piece,
+		nil,                     //void               *ctxp,
+		nil,                     //OCICallbackLobRead2 (cbfp)
+		csid,                    //ub2                csid,
+		csfrm,                   //ub1                csfrm );
+	) == C.OCI_ERROR {
+		return nil, true, env.ociError("OCILobRead2")
+	}
+	buf = buf[:int(byteAmt)]
+	if csid == 2000 && len(buf) > 1 {
+		// UTF-16
+		u16 := make([]uint16, len(buf)/2)
+		binary.Read(bytes.NewReader(buf), binary.BigEndian, &u16)
+		buf = []byte(string(utf16.Decode(u16)))
+	}
+	return buf, true, nil
 }
 
 func (def *defLob) Bytes(offset int) (value []byte, err error) {
+	value, ok, err := def.inline(offset)
+	if ok || err != nil {
+		return value, err
+	}
 	r := def.Reader(offset)
 	defer r.Close()
 	lr := r.(*lobReader)
@@ -69,7 +164,7 @@ func (def *defLob) Bytes(offset int) (value []byte, err error) {
 	defer lobChunkPool.Put(arr)
 	var buf bytes.Buffer
 
-	n, err := r.Read(arr[:])
+	n, err := r.Read(arr[:alignToChunk(lobChunkSize, lr.ChunkSize())])
 	lr.ses.logF(_drv.Cfg().Log.Ses.Prep, "Bytes-1(%p) amt=%d err=%v\n", lr, n, err)
 	length := lr.Length
 	if length == 0 {
@@ -232,6 +327,9 @@ type lobReader struct {
 	csid          C.ub2
 	off           C.oraub8
 	opened        bool
+	// explicit is set when the LOB is opened with Open
+	explicit  bool
+	chunkSize int
 
 	// Length is the underlying LOB's length.
 	// It is 0 before the first Read call!
@@ -254,6 +352,192 @@ func (lr *lobReader) Close() error {
 	return lobClose(ses, lob)
 }
 
+// open the LOB to obtain length and chunk size; round-trip to database.
+func (lr *lobReader) open() (err error) {
+	lr.Lock()
+	defer lr.Unlock()
+	if lr.opened || lr.ociLobLocator == nil {
+		return nil
+	}
+	lr.opened = true
+	//Log.Infof("Reader OCILobOpen %p", def.ociLobLocator)
+	//fmt.Printf("lobOpen(%p loc=%p)\n", lr, lr.ociLobLocator)
+	lr.Length, lr.csid, lr.csfrm, err = lobOpen(lr.ses, lr.ociLobLocator, C.OCI_LOB_READONLY)
+	if err != nil {
+		return err
+	}
+	// the chunk size is only used for aligning the reads
+	lr.chunkSize, _ = lobGetChunkSize(lr.ses, lr.ociLobLocator)
+	return nil
+}
+
+// ChunkSize returns the chunk size of the LOB, as reported by
+// OCILobGetChunkSize. Reads in multiples of it are the most efficient.
+//
+// Opens the LOB if it's not opened yet. Returns 0 if it's unknown.
+func (lr *lobReader) ChunkSize() int {
+	if lr == nil {
+		return 0
+	}
+	if err := lr.open(); err != nil {
+		return 0
+	}
+	lr.Lock()
+	defer lr.Unlock()
+	return lr.chunkSize
+}
+
+// Trim the LOB to newLen bytes (characters for CLOBs).
+//
+// The LOB must be selected FOR UPDATE. If it was already opened read-only for
+// reading, it is closed first, and reopened at the next Read.
+func (lr *lobReader) Trim(newLen int64) error {
+	return lr.edit(func(ses *Ses, lob *C.OCILobLocator) error {
+		return lobTrim(ses, lob, newLen)
+	})
+}
+
+// Erase amount bytes (characters for CLOBs), starting at offset (0-based).
+// The erased part is filled with zero bytes (spaces for CLOBs).
+//
+// The same restrictions apply as for Trim.
+func (lr *lobReader) Erase(offset, amount int64) error {
+	return lr.edit(func(ses *Ses, lob *C.OCILobLocator) error {
+		return lobErase(ses, lob, offset, amount)
+	})
+}
+
+// Append p to the end of the LOB, as bytes (text for CLOBs).
+//
+// The same restrictions apply as for Trim.
+func (lr *lobReader) Append(p []byte) (n int, err error) {
+	err = lr.edit(func(ses *Ses, lob *C.OCILobLocator) error {
+		n, err = lobWriteAppend(ses, lob, p)
+		return err
+	})
+	return n, err
+}
+
+// lobLength returns the length of the LOB (characters for CLOBs),
+// without opening it or moving the reading position.
+//
+// After the locator is released (Read closes the LOB at its end), it returns
+// the length got when the LOB was opened for reading.
+func (lr *lobReader) lobLength() (int64, error) {
+	if lr == nil {
+		return 0, errNew("nil LOB")
+	}
+	lr.Lock()
+	defer lr.Unlock()
+	if lr.ociLobLocator == nil {
+		if lr.opened {
+			return int64(lr.Length), nil
+		}
+		return 0, errNew("LOB is closed")
+	}
+	return lobGetLength(lr.ses, lr.ociLobLocator)
+}
+
+func (lr *lobReader) edit(f func(*Ses, *C.OCILobLocator) error) error {
+	if lr == nil {
+		return errNew("nil LOB")
+	}
+	lr.Lock()
+	defer lr.Unlock()
+	if lr.ociLobLocator == nil {
+		return errNew("LOB is closed")
+	}
+	if lr.opened && !lr.explicit {
+		// opened with OCI_LOB_READONLY
+		if C.OCILobClose(
+			lr.ses.ocisvcctx,      //OCISvcCtx          *svchp,
+			lr.ses.srv.env.ocierr, //OCIError           *errhp,
+			lr.ociLobLocator,      //OCILobLocator      *locp,
+		) == C.OCI_ERROR {
+			return lr.ses.srv.env.ociError("OCILobClose")
+		}
+		lr.opened = false
+	}
+	return f(lr.ses, lr.ociLobLocator)
+}
+
+// LobOpenMode is the mode of opening a LOB explicitly, with Lob.Open.
+type LobOpenMode uint8
+
+const (
+	// LobReadOnly opens the LOB for reading only.
+	LobReadOnly = LobOpenMode(C.OCI_LOB_READONLY)
+	// LobReadWrite opens the LOB for reading and writing.
+	LobReadWrite = LobOpenMode(C.OCI_LOB_READWRITE)
+)
+
+// Open the LOB explicitly, in the given mode, till CloseLob.
+// A LOB opened implicitly (for reading, or by an OUT bind) is reopened.
+//
+// Returns an error if the LOB is already open explicitly.
+func (lr *lobReader) Open(mode LobOpenMode) error {
+	if lr == nil {
+		return errNew("nil LOB")
+	}
+	lr.Lock()
+	defer lr.Unlock()
+	if lr.ociLobLocator == nil {
+		return errNew("LOB is closed")
+	}
+	isOpen, err := lobIsOpen(lr.ses, lr.ociLobLocator)
+	if err != nil {
+		return err
+	}
+	if isOpen && lr.opened && !lr.explicit {
+		// opened with OCI_LOB_READONLY for reading (or as an OUT bind)
+		if C.OCILobClose(
+			lr.ses.ocisvcctx,      //OCISvcCtx          *svchp,
+			lr.ses.srv.env.ocierr, //OCIError           *errhp,
+			lr.ociLobLocator,      //OCILobLocator      *locp,
+		) == C.OCI_ERROR {
+			return lr.ses.srv.env.ociError("OCILobClose")
+		}
+		lr.opened, isOpen = false, false
+	}
+	if isOpen {
+		return errNew("LOB is already open")
+	}
+	// the locator is kept on error, so the Lob can still be used or closed
+	lr.Length, lr.csid, lr.csfrm, err = lobOpen(lr.ses, lr.ociLobLocator, C.ub1(mode))
+	if err != nil {
+		return err
+	}
+	lr.opened, lr.explicit = true, true
+	lr.chunkSize, _ = lobGetChunkSize(lr.ses, lr.ociLobLocator)
+	return nil
+}
+
+// CloseLob closes the LOB opened with Open, without freeing the locator.
+//
+// Reading it afterwards opens it again, implicitly.
+func (lr *lobReader) CloseLob() error {
+	if lr == nil {
+		return errNew("nil LOB")
+	}
+	lr.Lock()
+	defer lr.Unlock()
+	if lr.ociLobLocator == nil {
+		return errNew("LOB is closed")
+	}
+	if !lr.explicit {
+		return errNew("LOB is not opened with Open")
+	}
+	lr.opened, lr.explicit = false, false
+	if C.OCILobClose(
+		lr.ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		lr.ses.srv.env.ocierr, //OCIError           *errhp,
+		lr.ociLobLocator,      //OCILobLocator      *locp,
+	) == C.OCI_ERROR {
+		return lr.ses.srv.env.ociError("OCILobClose")
+	}
+	return nil
+}
+
 // Read into p, the next chunk.
 // Will open the LOB at the first call.
 func (lr *lobReader) Read(p []byte) (n int, err error) {
@@ -274,14 +558,7 @@ func (lr *lobReader) Read(p []byte) (n int, err error) {
 		return 0, io.EOF
 	}
 	if !opened {
-		lr.Lock()
-		lr.opened = true
-		// Open the lob to obtain length; round-trip to database
-		//Log.Infof("Reader OCILobOpen %p", def.ociLobLocator)
-		//fmt.Printf("lobOpen(%p loc=%p)\n", lr, lr.ociLobLocator)
-		lr.Length, lr.csid, lr.csfrm, err = lobOpen(ses, ociLobLocator, C.OCI_LOB_READONLY)
-		lr.Unlock()
-		if err != nil {
+		if err = lr.open(); err != nil {
 			return 0, err
 		}
 	}
@@ -350,11 +627,12 @@ func (lr *lobReader) WriteTo(w io.Writer) (n int64, err error) {
 
 	arr := lobChunkPool.Get().([lobChunkSize]byte)
 	defer lobChunkPool.Put(arr)
+	buf := arr[:alignToChunk(lobChunkSize, lr.ChunkSize())]
 
 	for {
-		k, err := lr.Read(arr[:])
+		k, err := lr.Read(buf)
 		if k > 0 {
-			if _, err := w.Write(arr[:k]); err != nil {
+			if _, err := w.Write(buf[:k]); err != nil {
 				return n, err
 			}
 		}
@@ -395,15 +673,7 @@ func (lrw *lobReadWriter) Close() error {
 
 // Truncate the lob to the given length.
 func (lrw *lobReadWriter) Truncate(length int64) error {
-	if C.OCILobTrim2(
-		lrw.ses.ocisvcctx,      //OCISvcCtx          *svchp,
-		lrw.ses.srv.env.ocierr, //OCIError           *errhp,
-		lrw.ociLobLocator,      //OCILobLocator      *locp,
-		C.oraub8(length),       //oraub8             *newlen)
-	) == C.OCI_ERROR {
-		return lrw.ses.srv.env.ociError()
-	}
-	return nil
+	return lobTrim(lrw.ses, lrw.ociLobLocator, length)
 }
 
 // ReadAt reads into p, starting from off.
@@ -471,6 +741,9 @@ func (lrw *lobReadWriter) WriteAt(p []byte, off int64) (n int, err error) {
 	return int(byteAmt), nil
 }
 
+// lobOpen opens the LOB in mode, and returns its length and character set.
+//
+// On error the LOB is closed, but the locator is kept: the caller frees it.
 func lobOpen(ses *Ses, lob *C.OCILobLocator, mode C.ub1) (
 	length C.oraub8, csid C.ub2, csfrm C.ub1, err error,
 ) {
@@ -503,7 +776,6 @@ func lobOpen(ses *Ses, lob *C.OCILobLocator, mode C.ub1) (
 	}
 	//Log.Infof("OCILobOpen %p returned %d", lob, r)
 	if r != C.OCI_SUCCESS {
-		lobClose(ses, lob)
 		return 0, csid, csfrm, env.ociError("OCILobOpen")
 	}
 	// get the length of the lob
@@ -515,32 +787,215 @@ func lobOpen(ses *Ses, lob *C.OCILobLocator, mode C.ub1) (
 		lob,        //OCILobLocator      *locp,
 		&length,    //oraub8 *lenp)
 	); r == C.OCI_ERROR {
-		lobClose(ses, lob)
-		return length, csid, csfrm, env.ociError("OCILobGetLength2")
+		err = env.ociError("OCILobGetLength2")
+		C.OCILobClose(
+			ocisvcctx,  //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+		)
+		return length, csid, csfrm, err
 	}
 
-	if r = C.OCILobCharSetId(
-		env.ocienv,
-		env.ocierr,
-		lob,
-		&csid,
-	); r == C.OCI_ERROR {
-		lobClose(ses, lob)
-		return length, csid, csfrm, env.ociError("OCILobCharSetId")
+	if csid, csfrm, err = lobCharset(env, lob); err != nil {
+		C.OCILobClose(
+			ocisvcctx,  //OCISvcCtx          *svchp,
+			env.ocierr, //OCIError           *errhp,
+			lob,        //OCILobLocator      *locp,
+		)
+		return length, csid, csfrm, err
 	}
-	if csid == 0 {
+	return length, csid, csfrm, nil
+}
+
+// lobCharset returns the character set ID and form to read the LOB with.
+//
+// National character set LOBs (NCLOB, SQLCS_NCHAR) are read in AL32UTF8,
+// so OCI converts them from the national character set (i.e. AL16UTF16).
+func lobCharset(env *Env, lob *C.OCILobLocator) (csid C.ub2, csfrm C.ub1, err error) {
+	if C.OCILobCharSetId(
+		env.ocienv, //OCIEnv              *envhp,
+		env.ocierr, //OCIError            *errhp,
+		lob,        //const OCILobLocator *locp,
+		&csid,      //ub2                 *csid );
+	) == C.OCI_ERROR {
+		return csid, csfrm, env.ociError("OCILobCharSetId")
+	}
+	if C.OCILobCharSetForm(
+		env.ocienv, //OCIEnv              *envhp,
+		env.ocierr, //OCIError            *errhp,
+		lob,        //const OCILobLocator *locp,
+		&csfrm,     //ub1                 *csfrm );
+	) == C.OCI_ERROR {
+		return csid, csfrm, env.ociError("OCILobCharSetForm")
+	}
+	if csid == 0 || csfrm == C.SQLCS_NCHAR {
 		csid = C.ub2(atomic.LoadUint32(&csIDAl32UTF8))
 	}
-	if r = C.OCILobCharSetForm(
-		env.ocienv,
-		env.ocierr,
-		lob,
-		&csfrm,
-	); r == C.OCI_ERROR {
-		lobClose(ses, lob)
-		return length, csid, csfrm, env.ociError("OCILobCharSetForm")
+	return csid, csfrm, nil
+}
+
+// lobIsOpen returns whether the LOB is open.
+func lobIsOpen(ses *Ses, lob *C.OCILobLocator) (bool, error) {
+	var flag C.boolean
+	if C.OCILobIsOpen(
+		ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr, //OCIError           *errhp,
+		lob,                //OCILobLocator      *locp,
+		&flag,              //boolean            *flag );
+	) == C.OCI_ERROR {
+		return false, ses.srv.env.ociError("OCILobIsOpen")
 	}
-	return length, csid, csfrm, nil
+	return flag == C.TRUE, nil
+}
+
+// lobGetChunkSize returns the chunk size of the LOB.
+func lobGetChunkSize(ses *Ses, lob *C.OCILobLocator) (int, error) {
+	var chunkSize C.ub4
+	if C.OCILobGetChunkSize(
+		ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr, //OCIError           *errhp,
+		lob,                //OCILobLocator      *locp,
+		&chunkSize,         //ub4                *chunksizep );
+	) == C.OCI_ERROR {
+		return 0, ses.srv.env.ociError("OCILobGetChunkSize")
+	}
+	return int(chunkSize), nil
+}
+
+func lobTrim(ses *Ses, lob *C.OCILobLocator, newLen int64) error {
+	if lob == nil {
+		return errNew("LOB is closed")
+	}
+	if newLen < 0 {
+		return errF("negative LOB length %d", newLen)
+	}
+	ses.logF(_drv.Cfg().Log.Ses.Prep, "OCILobTrim2(%p) newLen=%d", lob, newLen)
+	if C.OCILobTrim2(
+		ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr, //OCIError           *errhp,
+		lob,                //OCILobLocator      *locp,
+		C.oraub8(newLen),   //oraub8             newlen );
+	) == C.OCI_ERROR {
+		return ses.srv.env.ociError("OCILobTrim2")
+	}
+	return nil
+}
+
+// lobWriteAppend appends p to the end of the LOB, with OCILobWriteAppend2,
+// so the server tracks the position.
+func lobWriteAppend(ses *Ses, lob *C.OCILobLocator, p []byte) (int, error) {
+	if lob == nil {
+		return 0, errNew("LOB is closed")
+	}
+	// OCILobWriteAppend2 doesn't support writing zero bytes
+	if len(p) == 0 {
+		return 0, nil
+	}
+	csid, csfrm, err := lobCharset(ses.srv.env, lob)
+	if err != nil {
+		return 0, err
+	}
+	byteAmt := C.oraub8(len(p))
+	ses.logF(_drv.Cfg().Log.Ses.Prep, "OCILobWriteAppend2(%p) amount=%d", lob, len(p))
+	if C.OCILobWriteAppend2(
+		ses.ocisvcctx,         //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr,    //OCIError           *errhp,
+		lob,                   //OCILobLocator      *locp,
+		&byteAmt,              //oraub8             *byte_amtp,
+		nil,                   //oraub8             *char_amtp,
+		unsafe.Pointer(&p[0]), //void               *bufp,
+		C.oraub8(len(p)),      //oraub8             bufl,
+		C.OCI_ONE_PIECE,       //ub1                piece,
+		nil,                   //void               *ctxp,
+		nil,                   //OCICallbackLobWrite2 (cbfp)
+		csid,                  //ub2                csid,
+		csfrm,                 //ub1                csfrm );
+	) == C.OCI_ERROR {
+		return 0, ses.srv.env.ociError("OCILobWriteAppend2")
+	}
+	return int(byteAmt), nil
+}
+
+func lobErase(ses *Ses, lob *C.OCILobLocator, offset, amount int64) error {
+	if lob == nil {
+		return errNew("LOB is closed")
+	}
+	if offset < 0 || amount < 0 {
+		return errF("negative LOB offset (%d) or amount (%d)", offset, amount)
+	}
+	if amount == 0 {
+		return nil
+	}
+	amt := C.oraub8(amount)
+	ses.logF(_drv.Cfg().Log.Ses.Prep, "OCILobErase2(%p) offset=%d amount=%d", lob, offset, amount)
+	if C.OCILobErase2(
+		ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr, //OCIError           *errhp,
+		lob,                //OCILobLocator      *locp,
+		&amt,               //oraub8             *amount,
+		C.oraub8(offset)+1, //oraub8             offset, offset is 1-based
+	) == C.OCI_ERROR {
+		return ses.srv.env.ociError("OCILobErase2")
+	}
+	return nil
+}
+
+// lobGetLength returns the length of the LOB: characters for CLOBs,
+// bytes for BLOBs.
+func lobGetLength(ses *Ses, lob *C.OCILobLocator) (int64, error) {
+	if lob == nil {
+		return 0, errNew("LOB is closed")
+	}
+	var length C.oraub8
+	if C.OCILobGetLength2(
+		ses.ocisvcctx,      //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr, //OCIError           *errhp,
+		lob,                //OCILobLocator      *locp,
+		&length,            //oraub8             *lenp )
+	) == C.OCI_ERROR {
+		return 0, ses.srv.env.ociError("OCILobGetLength2")
+	}
+	return int64(length), nil
+}
+
+// lobCopy copies amount bytes (characters for CLOBs) from src at srcOffset
+// to dst at dstOffset (both 0-based), on the server side.
+// A non-positive amount copies till the end of src.
+func lobCopy(ses *Ses, dst, src *C.OCILobLocator, amount, dstOffset, srcOffset int64) error {
+	if dst == nil || src == nil {
+		return errNew("LOB is closed")
+	}
+	if dstOffset < 0 || srcOffset < 0 {
+		return errF("negative LOB offset (dst=%d, src=%d)", dstOffset, srcOffset)
+	}
+	if amount <= 0 {
+		length, err := lobGetLength(ses, src)
+		if err != nil {
+			return err
+		}
+		if amount = length - srcOffset; amount <= 0 {
+			return nil
+		}
+	}
+	ses.logF(_drv.Cfg().Log.Ses.Prep, "OCILobCopy2(%p, %p) amount=%d dstOffset=%d srcOffset=%d", dst, src, amount, dstOffset, srcOffset)
+	if C.OCILobCopy2(
+		ses.ocisvcctx,         //OCISvcCtx          *svchp,
+		ses.srv.env.ocierr,    //OCIError           *errhp,
+		dst,                   //OCILobLocator      *dst_locp,
+		src,                   //OCILobLocator      *src_locp,
+		C.oraub8(amount),      //oraub8             amount,
+		C.oraub8(dstOffset)+1, //oraub8             dst_offset, offsets are 1-based
+		C.oraub8(srcOffset)+1, //oraub8             src_offset );
+	) == C.OCI_ERROR {
+		return ses.srv.env.ociError("OCILobCopy2")
+	}
+	return nil
+}
+
+// lobLocator is implemented by the LOB readers holding a LOB locator,
+// to run f with the locator.
+type lobLocator interface {
+	edit(f func(*Ses, *C.OCILobLocator) error) error
 }
 
 func lobClose(ses *Ses, lob *C.OCILobLocator) error {
@@ -17,6 +17,7 @@ import (
 type defLongRaw struct {
 	ociDef
 	isNullable bool
+	reuse      bool
 	buf        []byte
 	bufSize    int
 }
@@ -24,6 +25,7 @@ type defLongRaw struct {
 func (def *defLongRaw) define(position int, bufSize uint32, isNullable bool, rset *Rset) error {
 	def.rset = rset
 	def.isNullable = isNullable
+	def.reuse = rset.stmt.Cfg().ReuseRow
 	if n := rset.fetchLen * int(bufSize); cap(def.buf) < n {
 		//def.buf = make([]byte, n)
 		def.buf = bytesPool.Get(n)
@@ -42,6 +44,14 @@ func (def *defLongRaw) value(offset int) (value interface{}, err error) {
 		}
 		return nil, nil
 	}
+	if def.reuse {
+		off := offset * def.bufSize
+		result := def.buf[off : off+int(def.alen[offset])]
+		if def.isNullable {
+			return Raw{Value: result}, nil
+		}
+		return result, nil
+	}
 	// Make a slice of length equal to the return length
 	result := make([]byte, def.alen[offset])
 	// Copy returned data
@@ -0,0 +1,109 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"strings"
+	"unsafe"
+)
+
+// XMLTypeName is the Column.TypeName of XMLType columns.
+const XMLTypeName = "SYS.XMLTYPE"
+
+// defOpaque defines an XMLType (SQLT_NTY) column by its serialized image:
+// OCI converts the value to text on fetch.
+// Other named types have no such conversion (they'd fail on the fetch with
+// ORA-00932), so Rset rejects them when defining the columns.
+//
+// It is read-only: there's no bind counterpart.
+type defOpaque struct {
+	ociDef
+	isNullable bool
+	isString   bool // return as string (S, OraS), []byte otherwise
+	buf        []byte
+	columnSize int
+}
+
+func (def *defOpaque) define(position int, columnSize uint32, gct GoColumnType, rset *Rset) error {
+	def.rset = rset
+	def.isNullable = gct == OraBin || gct == OraS
+	def.isString = gct == S || gct == OraS
+	def.columnSize = int(columnSize)
+	if n := rset.fetchLen * def.columnSize; cap(def.buf) < n {
+		def.buf = bytesPool.Get(n)
+	} else {
+		def.buf = def.buf[:n]
+	}
+	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.columnSize, C.SQLT_LNG)
+}
+
+func (def *defOpaque) value(offset int) (value interface{}, err error) {
+	if def.nullInds[offset] < 0 {
+		if def.isString {
+			if def.isNullable {
+				return String{IsNull: true}, nil
+			}
+			return "", nil
+		}
+		if def.isNullable {
+			return Raw{IsNull: true}, nil
+		}
+		return nil, nil
+	}
+	off := offset * def.columnSize
+	b := make([]byte, int(def.alen[offset]))
+	copy(b, def.buf[off:off+len(b)])
+	if def.isString {
+		if def.isNullable {
+			return String{Value: string(b)}, nil
+		}
+		return string(b), nil
+	}
+	if def.isNullable {
+		return Raw{Value: b}, nil
+	}
+	return b, nil
+}
+
+func (def *defOpaque) alloc() error {
+	return nil
+}
+
+func (def *defOpaque) free() {
+	def.arrHlp.close()
+	if def.buf != nil {
+		bytesPool.Put(def.buf)
+		def.buf = nil
+	}
+}
+
+func (def *defOpaque) close() (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+
+	rset := def.rset
+	def.rset = nil
+	def.ocidef = nil
+	def.free()
+	rset.putDef(defIdxOpaque, def)
+	return nil
+}
+
+// opaqueGct returns the default GoColumnType of a named type:
+// S for XMLType, Bin for the others.
+func opaqueGct(typeName string) GoColumnType {
+	if strings.EqualFold(typeName, XMLTypeName) {
+		return S
+	}
+	return Bin
+}
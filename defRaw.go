@@ -18,13 +18,15 @@ type defRaw struct {
 	ociDef
 	ociRaw     *C.OCIRaw
 	isNullable bool
+	isString   bool // return as uppercase hex string (S, OraS)
 	buf        []byte
 	columnSize int
 }
 
-func (def *defRaw) define(position int, columnSize int, isNullable bool, rset *Rset) error {
+func (def *defRaw) define(position int, columnSize int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
-	def.isNullable = isNullable
+	def.isNullable = gct == OraBin || gct == OraS
+	def.isString = gct == S || gct == OraS
 	def.columnSize = columnSize
 	if n := rset.fetchLen * columnSize; cap(def.buf) < n {
 		//def.buf = make([]byte, n)
@@ -38,6 +40,12 @@ func (def *defRaw) define(position int, columnSize int, isNullable bool, rset *R
 
 func (def *defRaw) value(offset int) (value interface{}, err error) {
 	if def.nullInds[offset] < 0 {
+		if def.isString {
+			if def.isNullable {
+				return String{IsNull: true}, nil
+			}
+			return "", nil
+		}
 		if def.isNullable {
 			return Raw{IsNull: true}, nil
 		}
@@ -45,6 +53,13 @@ func (def *defRaw) value(offset int) (value interface{}, err error) {
 	}
 	n := int(def.alen[offset])
 	off := offset * def.columnSize
+	if def.isString {
+		s := GUIDToString(def.buf[off : off+n])
+		if def.isNullable {
+			return String{Value: s}, nil
+		}
+		return s, nil
+	}
 	if def.isNullable {
 		return Raw{Value: def.buf[off : off+n]}, nil
 	}
@@ -5,51 +5,92 @@
 package ora
 
 /*
+#include <stdlib.h>
 #include <oci.h>
 #include "version.h"
 */
 import "C"
 import (
-	"bytes"
 	"unsafe"
 )
 
-const rowidLen = 19
+// rowidMaxLen is the maximum length of the character form of a
+// (universal) ROWID.
+const rowidMaxLen = 4000
 
 type defRowid struct {
 	ociDef
-	buf []byte
+	rowids []*C.OCIRowid
+	buf    []byte
 }
 
 func (def *defRowid) define(position int, rset *Rset) error {
 	def.rset = rset
-	// using a character host variable of width between 19
-	// (18 bytes plus the null-terminator) and 4001 as the
-	// host bind variable for universal ROWID.
-	if n := rset.fetchLen * rowidLen; cap(def.buf) < n {
-		//def.buf = make([]byte, n)
-		def.buf = bytesPool.Get(n)
-	} else {
-		def.buf = def.buf[:n]
+	// the ROWIDs are fetched into descriptors, and converted with
+	// OCIRowidToChar, so universal ROWIDs (e.g. of index-organized tables)
+	// are returned as a whole, too.
+	if def.rowids != nil {
+		C.free(unsafe.Pointer(&def.rowids[0]))
 	}
-	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), rowidLen, C.SQLT_STR)
+	def.rowids = (*((*[MaxFetchLen]*C.OCIRowid)(C.calloc(C.size_t(rset.fetchLen), C.sof_Rowidp))))[:rset.fetchLen]
+	if cap(def.buf) < rowidMaxLen {
+		def.buf = make([]byte, rowidMaxLen)
+	}
+	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.rowids[0]), int(C.sof_Rowidp), C.SQLT_RDD)
 }
 
 func (def *defRowid) value(offset int) (value interface{}, err error) {
-	n := bytes.Index(def.buf[offset*rowidLen:(offset+1)*rowidLen], []byte{0})
-	if n == -1 {
-		n = rowidLen
+	if def.nullInds[offset] < 0 {
+		return "", nil
+	}
+	return rowidToChar(def.rset.env, def.rowids[offset], def.buf[:rowidMaxLen])
+}
+
+// rowidToChar returns the character (base-64) form of the ROWID,
+// as seen in SQL tools, using buf as the buffer.
+func rowidToChar(env *Env, rowid *C.OCIRowid, buf []byte) (string, error) {
+	n := C.ub2(len(buf))
+	r := C.OCIRowidToChar(
+		rowid,                                 //OCIRowid     *rowidDesc,
+		(*C.OraText)(unsafe.Pointer(&buf[0])), //OraText      *outbfp,
+		&n,                                    //ub2          *outbflp,
+		env.ocierr)                            //OCIError     *errhp );
+	if r == C.OCI_ERROR {
+		return "", env.ociError("OCIRowidToChar")
+	}
+	return string(buf[:int(n)]), nil
+}
+
+func (def *defRowid) alloc() error {
+	for i, p := range def.rowids {
+		if p != nil { // reuse the descriptors of the previous fetch
+			continue
+		}
+		r := C.OCIDescriptorAlloc(
+			unsafe.Pointer(def.rset.stmt.ses.srv.env.ocienv),  //CONST dvoid   *parenth,
+			(*unsafe.Pointer)(unsafe.Pointer(&def.rowids[i])), //dvoid         **descpp,
+			C.OCI_DTYPE_ROWID, //ub4           type,
+			0,                 //size_t        xtramem_sz,
+			nil)               //dvoid         **usrmempp);
+		if r == C.OCI_ERROR {
+			return def.rset.stmt.ses.srv.env.ociError()
+		} else if r == C.OCI_INVALID_HANDLE {
+			return errNew("unable to allocate oci rowid handle during define")
+		}
 	}
-	value = string(def.buf[offset*rowidLen : offset*rowidLen+n])
-	return value, err
+	return nil
 }
 
-func (def *defRowid) alloc() error { return nil }
 func (def *defRowid) free() {
 	def.arrHlp.close()
-	if def.buf != nil {
-		bytesPool.Put(def.buf)
-		def.buf = nil
+	for i, p := range def.rowids {
+		if p == nil {
+			continue
+		}
+		def.rowids[i] = nil
+		C.OCIDescriptorFree(
+			unsafe.Pointer(p), //void     *descp,
+			C.OCI_DTYPE_ROWID) //ub4      type );
 	}
 }
 
@@ -60,10 +101,14 @@ func (def *defRowid) close() (err error) {
 		}
 	}()
 
+	def.free()
+	if def.rowids != nil {
+		C.free(unsafe.Pointer(&def.rowids[0]))
+		def.rowids = nil
+	}
 	rset := def.rset
 	def.rset = nil
 	def.ocidef = nil
-	def.free()
 	rset.putDef(defIdxRowid, def)
 	return nil
 }
@@ -12,6 +12,11 @@ package ora
 import "C"
 import "unsafe"
 
+// defRset defines a nested cursor (CURSOR expression) column.
+//
+// Each row's value is a new *Rset on the row's statement handle. The handles
+// are reused by the next fetch, so the nested Rsets of the fetched rows are
+// closed before it, and with the parent Rset.
 type defRset struct {
 	ociDef
 	ocistmt []*C.OCIStmt
@@ -25,19 +30,7 @@ func (def *defRset) define(position int, rset *Rset) error {
 	}
 	def.ocistmt = (*((*[MaxFetchLen]*C.OCIStmt)(C.malloc(C.size_t(rset.fetchLen) * C.sof_Stmtp))))[:rset.fetchLen]
 	def.result = make([]*Rset, len(def.ocistmt))
-
-	// create result set
-	for i := range def.result {
-		result := _drv.rsetPool.Get().(*Rset)
-		if result.id == 0 {
-			result.id = _drv.rsetId.nextId()
-		}
-		result.autoClose = false
-		result.env = def.rset.env
-		result.stmt = rset.stmt
-		result.ocistmt = rset.ocistmt
-		def.result[i] = result
-
+	for i := range def.ocistmt {
 		upOciStmt, err := def.rset.stmt.ses.srv.env.allocOciHandle(C.OCI_HTYPE_STMT)
 		if err != nil {
 			return errE(err)
@@ -49,27 +42,40 @@ func (def *defRset) define(position int, rset *Rset) error {
 }
 
 func (def *defRset) value(offset int) (value interface{}, err error) {
-	rst := def.result[offset]
-
-	err = rst.open(rst.stmt, def.ocistmt[offset])
-	rst.stmt.openRsets.add(rst)
+	def.closeResult(offset)
+	// not from _drv.rsetPool: closeResult closes the nested Rset on the next
+	// fetch, and a pooled Rset would be zeroed and recycled by close, while
+	// the caller may still hold it
+	rst := &Rset{id: _drv.rsetId.nextId(), env: def.rset.env}
+	if err = rst.open(def.rset.stmt, def.ocistmt[offset]); err != nil {
+		return nil, err
+	}
+	def.result[offset] = rst
+	return rst, nil
+}
 
-	return rst, err
+// closeResult closes the nested Rset of the offset-th row, if it is open.
+func (def *defRset) closeResult(offset int) {
+	if rst := def.result[offset]; rst != nil {
+		def.result[offset] = nil
+		if rst.IsOpen() {
+			rst.close()
+		}
+	}
 }
 
 func (def *defRset) alloc() error {
+	// the next fetch reuses the statement handles
+	for i := range def.result {
+		def.closeResult(i)
+	}
 	return nil
 }
 
 func (def *defRset) free() {
+	// the handles are freed by close, as the nested Rsets of the last
+	// fetched rows are still in use
 	def.arrHlp.close()
-	for i, p := range def.ocistmt {
-		if p == nil {
-			continue
-		}
-		def.ocistmt[i] = nil
-		def.rset.stmt.ses.srv.env.freeOciHandle(unsafe.Pointer(p), C.OCI_HTYPE_STMT)
-	}
 }
 
 func (def *defRset) close() (err error) {
@@ -80,6 +86,17 @@ func (def *defRset) close() (err error) {
 	}()
 
 	def.free()
+	for i := range def.result {
+		def.closeResult(i)
+	}
+	def.result = nil
+	for i, p := range def.ocistmt {
+		if p == nil {
+			continue
+		}
+		def.ocistmt[i] = nil
+		def.rset.stmt.ses.srv.env.freeOciHandle(unsafe.Pointer(p), C.OCI_HTYPE_STMT)
+	}
 	if def.ocistmt != nil {
 		C.free(unsafe.Pointer(&def.ocistmt[0]))
 		def.ocistmt = nil
@@ -23,6 +23,7 @@ type defString struct {
 	sync.RWMutex
 	buf               []byte
 	isNullable, rTrim bool
+	reuse             bool
 	columnSize        int
 }
 
@@ -39,6 +40,7 @@ func (def *defString) define(position int, columnSize int, isNullable, rTrim boo
 	defer def.Unlock()
 	def.rset = rset
 	def.isNullable, def.rTrim = isNullable, rTrim
+	def.reuse = rset.stmt.Cfg().ReuseRow
 	//Log.Infof("defString position=%d columnSize=%d", position, columnSize)
 	n := columnSize
 	// AL32UTF8: one db "char" can be 4 bytes on wire, esp. if the database's
@@ -69,7 +71,15 @@ func (def *defString) define(position int, columnSize int, isNullable, rTrim boo
 		def.buf = def.buf[:n]
 	}
 
-	return def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.columnSize, C.SQLT_CHR)
+	if err := def.ociDef.defineByPos(position, unsafe.Pointer(&def.buf[0]), def.columnSize, C.SQLT_CHR); err != nil {
+		return err
+	}
+	if csid := rset.charsetID; csid != 0 {
+		// converted to the charset of WithCharset
+		return rset.env.setAttr(unsafe.Pointer(def.ocidef), C.OCI_HTYPE_DEFINE,
+			unsafe.Pointer(&csid), 0, C.OCI_ATTR_CHARSET_ID)
+	}
+	return nil
 }
 
 func (def *defString) value(offset int) (value interface{}, err error) {
@@ -90,7 +100,13 @@ func (def *defString) value(offset int) (value interface{}, err error) {
 	//	def, offset, def.alen, def.columnSize, def.buf[offset*def.columnSize:offset*def.columnSize+int(def.alen[offset])])
 	if def.alen[offset] > 0 {
 		off := offset * def.columnSize
-		s = string(def.buf[off : off+int(def.alen[offset])])
+		b := def.buf[off : off+int(def.alen[offset])]
+		if def.reuse {
+			// aliases the fetch buffer, valid only till the next Rset.Next
+			s = *(*string)(unsafe.Pointer(&b))
+		} else {
+			s = string(b)
+		}
 		if def.rTrim {
 			s = strings.TrimRight(s, " ")
 		}
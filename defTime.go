@@ -11,9 +11,8 @@ package ora
 */
 import "C"
 import (
-	"bytes"
 	"math"
-	"strings"
+	"sync/atomic"
 	"time"
 	"unsafe"
 )
@@ -21,12 +20,19 @@ import (
 type defTime struct {
 	ociDef
 	isNullable bool
+	epoch      GoColumnType
+	tzRegion   bool
 	dates      []*C.OCIDateTime
 }
 
-func (def *defTime) define(position int, isNullable bool, rset *Rset) error {
+func (def *defTime) define(position int, gct GoColumnType, rset *Rset) error {
 	def.rset = rset
-	def.isNullable = isNullable
+	def.isNullable = gct == OraT
+	def.epoch = 0
+	if isEpochColumn(gct) {
+		def.epoch = gct
+	}
+	def.tzRegion = rset.stmt.Cfg().TimeZoneRegion
 	if def.dates != nil {
 		C.free(unsafe.Pointer(&def.dates[0]))
 	}
@@ -41,7 +47,10 @@ func (def *defTime) value(offset int) (value interface{}, err error) {
 		}
 		return nil, nil
 	}
-	t, err := getTime(def.rset.stmt.ses.srv.env, def.dates[offset])
+	t, err := getTime(def.rset.stmt.ses.srv.env, def.dates[offset], def.tzRegion)
+	if def.epoch != 0 {
+		return epochValue(t, def.epoch), err
+	}
 	if def.isNullable {
 		return Time{Value: t}, err
 	}
@@ -98,7 +107,12 @@ func (def *defTime) close() (err error) {
 	return nil
 }
 
-func getTime(env *Env, ociDateTime *C.OCIDateTime) (result time.Time, err error) {
+// getTime returns the time.Time of ociDateTime.
+//
+// If region is true, then a time zone region name (i.e. America/New_York)
+// is kept as a named time.Location; otherwise, it is collapsed to
+// a fixed offset.
+func getTime(env *Env, ociDateTime *C.OCIDateTime, region bool) (result time.Time, err error) {
 	var year C.sb2
 	var month C.ub1
 	var day C.ub1
@@ -137,48 +151,37 @@ func getTime(env *Env, ociDateTime *C.OCIDateTime) (result time.Time, err error)
 		(*C.ub1)(&buf[0]),          //ub1                *buf,
 		&buflen)                    //ub4                *buflen, );
 	if r != C.OCI_ERROR {
-		var buffer bytes.Buffer
-		for n := 0; n < int(buflen); n++ {
-			buffer.WriteByte(buf[n])
+		// a numeric offset (e.g. +02:00) needs no lookup
+		if region && buflen > 0 && buf[0] != '+' && buf[0] != '-' {
+			locName := string(buf[:buflen])
+			// keep the named region, so DST-aware arithmetic is correct
+			if location, err = cachedLocation(locName, func() (*time.Location, error) {
+				return time.LoadLocation(locName)
+			}); err != nil {
+				location = nil // unknown region, fall back to the offset
+			}
 		}
-		locName := buffer.String()
-		_drv.locationsMu.RLock()
-		location = _drv.locations[locName]
-		_drv.locationsMu.RUnlock()
 		if location == nil {
 			// timestamp_ltz returns numeric offset
 			// time.Time's lookup for numeric offset is unknown;
 			// therefore, create a fixed location for the offset
 			var offsetHour C.sb1
 			var offsetMinute C.sb1
-			if strings.ContainsAny(locName, "-0123456789") {
-				r = C.OCIDateTimeGetTimeZoneOffset(
-					unsafe.Pointer(env.ocienv), //void               *hndl,
-					env.ocierr,                 //OCIError           *err,
-					ociDateTime,                //const OCIDateTime  *datetime,
-					&offsetHour,                //sb1                *hour,
-					&offsetMinute)              //sb1                *min, );
-				if r == C.OCI_ERROR {
-					return result, env.ociError()
-				}
-				seconds := math.Abs(float64(offsetHour)) * 60 * 60
-				seconds += math.Abs(float64(offsetMinute)) * 60
-				if offsetHour < 0 {
-					seconds *= -1
-				}
-				location = time.FixedZone(locName, int(seconds))
-			} else {
-				location, err = time.LoadLocation(locName)
-				if err != nil {
-					return result, err
-				}
+			r = C.OCIDateTimeGetTimeZoneOffset(
+				unsafe.Pointer(env.ocienv), //void               *hndl,
+				env.ocierr,                 //OCIError           *err,
+				ociDateTime,                //const OCIDateTime  *datetime,
+				&offsetHour,                //sb1                *hour,
+				&offsetMinute)              //sb1                *min, );
+			if r == C.OCI_ERROR {
+				return result, env.ociError()
+			}
+			seconds := math.Abs(float64(offsetHour)) * 60 * 60
+			seconds += math.Abs(float64(offsetMinute)) * 60
+			if offsetHour < 0 || offsetMinute < 0 {
+				seconds *= -1
 			}
-			// stored location for future reference
-			// important that FixedZone is called as few times as possible
-			// to reduce significant memory allocation
-			_drv.locationsMu.Lock()
-			_drv.locations[locName] = location
-			_drv.locationsMu.Unlock()
+			location = offsetZone(int(seconds))
 		}
 	} else {
 		// Date Oracle type doesn't have timezone info
@@ -188,3 +191,55 @@ func getTime(env *Env, ociDateTime *C.OCIDateTime) (result time.Time, err error)
 	result = time.Date(int(year), time.Month(int(month)), int(day), int(hour), int(minute), int(second), int(fsec), location)
 	return result, nil
 }
+
+// The offsets of the time zones are between -15:00 and +15:00, in minutes.
+const (
+	minZoneOffset = -15 * 60
+	maxZoneOffset = 15 * 60
+)
+
+// offsetZones caches the fixed zone (*time.Location) of each offset,
+// indexed by the offset in minutes from minZoneOffset.
+// It is read without locking, as the offset-only timestamps are common.
+var offsetZones [maxZoneOffset - minZoneOffset + 1]unsafe.Pointer
+
+// offsetZone returns the fixed zone of the offset (in seconds east of UTC),
+// named by the offset (e.g. +02:00), as Oracle names it.
+//
+// It's important that FixedZone is called as few times as possible
+// to reduce significant memory allocation, so the zones are cached,
+// without touching the (locked) locations map.
+func offsetZone(seconds int) *time.Location {
+	minutes := seconds / 60
+	if seconds%60 != 0 || minutes < minZoneOffset || minutes > maxZoneOffset {
+		return time.FixedZone(string(appendZoneOffset(make([]byte, 0, 6), seconds)), seconds)
+	}
+	p := &offsetZones[minutes-minZoneOffset]
+	if location := (*time.Location)(atomic.LoadPointer(p)); location != nil {
+		return location
+	}
+	location := time.FixedZone(string(appendZoneOffset(make([]byte, 0, 6), seconds)), seconds)
+	// a concurrent store stores an equivalent zone
+	atomic.StorePointer(p, unsafe.Pointer(location))
+	return location
+}
+
+// cachedLocation returns the location stored under name,
+// or stores and returns the result of create.
+func cachedLocation(name string, create func() (*time.Location, error)) (*time.Location, error) {
+	_drv.locationsMu.RLock()
+	location := _drv.locations[name]
+	_drv.locationsMu.RUnlock()
+	if location != nil {
+		return location, nil
+	}
+	location, err := create()
+	if err != nil {
+		return nil, err
+	}
+	// stored location for future reference
+	_drv.locationsMu.Lock()
+	_drv.locations[name] = location
+	_drv.locationsMu.Unlock()
+	return location, nil
+}
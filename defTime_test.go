@@ -0,0 +1,27 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOffsetZone(t *testing.T) {
+	for _, seconds := range []int{0, 2 * 3600, 5*3600 + 30*60, -9*3600 - 30*60, 14 * 3600} {
+		loc := offsetZone(seconds)
+		if loc != offsetZone(seconds) {
+			t.Errorf("%d: the zone is not cached", seconds)
+		}
+		want := string(appendZoneOffset(nil, seconds))
+		if name, offset := time.Date(2017, 1, 1, 0, 0, 0, 0, loc).Zone(); name != want || offset != seconds {
+			t.Errorf("%d: got %s (%d), wanted %s", seconds, name, offset, want)
+		}
+	}
+	// not a whole minute
+	if _, offset := time.Date(2017, 1, 1, 0, 0, 0, 0, offsetZone(61)).Zone(); offset != 61 {
+		t.Errorf("got %d, wanted 61", offset)
+	}
+}
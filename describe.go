@@ -0,0 +1,395 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <oci.h>
+#include <stdlib.h>
+#include "version.h"
+*/
+import "C"
+import (
+	"strings"
+	"unsafe"
+)
+
+// ObjectType is the type of a schema object described by Ses.DescribeObject.
+type ObjectType uint8
+
+// object types
+const (
+	// ObjectUnknown defines a described object as of an unknown type.
+	ObjectUnknown = ObjectType(C.OCI_PTYPE_UNK)
+	// ObjectTable defines a described object as a table.
+	ObjectTable = ObjectType(C.OCI_PTYPE_TABLE)
+	// ObjectView defines a described object as a view.
+	ObjectView = ObjectType(C.OCI_PTYPE_VIEW)
+	// ObjectProcedure defines a described object as a standalone procedure.
+	ObjectProcedure = ObjectType(C.OCI_PTYPE_PROC)
+	// ObjectFunction defines a described object as a standalone function.
+	ObjectFunction = ObjectType(C.OCI_PTYPE_FUNC)
+	// ObjectPackage defines a described object as a package.
+	ObjectPackage = ObjectType(C.OCI_PTYPE_PKG)
+	// ObjectUserType defines a described object as a user-defined type.
+	ObjectUserType = ObjectType(C.OCI_PTYPE_TYPE)
+	// ObjectSequence defines a described object as a sequence.
+	ObjectSequence = ObjectType(C.OCI_PTYPE_SEQ)
+)
+
+// String returns the object type as in ALL_OBJECTS.OBJECT_TYPE (e.g. "TABLE"),
+// or "UNKNOWN".
+func (t ObjectType) String() string {
+	switch t {
+	case ObjectTable:
+		return "TABLE"
+	case ObjectView:
+		return "VIEW"
+	case ObjectProcedure:
+		return "PROCEDURE"
+	case ObjectFunction:
+		return "FUNCTION"
+	case ObjectPackage:
+		return "PACKAGE"
+	case ObjectUserType:
+		return "TYPE"
+	case ObjectSequence:
+		return "SEQUENCE"
+	}
+	return "UNKNOWN"
+}
+
+// ObjectDescription is the description of a schema object,
+// returned by Ses.DescribeObject.
+type ObjectDescription struct {
+	Type         ObjectType
+	Schema, Name string
+
+	// Columns are the columns of a table or view.
+	Columns []Column
+	// Arguments are the arguments of a procedure or function.
+	// The first argument of a function is its return value, with an
+	// empty Name and Position 0.
+	Arguments []Argument
+	// Attributes are the attributes of an object type.
+	Attributes []Column
+}
+
+// Argument describes an argument of a procedure or function.
+type Argument struct {
+	Name      string
+	Position  uint16
+	Type      C.ub2
+	Length    uint32
+	Precision C.sb2
+	Scale     C.sb1
+	TypeName  string // schema-qualified type name of named (SQLT_NTY) arguments
+	// Dir is BindIn, BindOut or BindInOut.
+	Dir BindDir
+	// HasDefault is set for arguments with a default value.
+	HasDefault bool
+}
+
+// DescribeObject describes the schema object named name, i.e. "TABLE1" or
+// "SCHEMA1.PROC1", using OCIDescribeAny, without querying the data
+// dictionary views. Synonyms are resolved to the object they stand for.
+//
+// Tables and views are described by their Columns, procedures and
+// functions by their Arguments, object types by their Attributes.
+// For the other types of objects (packages, sequences), only
+// Type, Schema and Name are set.
+func (ses *Ses) DescribeObject(name string) (*ObjectDescription, error) {
+	ses.log(_drv.Cfg().Log.Ses.Prep, name)
+	if err := ses.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	if name == "" {
+		return nil, er("name is empty.")
+	}
+	env := ses.Env()
+	ocidsc, err := env.allocOciHandle(C.OCI_HTYPE_DESCRIBE)
+	if err != nil {
+		return nil, errE(err)
+	}
+	defer env.freeOciHandle(ocidsc, C.OCI_HTYPE_DESCRIBE)
+	// the synonyms of synonyms are resolved, too
+	for i := 0; ; i++ {
+		desc, target, err := ses.describeAny(name, (*C.OCIDescribe)(ocidsc))
+		if err != nil {
+			return nil, errE(err)
+		}
+		if target == "" {
+			return desc, nil
+		}
+		if i == 8 {
+			return nil, errF("too many levels of synonyms for %q", name)
+		}
+		name = target
+	}
+}
+
+// describeAny describes the object named name with ocidsc.
+// For a synonym, it returns the name of the object it stands for.
+func (ses *Ses) describeAny(name string, ocidsc *C.OCIDescribe) (desc *ObjectDescription, target string, err error) {
+	env := ses.Env()
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	ses.RLock()
+	r := C.OCIDescribeAny(
+		ses.ocisvcctx,         //OCISvcCtx     *svchp,
+		env.ocierr,            //OCIError      *errhp,
+		unsafe.Pointer(cName), //void          *objptr,
+		C.ub4(len(name)),      //ub4           objptr_len,
+		C.OCI_OTYPE_NAME,      //ub1           objptr_typ,
+		C.OCI_DEFAULT,         //ub1           info_level,
+		C.OCI_PTYPE_UNK,       //ub1           objtyp,
+		ocidsc)                //OCIDescribe   *dschp );
+	ses.RUnlock()
+	if r == C.OCI_ERROR {
+		return nil, "", env.ociError()
+	}
+	var ocipar *C.OCIParam
+	r = C.OCIAttrGet(
+		unsafe.Pointer(ocidsc),  //const void     *trgthndlp,
+		C.OCI_HTYPE_DESCRIBE,    //ub4            trghndltyp,
+		unsafe.Pointer(&ocipar), //void           *attributep,
+		nil,                     //ub4            *sizep,
+		C.OCI_ATTR_PARAM,        //ub4            attrtype,
+		env.ocierr)              //OCIError       *errhp );
+	if r == C.OCI_ERROR {
+		return nil, "", env.ociError()
+	}
+	var ptype C.ub1
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&ptype), nil, C.OCI_ATTR_PTYPE); err != nil {
+		return nil, "", err
+	}
+	desc = &ObjectDescription{Type: ObjectType(ptype)}
+	if desc.Schema, err = env.paramString(ocipar, C.OCI_ATTR_OBJ_SCHEMA); err != nil {
+		return nil, "", err
+	}
+	if desc.Name, err = env.paramString(ocipar, C.OCI_ATTR_OBJ_NAME); err != nil {
+		return nil, "", err
+	}
+
+	var list *C.OCIParam
+	switch ptype {
+	case C.OCI_PTYPE_SYN:
+		var schema, obj string
+		if schema, err = env.paramString(ocipar, C.OCI_ATTR_SCHEMA_NAME); err != nil {
+			return nil, "", err
+		}
+		if obj, err = env.paramString(ocipar, C.OCI_ATTR_NAME); err != nil {
+			return nil, "", err
+		}
+		if schema == "" {
+			return nil, obj, nil
+		}
+		return nil, schema + "." + obj, nil
+
+	case C.OCI_PTYPE_TABLE, C.OCI_PTYPE_VIEW:
+		var num C.ub2
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&num), nil, C.OCI_ATTR_NUM_COLS); err != nil {
+			return nil, "", err
+		}
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&list), nil, C.OCI_ATTR_LIST_COLUMNS); err != nil {
+			return nil, "", err
+		}
+		if desc.Columns, err = env.describeColumns(list, 1, int(num)); err != nil {
+			return nil, "", err
+		}
+
+	case C.OCI_PTYPE_TYPE:
+		var num C.ub2
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&num), nil, C.OCI_ATTR_NUM_TYPE_ATTRS); err != nil {
+			return nil, "", err
+		}
+		if num == 0 { // i.e. a collection type
+			return desc, "", nil
+		}
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&list), nil, C.OCI_ATTR_LIST_TYPE_ATTRS); err != nil {
+			return nil, "", err
+		}
+		if desc.Attributes, err = env.describeColumns(list, 1, int(num)); err != nil {
+			return nil, "", err
+		}
+
+	case C.OCI_PTYPE_PROC, C.OCI_PTYPE_FUNC:
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&list), nil, C.OCI_ATTR_LIST_ARGUMENTS); err != nil {
+			return nil, "", err
+		}
+		var num C.ub2
+		if err = env.paramAttr(list, unsafe.Pointer(&num), nil, C.OCI_ATTR_NUM_PARAMS); err != nil {
+			return nil, "", err
+		}
+		// the return value of a function is at position 0
+		first := 1
+		if ptype == C.OCI_PTYPE_FUNC {
+			first = 0
+		}
+		desc.Arguments = make([]Argument, 0, int(num))
+		for i := first; i < first+int(num); i++ {
+			arg, err := env.describeArgument(list, i)
+			if err != nil {
+				return nil, "", err
+			}
+			desc.Arguments = append(desc.Arguments, arg)
+		}
+	}
+	return desc, "", nil
+}
+
+// describeColumns describes the num columns (or type attributes)
+// of the list, starting at position first.
+func (env *Env) describeColumns(list *C.OCIParam, first, num int) ([]Column, error) {
+	cols := make([]Column, 0, num)
+	for i := first; i < first+num; i++ {
+		ocipar, err := env.paramGet(list, i)
+		if err != nil {
+			return nil, err
+		}
+		var col Column
+		var typ, size, charSize C.ub2
+		var prec C.ub1
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&typ), nil, C.OCI_ATTR_DATA_TYPE); err != nil {
+			return nil, err
+		}
+		if err = env.paramAttr(ocipar, unsafe.Pointer(&size), nil, C.OCI_ATTR_DATA_SIZE); err != nil {
+			return nil, err
+		}
+		if col.Name, err = env.paramString(ocipar, C.OCI_ATTR_NAME); err != nil {
+			return nil, err
+		}
+		col.Type, col.Length = typ, uint32(size)
+		switch typ {
+		case C.SQLT_CHR, C.SQLT_AFC:
+			if err = env.paramAttr(ocipar, unsafe.Pointer(&charSize), nil, C.OCI_ATTR_CHAR_SIZE); err != nil {
+				return nil, err
+			}
+			col.CharSize = uint16(charSize)
+		case C.SQLT_NUM:
+			// the precision is an ub1 for explicit describes
+			if err = env.paramAttr(ocipar, unsafe.Pointer(&prec), nil, C.OCI_ATTR_PRECISION); err != nil {
+				return nil, err
+			}
+			col.Precision = C.sb2(prec)
+			if err = env.paramAttr(ocipar, unsafe.Pointer(&col.Scale), nil, C.OCI_ATTR_SCALE); err != nil {
+				return nil, err
+			}
+		case C.SQLT_NTY, C.SQLT_REF:
+			if col.TypeName, err = env.paramTypeName(ocipar); err != nil {
+				return nil, err
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// describeArgument describes the argument at position pos of the list.
+func (env *Env) describeArgument(list *C.OCIParam, pos int) (arg Argument, err error) {
+	ocipar, err := env.paramGet(list, pos)
+	if err != nil {
+		return arg, err
+	}
+	var typ, size, position C.ub2
+	var prec, hasDefault C.ub1
+	var mode C.OCITypeParamMode
+	if arg.Name, err = env.paramString(ocipar, C.OCI_ATTR_NAME); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&position), nil, C.OCI_ATTR_POSITION); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&typ), nil, C.OCI_ATTR_DATA_TYPE); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&size), nil, C.OCI_ATTR_DATA_SIZE); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&prec), nil, C.OCI_ATTR_PRECISION); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&arg.Scale), nil, C.OCI_ATTR_SCALE); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&mode), nil, C.OCI_ATTR_IOMODE); err != nil {
+		return arg, err
+	}
+	if err = env.paramAttr(ocipar, unsafe.Pointer(&hasDefault), nil, C.OCI_ATTR_HAS_DEFAULT); err != nil {
+		return arg, err
+	}
+	arg.Position, arg.Type, arg.Length = uint16(position), typ, uint32(size)
+	arg.Precision, arg.HasDefault = C.sb2(prec), hasDefault != 0
+	switch mode {
+	case C.OCI_TYPEPARAM_OUT:
+		arg.Dir = BindOut
+	case C.OCI_TYPEPARAM_INOUT:
+		arg.Dir = BindInOut
+	default:
+		arg.Dir = BindIn
+	}
+	if typ == C.SQLT_NTY || typ == C.SQLT_REF {
+		if arg.TypeName, err = env.paramTypeName(ocipar); err != nil {
+			return arg, err
+		}
+	}
+	return arg, nil
+}
+
+// paramGet returns the parameter at position pos of the list,
+// owned by the describe handle.
+func (env *Env) paramGet(list *C.OCIParam, pos int) (*C.OCIParam, error) {
+	var ocipar *C.OCIParam
+	r := C.OCIParamGet(
+		unsafe.Pointer(list), //const void        *hndlp,
+		C.OCI_DTYPE_PARAM,    //ub4               htype,
+		env.ocierr,           //OCIError          *errhp,
+		(*unsafe.Pointer)(unsafe.Pointer(&ocipar)), //void              **parmdpp,
+		C.ub4(pos)) //ub4               pos );
+	if r == C.OCI_ERROR {
+		return nil, env.ociError()
+	}
+	return ocipar, nil
+}
+
+// paramString gets a text attribute from the parameter handle.
+func (env *Env) paramString(ocipar *C.OCIParam, attrType C.ub4) (string, error) {
+	var value *C.char
+	var n C.ub4
+	if err := env.paramAttr(ocipar, unsafe.Pointer(&value), &n, attrType); err != nil {
+		return "", err
+	}
+	return C.GoStringN(value, C.int(n)), nil
+}
+
+// paramTypeName returns the schema-qualified type name of the parameter.
+func (env *Env) paramTypeName(ocipar *C.OCIParam) (string, error) {
+	schema, err := env.paramString(ocipar, C.OCI_ATTR_SCHEMA_NAME)
+	if err != nil {
+		return "", err
+	}
+	typ, err := env.paramString(ocipar, C.OCI_ATTR_TYPE_NAME)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(schema+"."+typ, "."), nil
+}
+
+// paramAttr gets an attribute from the parameter handle.
+func (env *Env) paramAttr(ocipar *C.OCIParam, attrup unsafe.Pointer, attrSizep *C.ub4, attrType C.ub4) error {
+	if attrSizep == nil {
+		attrSizep = new(C.ub4)
+	}
+	r := C.OCIAttrGet(
+		unsafe.Pointer(ocipar), //const void     *trgthndlp,
+		C.OCI_DTYPE_PARAM,      //ub4            trghndltyp,
+		attrup,                 //void           *attributep,
+		attrSizep,              //ub4            *sizep,
+		attrType,               //ub4            attrtype,
+		env.ocierr)             //OCIError       *errhp );
+	if r == C.OCI_ERROR {
+		return env.ociError()
+	}
+	return nil
+}
@@ -22,7 +22,7 @@ Package ora implements an Oracle database driver.
 		// Set timeout (Go 1.8)
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		// Set prefetch count (Go 1.8)
-		ctx = ora.WithStmtCfg(ctx, ora.Cfg().StmtCfg.SetPrefetchCount(50000))
+		ctx = ora.WithPrefetch(ctx, 50000)
 		rows, err := db.QueryContext(ctx, "SELECT * FROM user_objects")
 		defer rows.Close()
 	}
@@ -120,7 +120,8 @@ built-in data types are NUMBER, BINARY_DOUBLE, BINARY_FLOAT, FLOAT, DATE,
 TIMESTAMP, TIMESTAMP WITH TIME ZONE, TIMESTAMP WITH LOCAL TIME ZONE,
 INTERVAL YEAR TO MONTH, INTERVAL DAY TO SECOND, CHAR, NCHAR, VARCHAR, VARCHAR2,
 NVARCHAR2, LONG, CLOB, NCLOB, BLOB, LONG RAW, RAW, ROWID and BFILE.
-SYS_REFCURSOR is also supported.
+SYS_REFCURSOR is also supported: as an OUT parameter with a *Rset, and as
+an IN parameter with the InCursor of an open Rset.
 
 Oracle does not provide a built-in boolean type. Oracle provides a single-byte
 character type. A common practice is to define two single-byte characters which
@@ -253,6 +254,9 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 	[]time.Time
 	[]Time
 
+	Date, []Date			DATE (nil elements of []*time.Time are NULL)
+	[]*time.Time
+
 	string				CHAR², NCHAR, VARCHAR, VARCHAR2,
 	String				NVARCHAR2, LONG, CLOB, NCLOB, ROWID
 	*string
@@ -265,7 +269,10 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 	[]bool
 	[]Bool
 
-	[]byte, [][]byte	BLOB
+	[]byte			BLOB
+	[]BlobBytes
+
+	[][]byte			RAW, LONG RAW, BLOB (32767 bytes per element at most⁴)
 
 	Lob, []Lob, *Lob	BLOB, CLOB
 
@@ -296,6 +303,10 @@ may be changed. The Go-to-Oracle type mapping for the ora package is:
 	³ The Go bool value false is mapped to the zero rune '0'. The Go bool value
 	true is mapped to the one rune '1'.
 
+	⁴ [][]byte is bound as an array of RAW, with the nil elements as NULL.
+	A RAW column holds 2000 bytes, or 32767 with MAX_STRING_SIZE=EXTENDED;
+	bind longer values to a BLOB column as []BlobBytes.
+
 An example of using the ora package directly:
 
 	package main
@@ -647,6 +658,10 @@ GoColumnTypes defined by the ora package are:
 
 	Lob°		Bin or S
 
+	time.Duration	Dur²
+
+	int		Months²
+
 	default¹	D
 
 	° Lob will return binary data if the Oracle column is a BLOB; otherwise, Lob
@@ -655,6 +670,9 @@ GoColumnTypes defined by the ora package are:
 	¹ D represents a default mapping between a select-list column and a Go type.
 	The default mapping is defined in RsetCfg.
 
+	² Dur is valid for INTERVAL DAY TO SECOND, Months (the total months)
+	for INTERVAL YEAR TO MONTH columns only.
+
 When Stmt.Prep doesn't receive a GoColumnType, or receives an incorrect GoColumnType,
 the default value defined in RsetCfg is used.
 
@@ -834,6 +852,21 @@ multiple Rsets:
 		}
 	}
 
+A CURSOR expression in the select-list yields a nested *Rset in each row,
+for master-detail queries in one statement:
+
+	rset, err := ses.PrepAndQry("SELECT id, CURSOR(SELECT c2 FROM t2 WHERE t2.id = t1.id) FROM t1")
+	for rset.Next() {
+		detail := rset.Row[1].(*ora.Rset)
+		for detail.Next() {
+			fmt.Println(rset.Row[0], detail.Row[0])
+		}
+	}
+
+The nested Rsets of the rows of one fetch are closed before the next fetch
+(their statement handles are reused), and with the parent Rset, so read
+them before moving on.
+
 The types of values assigned to Row may be configured in StmtCfg.Rset. For configuration
 to take effect, assign StmtCfg.Rset prior to calling Stmt.Qry or Stmt.Exe.
 
@@ -20,11 +20,22 @@ import (
 type DrvCfg struct {
 	StmtCfg
 	Log LogDrvCfg
+
+	// Object makes OpenEnv create the OCI environment in object mode
+	// (OCI_OBJECT), which object types, collections and REFs (e.g. XMLType
+	// columns) need.
+	//
+	// It is observed by OpenEnv only, so set it (with SetCfg) before
+	// opening the Env: defining a named type column in an Env opened
+	// without it returns an error.
+	//
+	// The default is true.
+	Object bool
 }
 
 // NewDrvCfg creates a DrvCfg with default values.
 func NewDrvCfg() DrvCfg {
-	return DrvCfg{StmtCfg: NewStmtCfg(), Log: NewLogDrvCfg()}
+	return DrvCfg{StmtCfg: NewStmtCfg(), Log: NewLogDrvCfg(), Object: true}
 }
 
 func (cfg DrvCfg) SetStmtCfg(stmtCfg StmtCfg) DrvCfg {
@@ -123,6 +134,17 @@ type LogDrvCfg struct {
 	// LogDrvCfg.Logger = lg15.Log
 	Logger Logger
 
+	// OnEvent, when not nil, is called with every logged message and error,
+	// with the parts as separate fields, for structured logging.
+	// It is called in addition to Logger; set Logger to EmpLgr{} to use
+	// OnEvent only.
+	//
+	// The enabling flags (Ses.Prep etc.) are observed for messages,
+	// errors are passed always.
+	//
+	// The default is nil.
+	OnEvent func(LogEvent)
+
 	// OpenEnv determines whether the ora.OpenEnv method is logged.
 	//
 	// The default is true.
@@ -182,9 +204,15 @@ func NewLogDrvCfg() LogDrvCfg {
 	return c
 }
 
-// IsEnabled returns whether the logger is enabled (and enabled is true).
+// IsEnabled returns whether the logger or OnEvent is enabled (and enabled is true).
 func (c LogDrvCfg) IsEnabled(enabled bool) bool {
-	if !enabled || c.Logger == nil {
+	if !enabled {
+		return false
+	}
+	if c.OnEvent != nil {
+		return true
+	}
+	if c.Logger == nil {
 		return false
 	}
 	_, ok := c.Logger.(EmpLgr)
@@ -52,6 +52,10 @@ func (qr *DrvQueryResult) Next(dest []driver.Value) (err error) {
 	}
 	offset := int(qr.rset.offset)
 	for n, define := range qr.rset.defs {
+		if define == nil { // not projected
+			dest[n] = nil
+			continue
+		}
 		value, err := define.value(offset)
 		if err != nil {
 			fmt.Printf("%d. %T (%#v): %v\n", n, define, define, err)
@@ -93,7 +97,7 @@ func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
 	}
 	// https://docs.oracle.com/cd/E11882_01/appdev.112/e10646/oci03typ.htm#LNOCI16271
 	qr.rset.RLock()
-	x := qr.rset.Columns[index].Type
+	x, typeName := qr.rset.Columns[index].Type, qr.rset.Columns[index].TypeName
 	qr.rset.RUnlock()
 	switch x {
 	case C.SQLT_CHR:
@@ -137,6 +141,9 @@ func (qr *DrvQueryResult) ColumnTypeDatabaseTypeName(index int) string {
 	case C.SQLT_RDD:
 		return "ROWID"
 	case C.SQLT_NTY:
+		if typeName != "" {
+			return typeName
+		}
 		return "NAMED"
 	case C.SQLT_REF:
 		return "REF"
@@ -212,7 +219,7 @@ func (qr *DrvQueryResult) ColumnTypeScanType(index int) reflect.Type {
 		return nil
 	}
 	qr.rset.RLock()
-	x := qr.rset.Columns[index].Type
+	x, typeName := qr.rset.Columns[index].Type, qr.rset.Columns[index].TypeName
 	qr.rset.RUnlock()
 	switch x {
 	case C.SQLT_CHR, C.SQLT_STR, C.SQLT_LNG, C.SQLT_VCS, C.SQLT_LVC, C.SQLT_AFC, C.SQLT_AVC, C.SQLT_CLOB, C.SQLT_VST:
@@ -231,6 +238,9 @@ func (qr *DrvQueryResult) ColumnTypeScanType(index int) reflect.Type {
 		return reflect.TypeOf(uint64(0))
 
 	case C.SQLT_NTY, C.SQLT_REF:
+		if opaqueGct(typeName) == S {
+			return reflect.TypeOf("")
+		}
 		return reflect.TypeOf([]byte{})
 	case C.SQLT_INTERVAL_YM, C.SQLT_INTERVAL_DS:
 		return reflect.TypeOf(time.Duration(0))
@@ -103,13 +103,9 @@ func (ds *DrvStmt) sysName() string {
 
 // log writes a message with an DrvStmt system name and caller info.
 func (ds *DrvStmt) log(enabled bool, v ...interface{}) {
-	cfg := _drv.Cfg()
-	if !cfg.Log.IsEnabled(enabled) {
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		cfg.Log.Logger.Infof("%v %v", ds.sysName(), callInfo(1))
-	} else {
-		cfg.Log.Logger.Infof("%v %v %v", ds.sysName(), callInfo(1), fmt.Sprint(v...))
-	}
+	Log.emit(LogEvent{Component: "DrvStmt", Operation: callOp(1), SysName: ds.sysName(), SQL: ds.stmt.sql, Msg: fmt.Sprint(v...)})
 }
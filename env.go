@@ -16,6 +16,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -58,6 +59,10 @@ type Env struct {
 	errBuf   [512]C.char
 	ociHndMu sync.Mutex
 	isPkgEnv bool
+	isObject bool // created with OCI_OBJECT, see DrvCfg.Object
+
+	// maximum bytes per character of the client character set, cached
+	charMaxBytes int32
 
 	openSrvs *srvList
 	openCons *conList
@@ -100,6 +105,7 @@ func (env *Env) Close() (err error) {
 		env.SetCfg(StmtCfg{})
 		env.Lock()
 		env.isPkgEnv = false
+		env.isObject = false
 		env.ocienv = nil
 		env.ocierr = nil
 		env.Unlock()
@@ -143,6 +149,10 @@ func (env *Env) OpenSrv(cfg SrvCfg) (srv *Srv, err error) {
 	if err != nil {
 		return nil, errE(err)
 	}
+	if cfg.ServerGroup != "" && cfg.Pool.Type != NoPool {
+		// the OCI pools create their server handles themselves
+		return nil, errF("ServerGroup %q is observed for NoPool connections only, not with Pool.Type %d.", cfg.ServerGroup, cfg.Pool.Type)
+	}
 	// allocate server handle
 	ocisrv, err := env.allocOciHandle(C.OCI_HTYPE_SERVER)
 	if err != nil {
@@ -235,19 +245,30 @@ func (env *Env) OpenSrv(cfg SrvCfg) (srv *Srv, err error) {
 			env.freeOciHandle(ocipool, C.OCI_HTYPE_SPOOL)
 			return nil, errE(err)
 		}
+		if cfg.StmtCacheSize > 0 && !cfg.CompatMode {
+			// cache the statements of the pooled sessions
+			stmtCacheSize := C.ub4(cfg.StmtCacheSize)
+			if err := env.setAttr(ocipool, C.OCI_HTYPE_SPOOL, unsafe.Pointer(&stmtCacheSize), C.ub4(0), C.OCI_ATTR_SPOOL_STMTCACHESIZE); err != nil {
+				env.logF(_drv.Cfg().Log.Env.OpenSrv, "skip statement cache size: %v", err)
+			}
+		}
 
 	default:
-		env.RLock()
-		r := C.OCIServerAttach(
-			(*C.OCIServer)(ocisrv),                //OCIServer     *srvhp,
-			env.ocierr,                            //OCIError      *errhp,
-			(*C.OraText)(unsafe.Pointer(cDblink)), //const OraText *dblink,
-			C.sb4(len(cfg.Dblink)),                //sb4           dblink_len,
-			C.OCI_DEFAULT)                         //ub4           mode);
-		env.RUnlock()
-		if r == C.OCI_ERROR {
+		if cfg.ServerGroup != "" {
+			cServerGroup := C.CString(cfg.ServerGroup)
+			err = env.setAttr(ocisrv, C.OCI_HTYPE_SERVER, unsafe.Pointer(cServerGroup), C.ub4(len(cfg.ServerGroup)), C.OCI_ATTR_SERVER_GROUP)
+			C.free(unsafe.Pointer(cServerGroup))
+			if err != nil {
+				C.free(unsafe.Pointer(cDblink))
+				return nil, errE(err)
+			}
+		}
+		if abandoned, err := env.serverAttach(ocisrv, cDblink, cfg); err != nil {
+			if abandoned { // *ConnectTimeoutError, unwrapped
+				return nil, err
+			}
 			C.free(unsafe.Pointer(cDblink))
-			return nil, errE(env.ociError())
+			return nil, errE(err)
 		}
 	}
 
@@ -276,6 +297,66 @@ func (env *Env) OpenSrv(cfg SrvCfg) (srv *Srv, err error) {
 	return srv, nil
 }
 
+// serverAttach attaches the server handle to cfg.Dblink (OCIServerAttach).
+//
+// With a positive cfg.ConnectTimeout, OCIBreak is called on the handle after
+// the timeout, and a *ConnectTimeoutError is returned without waiting for the
+// attach: the abandoned attach detaches, and frees ocisrv and cDblink when
+// it returns.
+func (env *Env) serverAttach(ocisrv unsafe.Pointer, cDblink *C.char, cfg SrvCfg) (abandoned bool, err error) {
+	attach := func() error {
+		env.RLock()
+		r := C.OCIServerAttach(
+			(*C.OCIServer)(ocisrv),                //OCIServer     *srvhp,
+			env.ocierr,                            //OCIError      *errhp,
+			(*C.OraText)(unsafe.Pointer(cDblink)), //const OraText *dblink,
+			C.sb4(len(cfg.Dblink)),                //sb4           dblink_len,
+			C.OCI_DEFAULT)                         //ub4           mode);
+		env.RUnlock()
+		if r == C.OCI_ERROR {
+			return env.ociError()
+		}
+		return nil
+	}
+	if cfg.ConnectTimeout <= 0 {
+		return false, attach()
+	}
+	done := make(chan error, 1)
+	go func() { done <- attach() }()
+	timer := time.NewTimer(cfg.ConnectTimeout)
+	defer timer.Stop()
+	select {
+	case err = <-done:
+		return false, err
+	case <-timer.C:
+	}
+	// with an own error handle, as env.ocierr is used by the attach
+	if ocierr, err := env.allocOciHandle(C.OCI_HTYPE_ERROR); err == nil {
+		C.OCIBreak(
+			ocisrv,                //void      *hndlp,
+			(*C.OCIError)(ocierr)) //OCIError  *errhp );
+		env.freeOciHandle(ocierr, C.OCI_HTYPE_ERROR)
+	}
+	env.logF(_drv.Cfg().Log.Env.OpenSrv, "abandon attach to %q after %s", cfg.Dblink, cfg.ConnectTimeout)
+	go func() {
+		err := <-done
+		defer C.free(unsafe.Pointer(cDblink))
+		env.RLock()
+		defer env.RUnlock()
+		if env.ocienv == nil { // freed with the Env
+			return
+		}
+		if err == nil {
+			C.OCIServerDetach(
+				(*C.OCIServer)(ocisrv), //OCIServer   *srvhp,
+				env.ocierr,             //OCIError    *errhp,
+				C.OCI_DEFAULT)          //ub4         mode );
+		}
+		env.freeOciHandle(ocisrv, C.OCI_HTYPE_SERVER)
+	}()
+	return true, &ConnectTimeoutError{Dblink: cfg.Dblink, After: cfg.ConnectTimeout}
+}
+
 var (
 	conCharset   = make(map[string]string, 2)
 	conCharsetMu sync.Mutex
@@ -414,11 +495,7 @@ func (env *Env) log(enabled bool, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", env.sysName(), callInfo(1))
-	} else {
-		Log.Logger.Infof("%v %v %v", env.sysName(), callInfo(1), fmt.Sprint(v...))
-	}
+	Log.emit(LogEvent{Component: "Env", Operation: callOp(1), SysName: env.sysName(), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Env system name and caller info.
@@ -427,11 +504,11 @@ func (env *Env) logF(enabled bool, format string, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", env.sysName(), callInfo(1))
-	} else {
-		Log.Logger.Infof("%v %v %v", env.sysName(), callInfo(1), fmt.Sprintf(format, v...))
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Component: "Env", Operation: callOp(1), SysName: env.sysName(), Msg: msg})
 }
 
 // allocateOciHandle allocates an oci handle. No locking occurs.
@@ -476,6 +553,44 @@ func (env *Env) freeOciHandle(ociHandle unsafe.Pointer, handleType C.ub4) error
 	return err
 }
 
+// charsetID returns the id of the named character set
+// (OCINlsCharSetNameToId), or 0 for "", which means the client character set.
+func (env *Env) charsetID(name string) (C.ub2, error) {
+	if name == "" {
+		return 0, nil
+	}
+	cName := C.CString(strings.ToUpper(name))
+	defer C.free(unsafe.Pointer(cName))
+	env.RLock()
+	csid := C.OCINlsCharSetNameToId(unsafe.Pointer(env.ocienv), (*C.oratext)(unsafe.Pointer(cName)))
+	env.RUnlock()
+	if csid == 0 {
+		return 0, errF("unknown character set %q.", name)
+	}
+	return csid, nil
+}
+
+// maxBytesPerChar returns the maximum number of bytes per character of the
+// client character set (OCI_NLS_CHARSET_MAXBYTESZ).
+func (env *Env) maxBytesPerChar() int {
+	if n := atomic.LoadInt32(&env.charMaxBytes); n > 0 {
+		return int(n)
+	}
+	var val C.sb4
+	env.RLock()
+	r := C.OCINlsNumericInfoGet(
+		unsafe.Pointer(env.ocienv),  //void          *envhp,
+		env.ocierr,                  //OCIError      *errhp,
+		&val,                        //sb4           *val,
+		C.OCI_NLS_CHARSET_MAXBYTESZ) //ub2           item );
+	env.RUnlock()
+	if r != C.OCI_SUCCESS || val <= 0 {
+		val = 4 // AL32UTF8
+	}
+	atomic.StoreInt32(&env.charMaxBytes, int32(val))
+	return int(val)
+}
+
 // setOciAttribute sets an attribute value on a handle or descriptor. No locking occurs.
 func (env *Env) setAttr(
 	target unsafe.Pointer,
@@ -520,23 +635,69 @@ func (env *Env) ociError(prefix ...string) error {
 type ORAError struct {
 	code            int
 	prefix, message string
+	// instance is the name of the database instance of the session
+	instance string
 }
 
 func (e ORAError) Code() int {
 	return e.code
 }
 
+// Instance returns the name of the database instance (i.e. the RAC node)
+// of the session which got the error, if known.
+func (e ORAError) Instance() string {
+	return e.instance
+}
+
+// setErrInstance sets the instance name of the ORAError wrapped in err.
+func setErrInstance(err error, instance string) {
+	for err != nil {
+		switch e := err.(type) {
+		case *ORAError:
+			e.instance = instance
+			return
+		case *oraErr:
+			err = e.Underlying
+		default:
+			return
+		}
+	}
+}
+
+// IsConnLost reports whether err means that the connection to the server
+// is lost: the session has been killed (ORA-00028), marked for kill
+// (ORA-00031), or the server is unreachable (ORA-03113, ORA-03114).
+func IsConnLost(err error) bool {
+	if err == nil {
+		return false
+	}
+	cd, ok := err.(interface {
+		Code() int
+	})
+	if !ok {
+		return false
+	}
+	switch cd.Code() {
+	case 28, 31, 3113, 3114:
+		return true
+	}
+	return false
+}
+
 func (e *ORAError) Error() string {
 	if e == nil {
 		return ""
 	}
-	if e.message != "" {
-		if e.prefix != "" {
-			return e.prefix + ": " + e.message
-		}
-		return e.message
+	msg := e.message
+	if msg == "" {
+		msg = fmt.Sprintf("ORA-%05d", e.code)
+	} else if e.prefix != "" {
+		msg = e.prefix + ": " + msg
+	}
+	if e.instance != "" {
+		msg += " (instance " + e.instance + ")"
 	}
-	return fmt.Sprintf("ORA-%05d", e.code)
+	return msg
 }
 
 var b8Pool = sync.Pool{
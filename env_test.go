@@ -0,0 +1,18 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import "testing"
+
+func TestErrInstance(t *testing.T) {
+	err := er(&ORAError{code: 3113, message: "ORA-03113: end-of-file on communication channel"})
+	setErrInstance(err, "orcl2")
+	if got, want := err.(*oraErr).Underlying.Error(), "ORA-03113: end-of-file on communication channel (instance orcl2)"; got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	if inst := err.(*oraErr).Instance(); inst != "orcl2" {
+		t.Errorf("got instance %q", inst)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// GUIDToString returns the uppercase hex representation of a RAW value,
+// such as a SYS_GUID(), the way SQL*Plus shows it.
+func GUIDToString(guid []byte) string {
+	return strings.ToUpper(hex.EncodeToString(guid))
+}
+
+// ParseGUID parses the 32 hex digits representation of a RAW(16) GUID,
+// as returned by GUIDToString, into its 16 bytes.
+func ParseGUID(s string) ([]byte, error) {
+	if len(s) != 32 {
+		return nil, errF("GUID %q must be 32 hex digits long", s)
+	}
+	guid, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errF("GUID %q: %v", s, err)
+	}
+	return guid, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGUID(t *testing.T) {
+	const s = "5A3F6C0E8B2D4E1F9A7B6C5D4E3F2A1B"
+	b, err := ParseGUID(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 16 {
+		t.Fatalf("got %d bytes, wanted 16", len(b))
+	}
+	if got := GUIDToString(b); got != s {
+		t.Errorf("got %q, wanted %q", got, s)
+	}
+	if _, err = ParseGUID(strings.ToLower(s)); err != nil {
+		t.Errorf("lowercase: %v", err)
+	}
+	for _, bad := range []string{"", s[:30], s + "00", "Z" + s[1:]} {
+		if _, err = ParseGUID(bad); err == nil {
+			t.Errorf("%q: wanted error", bad)
+		}
+	}
+}
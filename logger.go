@@ -18,3 +18,40 @@ func (e EmpLgr) Infof(format string, v ...interface{})  {}
 func (e EmpLgr) Infoln(v ...interface{})                {}
 func (e EmpLgr) Errorf(format string, v ...interface{}) {}
 func (e EmpLgr) Errorln(v ...interface{})               {}
+
+// LogEvent is a log message with its parts as separate fields,
+// passed to LogDrvCfg.OnEvent.
+type LogEvent struct {
+	// Component is the type logging the message: Env, Srv, Ses, Stmt, Rset,
+	// Tx, Con or DrvStmt. It is empty for package-level messages and errors.
+	Component string
+	// Operation is the logging method, such as "Stmt.exeC",
+	// or the location of the error.
+	Operation string
+	// SysName is the system name of the logging Env, Srv, Ses etc.
+	SysName string
+	// SQL is the text of the statement, for Stmt, Rset and DrvStmt messages.
+	SQL string
+	// Msg is the message, maybe empty.
+	Msg string
+	// Err is the error, for error events.
+	Err error
+}
+
+// emit writes the event to the Logger, as "SysName [Operation] Msg",
+// and passes it to OnEvent.
+func (c LogDrvCfg) emit(ev LogEvent) {
+	if c.Logger != nil {
+		msg := "[" + ev.Operation + "]"
+		if ev.SysName != "" {
+			msg = ev.SysName + " " + msg
+		}
+		if ev.Msg != "" {
+			msg += " " + ev.Msg
+		}
+		c.Logger.Infof("%s", msg)
+	}
+	if c.OnEvent != nil {
+		c.OnEvent(ev)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type recLgr struct {
+	EmpLgr
+	lines []string
+}
+
+func (r *recLgr) Infof(format string, v ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(format, v...))
+}
+
+func TestLogDrvCfgEmit(t *testing.T) {
+	var events []LogEvent
+	lgr := &recLgr{}
+	c := LogDrvCfg{Logger: lgr, OnEvent: func(ev LogEvent) { events = append(events, ev) }}
+	ev := LogEvent{Component: "Stmt", Operation: "Stmt.exeC", SysName: "E1S1S1S1", SQL: "SELECT 1 FROM DUAL", Msg: "done"}
+	c.emit(ev)
+	c.emit(LogEvent{Operation: "ora.log"})
+	if want := []string{"E1S1S1S1 [Stmt.exeC] done", "[ora.log]"}; strings.Join(lgr.lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, wanted %q", lgr.lines, want)
+	}
+	if len(events) != 2 || events[0] != ev {
+		t.Errorf("got %#v, wanted %#v as first of 2 events", events, ev)
+	}
+	if !(LogDrvCfg{Logger: EmpLgr{}, OnEvent: c.OnEvent}).IsEnabled(true) {
+		t.Error("OnEvent does not enable logging")
+	}
+}
@@ -112,7 +112,7 @@ func init() {
 	_drv.bndPools[bndIdxNil] = newPool(func() interface{} { return &bndNil{} })
 
 	// init def pools
-	_drv.defPools = make([]*sync.Pool, defIdxRset+1)
+	_drv.defPools = make([]*sync.Pool, defIdxOpaque+1)
 	_drv.defPools[defIdxInt64] = newPool(func() interface{} { return &defInt64{} })
 	_drv.defPools[defIdxInt32] = newPool(func() interface{} { return &defInt32{} })
 	_drv.defPools[defIdxInt16] = newPool(func() interface{} { return &defInt16{} })
@@ -138,6 +138,7 @@ func init() {
 	_drv.defPools[defIdxIntervalDS] = newPool(func() interface{} { return &defIntervalDS{} })
 	_drv.defPools[defIdxRowid] = newPool(func() interface{} { return &defRowid{} })
 	_drv.defPools[defIdxRset] = newPool(func() interface{} { return &defRset{} })
+	_drv.defPools[defIdxOpaque] = newPool(func() interface{} { return &defOpaque{} })
 
 	var err error
 	if _drv.sqlPkgEnv, err = OpenEnv(); err != nil {
@@ -174,22 +175,26 @@ func OpenEnv() (env *Env, err error) {
 	}
 	// OCI_DEFAULT  - The default value, which is non-UTF-16 encoding.
 	// OCI_THREADED - Uses threaded environment. Internal data structures not exposed to the user are protected from concurrent accesses by multiple threads.
-	// OCI_OBJECT   - Uses object features such as OCINumber, OCINumberToInt, OCINumberFromInt. These are used in oracle-go type conversions.
+	// OCI_OBJECT   - Uses object features, needed by object types, collections and REFs (DrvCfg.Object).
+	mode := C.ub4(C.OCI_DEFAULT | C.OCI_THREADED)
+	if cfg.Object {
+		mode |= C.OCI_OBJECT
+	}
 	_drv.RLock()
 	env = _drv.envPool.Get().(*Env) // set *Env
 	env.cmu.Lock()
 	defer env.cmu.Unlock()
 	r := C.OCIEnvNlsCreate(
 		&env.ocienv, //OCIEnv        **envhpp,
-		C.OCI_DEFAULT|C.OCI_OBJECT|C.OCI_THREADED, //ub4           mode,
-		nil,  //void          *ctxp,
-		nil,  //void          *(*malocfp)
-		nil,  //void          *(*ralocfp)
-		nil,  //void          (*mfreefp)
-		0,    //size_t        xtramemsz,
-		nil,  //void          **usrmempp
-		csid, //ub2           charset,
-		csid) //ub2           ncharset );
+		mode,        //ub4           mode,
+		nil,         //void          *ctxp,
+		nil,         //void          *(*malocfp)
+		nil,         //void          *(*ralocfp)
+		nil,         //void          (*mfreefp)
+		0,           //size_t        xtramemsz,
+		nil,         //void          **usrmempp
+		csid,        //ub2           charset,
+		csid)        //ub2           ncharset );
 	_drv.RUnlock()
 	if r == C.OCI_ERROR {
 		return nil, errF("Unable to create environment handle (Return code = %d).", r)
@@ -200,6 +205,7 @@ func OpenEnv() (env *Env, err error) {
 	}
 
 	env.ocierr = (*C.OCIError)(ocierr)
+	env.isObject = cfg.Object
 	if env.id == 0 {
 		env.id = _drv.envId.nextId()
 	}
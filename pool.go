@@ -18,6 +18,16 @@ type PoolCfg struct {
 	Username       string
 	Password       string
 	Min, Max, Incr uint32
+
+	// MaxLifetime is the maximum age of the sessions of a Pool
+	// (see Env.NewPool): older sessions are closed when they are put back,
+	// and replaced with new ones by Get.
+	//
+	// The limit of each session is shortened by up to a tenth (depending on
+	// its id), so the sessions opened at once are not recycled all at once.
+	//
+	// The default is 0, which means no limit.
+	MaxLifetime time.Duration
 }
 
 type PoolType uint8
@@ -55,8 +65,9 @@ func (env *Env) NewPool(srvCfg SrvCfg, sesCfg SesCfg, size int) *Pool {
 	p := &Pool{
 		env:    env,
 		srvCfg: srvCfg, sesCfg: sesCfg,
-		srv: newIdlePool(size),
-		ses: newIdlePool(size),
+		size: size,
+		srv:  newIdlePool(size),
+		ses:  newIdlePool(size),
 	}
 	p.poolEvictor = &poolEvictor{
 		Evict: func(d time.Duration) {
@@ -64,6 +75,7 @@ func (env *Env) NewPool(srvCfg SrvCfg, sesCfg SesCfg, size int) *Pool {
 			p.srv.Evict(d)
 		}}
 	p.SetEvictDuration(DefaultEvictDuration)
+	p.SetValidateOnGet(true)
 	return p
 }
 
@@ -80,12 +92,18 @@ func NewPool(dsn string, size int) (*Pool, error) {
 }
 
 type Pool struct {
+	recycled uint64 // first for 64-bit alignment
+
 	env    *Env
 	srvCfg SrvCfg
 	sesCfg SesCfg
+	size   int
 
 	sync.Mutex
 	srv, ses *idlePool
+	groups   map[string]*Pool
+
+	validateOnGet uint32
 
 	*poolEvictor
 }
@@ -112,9 +130,72 @@ func (p *Pool) Close() (err error) {
 	if err2 := p.srv.Close(); err2 != nil && err == nil {
 		err = err2
 	}
+	for _, g := range p.groups {
+		if err2 := g.Close(); err2 != nil && err == nil {
+			err = err2
+		}
+	}
+	p.groups = nil
 	return err
 }
 
+// ServerGroup returns the sub-pool of p for the given server group
+// (see SrvCfg.ServerGroup, so p must not use an OCI pool), creating it on
+// first use.
+//
+// The sub-pool has the same configuration and size as p, except the
+// server group, so sessions of different services are never mixed.
+// It is closed when p is closed.
+func (p *Pool) ServerGroup(group string) *Pool {
+	p.Lock()
+	defer p.Unlock()
+	if group == p.srvCfg.ServerGroup {
+		return p
+	}
+	if g, ok := p.groups[group]; ok {
+		return g
+	}
+	srvCfg := p.srvCfg
+	srvCfg.ServerGroup = group
+	g := p.env.NewPool(srvCfg, p.sesCfg, p.size)
+	g.SetEvictDuration(time.Second * time.Duration(atomic.LoadUint32(&p.evictDurSec)))
+	g.SetValidateOnGet(atomic.LoadUint32(&p.validateOnGet) == 1)
+	if p.groups == nil {
+		p.groups = make(map[string]*Pool)
+	}
+	p.groups[group] = g
+	return g
+}
+
+// Recycled returns the number of sessions closed by the pool
+// for being older than PoolCfg.MaxLifetime.
+func (p *Pool) Recycled() uint64 {
+	return atomic.LoadUint64(&p.recycled)
+}
+
+// expired reports whether ses is older than PoolCfg.MaxLifetime.
+func (p *Pool) expired(ses *Ses) bool {
+	maxLifetime := p.srvCfg.Pool.MaxLifetime
+	if maxLifetime <= 0 {
+		return false
+	}
+	ses.RLock()
+	openedAt, id := ses.openedAt, ses.id
+	ses.RUnlock()
+	if openedAt.IsZero() {
+		return false
+	}
+	// spread the recycling of the sessions opened at once
+	maxLifetime -= maxLifetime / 10 * time.Duration(id%16) / 16
+	return time.Since(openedAt) > maxLifetime
+}
+
+// recycle closes the expired ses and its srv.
+func (p *Pool) recycle(ses *Ses) {
+	atomic.AddUint64(&p.recycled, 1)
+	closeSesSrv(ses)
+}
+
 func insteadSesClose(ses *Ses, pool *idlePool) func() error {
 	return func() error {
 		ses.insteadClose = nil
@@ -123,10 +204,40 @@ func insteadSesClose(ses *Ses, pool *idlePool) func() error {
 	}
 }
 
+// SetValidateOnGet sets whether Get pings the idle sessions before
+// returning them, to replace the ones killed by the server
+// (i.e. by an idle timeout) with fresh ones.
+//
+// The default is true.
+func (p *Pool) SetValidateOnGet(validate bool) {
+	var v uint32
+	if validate {
+		v = 1
+	}
+	atomic.StoreUint32(&p.validateOnGet, v)
+}
+
 // Get a session - either an idle session, or if such does not exist, then
 // a new session on an idle connection; if such does not exist, then
 // a new session on a new connection.
 func (p *Pool) Get() (ses *Ses, err error) {
+	for {
+		var validate bool
+		if ses, validate, err = p.get(); err != nil || !validate {
+			return ses, err
+		}
+		// ping after releasing the lock of p, not to serialize the round-trips
+		if err = ses.Ping(); err == nil {
+			return ses, nil
+		}
+		// the session is dead (ORA-02396, ORA-03113), and so is its srv
+		closeSesSrv(ses)
+	}
+}
+
+// get returns a session for Get, and whether it is an idle one to be
+// validated (pinged) before use.
+func (p *Pool) get() (ses *Ses, validate bool, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = errR(r)
@@ -142,7 +253,17 @@ func (p *Pool) Get() (ses *Ses, err error) {
 		}
 		ses.Lock()
 		ses.insteadClose = nil // one-shot
+		ses.unpinStmts()       // the next user must not inherit the pins
 		ses.Unlock()
+		// the next user must not inherit the pending CommitEvery batch
+		if err := ses.settleBatch(); err != nil {
+			p.recycle(ses)
+			return err
+		}
+		if p.expired(ses) {
+			p.recycle(ses)
+			return nil
+		}
 		// if the session is to be evicted, its srv should go to the srv pool.
 		p.ses.Put(sesSrvPB{Ses: ses, p: p.srv})
 		return nil
@@ -154,11 +275,20 @@ func (p *Pool) Get() (ses *Ses, err error) {
 			break
 		}
 		ses = x.(sesSrvPB).Ses
-		if ses == nil || !ses.IsOpen() {
+		if ses == nil {
+			continue
+		}
+		if !ses.IsOpen() {
+			// closed, or its connection is lost
+			closeSesSrv(ses)
+			continue
+		}
+		if p.expired(ses) {
+			p.recycle(ses)
 			continue
 		}
 		ses.insteadClose = Instead
-		return ses, nil
+		return ses, atomic.LoadUint32(&p.validateOnGet) == 1, nil
 	}
 
 	var srv *Srv
@@ -184,32 +314,52 @@ func (p *Pool) Get() (ses *Ses, err error) {
 		}
 		if ses, err = srv.OpenSes(p.sesCfg); err == nil {
 			ses.insteadClose = Instead
-			return ses, nil
+			return ses, false, nil
 		}
 		_ = srv.Close()
 	}
 
 	//fmt.Fprintf(os.Stderr, "POOL: create new srv!\n")
 	if srv, err = p.env.OpenSrv(p.srvCfg); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	if ses, err = srv.OpenSes(p.sesCfg); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	ses.insteadClose = Instead
-	return ses, nil
+	return ses, false, nil
 }
 
 // Put the session back to the session pool.
 // Ensure that on ses Close (eviction), srv is put back on the idle pool.
 func (p *Pool) Put(ses *Ses) {
-	if ses == nil || !ses.IsOpen() {
+	if ses == nil {
+		return
+	}
+	if !ses.IsOpen() {
+		closeSesSrv(ses)
+		return
+	}
+	if p.expired(ses) {
+		p.recycle(ses)
 		return
 	}
 	//fmt.Fprintf(os.Stderr, "POOL: put back ses\n")
 	p.ses.Put(sesSrvPB{Ses: ses, p: p.srv})
 }
 
+// closeSesSrv closes ses and its srv.
+func closeSesSrv(ses *Ses) {
+	ses.Lock()
+	srv := ses.srv
+	ses.insteadClose = nil // really close it
+	ses.Unlock()
+	_ = ses.Close()
+	if srv != nil {
+		_ = srv.Close()
+	}
+}
+
 type sesSrvPB struct {
 	*Ses
 	p *idlePool
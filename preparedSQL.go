@@ -0,0 +1,41 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+// PreparedSQL is a session-independent description of a statement:
+// the SQL text, the GoColumnTypes and the StmtCfg.
+//
+// It can be prepared on any session with Ses.PrepFrom, without
+// analyzing the SQL text again. PreparedSQL is immutable, so it is safe
+// for concurrent use.
+type PreparedSQL struct {
+	sql                string
+	gcts               []GoColumnType
+	cfg                StmtCfg
+	lastInsertIdMarked bool
+}
+
+// NewPreparedSQL returns a PreparedSQL for the sql text and the optional
+// GoColumnTypes, as Ses.Prep would get them.
+func NewPreparedSQL(sql string, gcts ...GoColumnType) *PreparedSQL {
+	return &PreparedSQL{
+		sql:                sql,
+		gcts:               append([]GoColumnType(nil), gcts...),
+		lastInsertIdMarked: isLastInsertIdMarked(sql),
+	}
+}
+
+// SQL returns the sql text.
+func (p *PreparedSQL) SQL() string { return p.sql }
+
+// Cfg returns the StmtCfg. The zero StmtCfg means the Ses's StmtCfg.
+func (p *PreparedSQL) Cfg() StmtCfg { return p.cfg }
+
+// SetCfg returns a copy of the PreparedSQL with the given StmtCfg.
+func (p *PreparedSQL) SetCfg(cfg StmtCfg) *PreparedSQL {
+	q := *p
+	q.cfg = cfg
+	return &q
+}
@@ -6,12 +6,16 @@ package ora
 
 /*
 #include <oci.h>
+#include "version.h"
 */
 import "C"
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -25,6 +29,10 @@ const (
 	byteWidth32 = 4
 	byteWidth16 = 2
 	byteWidth8  = 1
+
+	// nlsTimeLen is the buffer size of the DATE and TIMESTAMP columns
+	// defined as TNls, for the longest NLS formats.
+	nlsTimeLen = 256
 )
 
 // LogRsetCfg represents Rset logging configuration values.
@@ -95,6 +103,17 @@ type Rset struct {
 	fetched, offset int64
 	fetchLen        int
 	finished        bool
+	// truncated is set when StmtCfg.MaxRows stopped the fetching
+	truncated bool
+	// defined is set when the columns are defined, at the first fetch
+	defined bool
+	// project is the set of columns to be defined, nil for all
+	project []bool
+	// gcts is set by DefineByName, overriding the statement's gcts
+	gcts []GoColumnType
+	// charsetID is the character set of the string defines (WithCharset),
+	// 0 for the client character set
+	charsetID C.ub2
 
 	sysNamer
 }
@@ -103,8 +122,27 @@ type Column struct {
 	Name      string
 	Type      C.ub2
 	Length    uint32
+	CharSize  uint16 // length in characters of CHAR, VARCHAR2 columns
 	Precision C.sb2
 	Scale     C.sb1
+	TypeName  string // schema-qualified type name of named (SQLT_NTY) columns, e.g. SYS.XMLTYPE
+
+	// DomainSchema and DomainName name the SQL domain of the column,
+	// and Annotations holds the column annotations (Oracle 23 and later).
+	// They are empty for older servers and clients.
+	DomainSchema, DomainName string
+	Annotations              map[string]string
+
+	// Identity is set for identity columns, GeneratedAlways for the
+	// GENERATED ALWAYS (not insertable) ones, and GeneratedOnNull for the
+	// GENERATED BY DEFAULT ON NULL ones (OCI_ATTR_COL_PROPERTIES, Oracle 12c
+	// and later). They are false for older servers and clients.
+	//
+	// OCI does not report virtual columns.
+	Identity, GeneratedAlways, GeneratedOnNull bool
+
+	// charsetForm is the OCI_ATTR_CHARSET_FORM of CLOB and NCLOB columns.
+	charsetForm C.ub1
 }
 
 // Err returns the last error of the reesult set.
@@ -115,11 +153,35 @@ func (rset *Rset) Err() error {
 	return err
 }
 
+// Truncated reports whether the fetching has been stopped by StmtCfg.MaxRows,
+// with more rows left in the result set.
+func (rset *Rset) Truncated() bool {
+	rset.RLock()
+	defer rset.RUnlock()
+	return rset.truncated
+}
+
 // Len returns the number of rows retrieved.
 func (rset *Rset) Len() int {
 	return int(atomic.LoadInt32(&rset.index)) + 1
 }
 
+// PrefetchedRows returns the number of rows fetched from the server and
+// buffered client-side, not yet returned by Next.
+//
+// It is zero before the first Next, and after the last buffered row,
+// when the next Next makes a round-trip, so it can be used to show progress
+// incrementally: the total number of rows is not known before the end of the
+// result set (select COUNT(*) OVER () for that).
+func (rset *Rset) PrefetchedRows() int {
+	rset.RLock()
+	defer rset.RUnlock()
+	if n := rset.fetched - rset.offset; n > 0 {
+		return int(n)
+	}
+	return 0
+}
+
 // checkIsOpen validates that the result set is open.
 func (rset *Rset) checkIsOpen() error {
 	if !rset.IsOpen() {
@@ -223,6 +285,12 @@ func (rset *Rset) beginRow() (err error) {
 		return errF("Rset env is closed")
 	}
 	env := rset.env
+	if !rset.defined {
+		if err = rset.defineColumns(); err != nil {
+			return err
+		}
+		rset.defined = true
+	}
 	for _, define := range rset.defs {
 		//rset.logF(_drv.Cfg().Log.Rset.BeginRow, "defs[%d]=%#v", i, define)
 		if define == nil {
@@ -245,6 +313,9 @@ func (rset *Rset) beginRow() (err error) {
 		C.OCI_DEFAULT)        //ub4         mode );
 	if r == C.OCI_ERROR {
 		err := env.ociError()
+		if rset.stmt != nil {
+			rset.stmt.ses.setLostIf(err)
+		}
 		return err
 	} else if r == C.OCI_NO_DATA {
 		rset.log(_drv.Cfg().Log.Rset.BeginRow, "OCI_NO_DATA")
@@ -278,6 +349,45 @@ func (rset *Rset) beginRow() (err error) {
 	return err
 }
 
+// overMaxRows reports whether the current row is over StmtCfg.MaxRows,
+// marking the Rset as truncated if so.
+func (rset *Rset) overMaxRows() bool {
+	rset.Lock()
+	defer rset.Unlock()
+	if rset.stmt == nil {
+		return false
+	}
+	maxRows := rset.stmt.Cfg().MaxRows
+	if maxRows <= 0 || atomic.LoadInt32(&rset.index) < int32(maxRows) {
+		return false
+	}
+	atomic.AddInt32(&rset.index, -1) // the row is not returned
+	rset.truncated = true
+	return true
+}
+
+// cancelFetch stops the fetching, and closes the cursor
+// (an OCIStmtFetch2 call with zero rows).
+func (rset *Rset) cancelFetch() {
+	rset.Lock()
+	defer rset.Unlock()
+	rset.finished = true
+	rset.fetched = rset.offset
+	if rset.ocistmt == nil || rset.env == nil {
+		return
+	}
+	r := C.OCIStmtFetch2(
+		rset.ocistmt,     //OCIStmt     *stmthp,
+		rset.env.ocierr,  //OCIError    *errhp,
+		C.ub4(0),         //ub4         nrows,
+		C.OCI_FETCH_NEXT, //ub2         orientation,
+		C.sb4(0),         //sb4         fetchOffset,
+		C.OCI_DEFAULT)    //ub4         mode );
+	if r == C.OCI_ERROR {
+		rset.logF(_drv.Cfg().Log.Rset.Next, "cancel fetch: %v", rset.env.ociError())
+	}
+}
+
 // endRow deallocates a handle for each column.
 func (rset *Rset) endRow() {
 	rset.log(_drv.Cfg().Log.Rset.EndRow)
@@ -319,6 +429,9 @@ func (rset *Rset) Exhaust() {
 // Retrieve the loaded row from the Rset.Row field. Rset.Row is updated
 // on each call to Next. Rset.Row is set to nil when Next returns false.
 //
+// With StmtCfg.ReuseRow set, the values in Rset.Row may alias the fetch
+// buffers, so they are valid only until the next call to Next.
+//
 // When Next returns false check Rset.Err() for any error that may have occured.
 func (rset *Rset) Next() bool {
 	rset.log(_drv.Cfg().Log.Rset.Next)
@@ -353,6 +466,11 @@ func (rset *Rset) Next() bool {
 		erase(err)
 		return false
 	}
+	if rset.overMaxRows() {
+		rset.cancelFetch()
+		erase(nil)
+		return false
+	}
 	// populate column values
 	rset.RLock()
 	Row := rset.Row
@@ -360,6 +478,10 @@ func (rset *Rset) Next() bool {
 	offset := rset.offset
 	rset.RUnlock()
 	for n, define := range defs {
+		if define == nil { // not projected
+			Row[n] = nil
+			continue
+		}
 		value, err := define.value(int(offset))
 		//rset.logF(_drv.Cfg().Log.Rset.Next, "value[%d]=%v (%v)", n, value, err)
 		if err != nil {
@@ -387,6 +509,316 @@ func (rset *Rset) NextRow() []interface{} {
 	return rset.Row
 }
 
+// FetchInto fetches at most maxRows rows (all rows if maxRows <= 0)
+// into the column slices: each element of dest must be a pointer to a slice,
+// one for each column, and the values of the column are appended to it.
+// NULL values are appended as the zero value of the element type.
+//
+// Pointer element types (e.g. []*bool for a column defined as B or OraB)
+// get a pointer to the value, or nil for NULL.
+//
+// The rows are fetched in batches of up to MaxFetchLen rows per round-trip,
+// so up to MaxFetchLen rows can be fetched at once, if the select-list
+// does not contain LOB or LONG columns.
+//
+// FetchInto returns the number of rows fetched; it is less than maxRows
+// only at the end of the result set, or on error.
+func (rset *Rset) FetchInto(dest []interface{}, maxRows int) (n int, err error) {
+	if err = rset.checkIsOpen(); err != nil {
+		return 0, err
+	}
+	rset.RLock()
+	numCols := len(rset.Columns)
+	reuse := rset.stmt.Cfg().ReuseRow
+	rset.RUnlock()
+	if len(dest) != numCols {
+		return 0, errF("dest has %d elements, wanted %d (one for each column)", len(dest), numCols)
+	}
+	slices := make([]reflect.Value, len(dest))
+	for i, d := range dest {
+		rv := reflect.ValueOf(d)
+		if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Slice {
+			return 0, errF("dest[%d] is %T, not a pointer to a slice", i, d)
+		}
+		slices[i] = rv.Elem()
+	}
+	for (maxRows <= 0 || n < maxRows) && rset.Next() {
+		for i, v := range rset.Row {
+			s := slices[i]
+			et := s.Type().Elem()
+			if b, ok := v.(Bool); ok && et.Kind() == reflect.Ptr {
+				if b.IsNull {
+					v = nil
+				} else {
+					v = b.Value
+				}
+			}
+			var ev reflect.Value
+			if v == nil {
+				ev = reflect.Zero(et)
+			} else {
+				if reuse { // don't keep references to the fetch buffers
+					switch x := v.(type) {
+					case string:
+						v = string([]byte(x))
+					case []byte:
+						v = append([]byte(nil), x...)
+					}
+				}
+				ev = reflect.ValueOf(v)
+				if t := ev.Type(); !t.AssignableTo(et) {
+					if et.Kind() == reflect.Ptr && t.ConvertibleTo(et.Elem()) {
+						p := reflect.New(et.Elem())
+						p.Elem().Set(ev.Convert(et.Elem()))
+						ev = p
+					} else if !t.ConvertibleTo(et) {
+						return n, errF("column %d: cannot put %T into %s", i, v, s.Type())
+					} else {
+						ev = ev.Convert(et)
+					}
+				}
+			}
+			s.Set(reflect.Append(s, ev))
+		}
+		n++
+	}
+	return n, rset.Err()
+}
+
+// RowEncoder encodes the rows of a Rset for StreamEncoded, i.e. with
+// protobuf or msgpack, keeping the encoding out of the driver.
+type RowEncoder interface {
+	// EncodeRow returns the encoding of row, which holds the values of a
+	// fetched row (as Rset.Row), in the order of cols.
+	//
+	// The values may alias the fetch buffers (see StmtCfg.ReuseRow),
+	// so they must not be retained; the returned bytes are sent to the
+	// receiver, so they must not be reused.
+	EncodeRow(cols []Column, row []interface{}) ([]byte, error)
+}
+
+// StreamEncoded fetches the rows of the Rset in a new goroutine, encodes
+// each one with enc, and sends the encoded rows on the returned channel,
+// which is closed after the last row.
+//
+// Fetching stops on the first error (of the fetch, or of enc), or when
+// ctx is cancelled; the error (ctx.Err() for cancellation) is sent on the
+// error channel, which is closed after the rows channel.
+// The receiver must drain the rows channel, or cancel ctx.
+//
+// The Rset must not be used otherwise while streaming.
+func (rset *Rset) StreamEncoded(ctx context.Context, enc RowEncoder) (<-chan []byte, <-chan error) {
+	rows := make(chan []byte, MaxFetchLen)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(rows)
+		stop := func(err error) {
+			rset.cancelFetch()
+			errc <- err
+		}
+		for {
+			if err := ctx.Err(); err != nil {
+				stop(err)
+				return
+			}
+			if !rset.Next() {
+				break
+			}
+			b, err := enc.EncodeRow(rset.Columns, rset.Row)
+			if err != nil {
+				stop(err)
+				return
+			}
+			select {
+			case rows <- b:
+			case <-ctx.Done():
+				stop(ctx.Err())
+				return
+			}
+		}
+		if err := rset.Err(); err != nil {
+			errc <- err
+		}
+	}()
+	return rows, errc
+}
+
+// ErrNullValue is returned by Rset.Scan when a NULL value would be
+// put into a non-nullable destination.
+type ErrNullValue struct {
+	// Index and Name identify the column.
+	Index int
+	Name  string
+}
+
+func (e *ErrNullValue) Error() string {
+	return fmt.Sprintf("ora: column %d (%s) is NULL", e.Index, e.Name)
+}
+
+// Scan copies the values of the current row (see Next) into dest:
+// each element of dest must be a pointer, one for each column.
+//
+// A NULL value can be put into a pointer (which is set to nil), or into
+// a nullable type of this package (e.g. an Int64 with IsNull set).
+// Scanning a NULL into any other destination (e.g. an *int64) returns an
+// *ErrNullValue identifying the column, instead of the zero value.
+//
+// Non-NULL values of the nullable types are unwrapped for the non-nullable
+// destinations, and numbers are converted, so a column defined as OraI64
+// can be scanned into an *int64, or into an **int64.
+//
+// There's no struct scanning (ScanStruct) in this package: Scan is the
+// only method putting the row into Go variables.
+func (rset *Rset) Scan(dest ...interface{}) error {
+	if err := rset.checkIsOpen(); err != nil {
+		return err
+	}
+	rset.RLock()
+	row, columns := rset.Row, rset.Columns
+	rset.RUnlock()
+	if row == nil {
+		return errNew("no current row: call Next first")
+	}
+	if len(dest) != len(row) {
+		return errF("dest has %d elements, wanted %d (one for each column)", len(dest), len(row))
+	}
+	for i, d := range dest {
+		rv := reflect.ValueOf(d)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return errF("dest[%d] is %T, not a non-nil pointer", i, d)
+		}
+		if err := scanValue(rv.Elem(), row[i]); err != nil {
+			if _, ok := err.(*ErrNullValue); ok {
+				return &ErrNullValue{Index: i, Name: columns[i].Name}
+			}
+			return errF("column %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// scanValue sets dst to v, as described at Rset.Scan.
+// A NULL for a non-nullable dst is reported as an empty *ErrNullValue.
+func scanValue(dst reflect.Value, v interface{}) error {
+	et := dst.Type()
+	if v != nil {
+		if t := reflect.TypeOf(v); t.AssignableTo(et) {
+			dst.Set(reflect.ValueOf(v))
+			return nil
+		}
+	}
+	ev := reflect.ValueOf(v)
+	if v != nil && ev.Kind() == reflect.Struct { // unwrap the nullable types
+		if isNull := ev.FieldByName("IsNull"); isNull.IsValid() && isNull.Kind() == reflect.Bool {
+			if isNull.Bool() {
+				v = nil
+			} else if value := ev.FieldByName("Value"); value.IsValid() {
+				ev, v = value, value.Interface()
+			}
+		}
+	}
+	if v == nil {
+		switch et.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			dst.Set(reflect.Zero(et))
+			return nil
+		}
+		return &ErrNullValue{}
+	}
+	t := ev.Type()
+	// don't convert numbers to strings (as runes)
+	convertible := func(et reflect.Type) bool {
+		return t.ConvertibleTo(et) && (et.Kind() != reflect.String || t.Kind() == reflect.String)
+	}
+	switch {
+	case t.AssignableTo(et):
+		dst.Set(ev)
+	case et.Kind() == reflect.Ptr && convertible(et.Elem()):
+		p := reflect.New(et.Elem())
+		p.Elem().Set(ev.Convert(et.Elem()))
+		dst.Set(p)
+	case convertible(et):
+		dst.Set(ev.Convert(et))
+	default:
+		return errF("cannot put %T into %s", v, et)
+	}
+	return nil
+}
+
+// UpdateByRowid updates the row of the selected ROWID of the current row, by
+// running "UPDATE <setClause> WHERE ROWID = :current_rowid" on the session of
+// the Rset. The setClause names the table and the SET list, such as
+// "emp SET sal = :1", and params are its bind values; the ROWID is bound
+// after them.
+//
+// By convention, the select-list contains the ROWID of the table, as the
+// ROWID is read from the first ROWID column of the current row (not from
+// the cursor): select it with FOR UPDATE, in a transaction, to keep the row
+// locked, such as "SELECT ROWID, ... FROM emp ... FOR UPDATE".
+func (rset *Rset) UpdateByRowid(setClause string, params ...interface{}) error {
+	rset.RLock()
+	stmt, cols, row := rset.stmt, rset.Columns, rset.Row
+	rset.RUnlock()
+	if stmt == nil {
+		return er("Rset is closed.")
+	}
+	idx := -1
+	for i, col := range cols {
+		if col.Type == C.SQLT_RDD {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return er("UpdateByRowid needs the ROWID in the select-list.")
+	}
+	if row == nil {
+		return er("There is no current row.")
+	}
+	rowid, ok := row[idx].(string)
+	if !ok || rowid == "" {
+		return errF("no ROWID in column %d (%#v)", idx, row[idx])
+	}
+	stmt.RLock()
+	ses := stmt.ses
+	stmt.RUnlock()
+	upd, err := ses.Prep("UPDATE " + setClause + " WHERE ROWID = :current_rowid")
+	if err != nil {
+		return err
+	}
+	defer upd.Close()
+	args := make([]interface{}, 0, len(params)+1)
+	args = append(append(args, params...), rowid)
+	n, err := upd.Exe(args...)
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return errF("updated %d rows, wanted 1", n)
+	}
+	return nil
+}
+
+// RowSCN returns the ORA_ROWSCN of the current row, for optimistic locking.
+//
+// ORA_ROWSCN must be in the select-list, without an alias.
+func (rset *Rset) RowSCN() (uint64, error) {
+	rset.RLock()
+	cols, row := rset.Columns, rset.Row
+	rset.RUnlock()
+	for i, col := range cols {
+		if col.Name != "ORA_ROWSCN" {
+			continue
+		}
+		if row == nil {
+			return 0, er("There is no current row.")
+		}
+		return scnValue(row[i])
+	}
+	return 0, er("ORA_ROWSCN is not in the select-list.")
+}
+
 var defStringPool = sync.Pool{New: func() interface{} { return &defString{} }}
 
 // gets a define struct from a driver slice
@@ -416,6 +848,7 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 	rset.offset = 0
 	rset.fetched = 0
 	rset.finished = false
+	rset.truncated = false
 	rset.err = nil
 	defs, Columns, Row := rset.defs, rset.Columns, rset.Row
 	rset.defs, rset.Columns, rset.Row = nil, nil, nil
@@ -469,6 +902,8 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		param      *C.OCIParam
 	}
 	params := make([]paramS, len(defs))
+	hasDomains := C.OCI_ATTR_DOMAIN_NAME != 0 && ses.srv.majorVersion() >= 23
+	hasColProps := C.OCI_ATTR_COL_PROPERTIES != 0 && ses.srv.majorVersion() >= 12
 	defer func() {
 		for _, param := range params {
 			if param.param == nil {
@@ -478,7 +913,6 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 		}
 	}()
 
-	var gct GoColumnType
 	for n := range defs {
 		// Create oci parameter handle; may be freed by OCIDescriptorFree()
 		// parameter position is 1-based
@@ -514,52 +948,192 @@ func (rset *Rset) open(stmt *Stmt, ocistmt *C.OCIStmt) error {
 			Type:   params[n].typeCode,
 			Length: params[n].columnSize,
 		}
+		if hasDomains {
+			if err = rset.describeDomain(ocipar, &Columns[n]); err != nil {
+				return err
+			}
+		}
+		if hasColProps {
+			var props C.ub8
+			if err = rset.paramAttr(ocipar, unsafe.Pointer(&props), nil, C.OCI_ATTR_COL_PROPERTIES); err != nil {
+				return err
+			}
+			Columns[n].setColProperties(uint64(props))
+		}
+		if typ := params[n].typeCode; typ == C.SQLT_CHR || typ == C.SQLT_AFC {
+			// Get column size in characters
+			var charSize C.ub2
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&charSize), nil, C.OCI_ATTR_CHAR_SIZE)
+			if err != nil {
+				return err
+			}
+			Columns[n].CharSize = uint16(charSize)
+		} else if typ == C.SQLT_CLOB {
+			// SQLCS_NCHAR for NCLOB
+			if err = rset.paramAttr(ocipar, unsafe.Pointer(&Columns[n].charsetForm), nil, C.OCI_ATTR_CHARSET_FORM); err != nil {
+				return err
+			}
+		} else if typ == C.SQLT_NTY {
+			// Get the type's schema and name
+			var schemaName, typeName *C.char
+			var schemaLen, typeLen C.ub4
+			if err = rset.paramAttr(ocipar, unsafe.Pointer(&schemaName), &schemaLen, C.OCI_ATTR_SCHEMA_NAME); err != nil {
+				return err
+			}
+			if err = rset.paramAttr(ocipar, unsafe.Pointer(&typeName), &typeLen, C.OCI_ATTR_TYPE_NAME); err != nil {
+				return err
+			}
+			Columns[n].TypeName = C.GoStringN(schemaName, C.int(schemaLen)) + "." + C.GoStringN(typeName, C.int(typeLen))
+		} else if typ == C.SQLT_NUM || typ == C.SQLT_INT {
+			// Get precision
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&Columns[n].Precision), nil, C.OCI_ATTR_PRECISION)
+			if err != nil {
+				return err
+			}
+			// Get scale (the number of decimal places)
+			err = rset.paramAttr(ocipar, unsafe.Pointer(&Columns[n].Scale), nil, C.OCI_ATTR_SCALE)
+			if err != nil {
+				return err
+			}
+		}
 		rset.logF(logCfg.Rset.OpenDefs, "%d. %s/%d", n+1, Columns[n].Name, params[n].typeCode)
 	}
 
+	for n := range defs {
+		defs[n] = nil
+	}
+	rset.defs, rset.Columns, rset.Row = defs, Columns, Row
+	rset.project = nil
+	rset.gcts = nil
+	rset.defined = false
+	return nil
+}
+
+// Project restricts the fetched columns to the ones at the given
+// (0-based) indexes of Rset.Columns.
+//
+// The other columns are not defined, so they are not fetched and converted,
+// and their value in Rset.Row is always nil.
+//
+// Project must be called before the first call to Next.
+func (rset *Rset) Project(colIndexes []int) error {
+	if err := rset.checkIsOpen(); err != nil {
+		return err
+	}
+	rset.Lock()
+	defer rset.Unlock()
+	if rset.defined {
+		return er("Project must be called before the first Next.")
+	}
+	project := make([]bool, len(rset.Columns))
+	for _, i := range colIndexes {
+		if i < 0 || i >= len(project) {
+			return errF("column index %d out of range [0, %d)", i, len(project))
+		}
+		project[i] = true
+	}
+	rset.project = project
+	return nil
+}
+
+// DefineByName sets the GoColumnType of the columns by their name
+// (case-insensitively), regardless of their position in the select-list.
+// Columns not in mapping use the default mapping (D).
+//
+// It overrides the positional GoColumnTypes given to Ses.Prep or
+// Stmt.SetGcts, for this Rset only.
+//
+// DefineByName must be called before the first call to Next.
+func (rset *Rset) DefineByName(mapping map[string]GoColumnType) error {
+	if err := rset.checkIsOpen(); err != nil {
+		return err
+	}
+	rset.Lock()
+	defer rset.Unlock()
+	if rset.defined {
+		return er("DefineByName must be called before the first Next.")
+	}
+	gcts := make([]GoColumnType, len(rset.Columns))
+	for i := range gcts {
+		gcts[i] = D
+	}
+Mapping:
+	for name, gct := range mapping {
+		for i, col := range rset.Columns {
+			if strings.EqualFold(col.Name, name) {
+				gcts[i] = gct
+				continue Mapping
+			}
+		}
+		return errF("column %q not found in the select-list", name)
+	}
+	rset.gcts = gcts
+	return nil
+}
+
+// RowMap returns the values of the current row keyed by column name.
+func (rset *Rset) RowMap() map[string]interface{} {
+	rset.RLock()
+	defer rset.RUnlock()
+	m := make(map[string]interface{}, len(rset.Columns))
+	for i, col := range rset.Columns {
+		if i < len(rset.Row) {
+			m[col.Name] = rset.Row[i]
+		}
+	}
+	return m
+}
+
+// defineColumns defines the (projected) select-list columns,
+// right before the first fetch.
+func (rset *Rset) defineColumns() (err error) {
+	logCfg := _drv.Cfg().Log
+	defs, project := rset.defs, rset.project
 	fetchLen := MaxFetchLen
 Loop:
-	for _, param := range params {
-		switch param.typeCode {
+	for n, col := range rset.Columns {
+		if project != nil && !project[n] {
+			continue
+		}
+		switch col.Type {
 		// These can consume a lot of memory.
-		case C.SQLT_LNG, C.SQLT_BFILE, C.SQLT_BLOB, C.SQLT_CLOB, C.SQLT_LBI:
+		case C.SQLT_LNG, C.SQLT_BFILE, C.SQLT_BLOB, C.SQLT_CLOB, C.SQLT_LBI, C.SQLT_NTY:
 			fetchLen = MinFetchLen
 			break Loop
 		}
 	}
-
-	rset.defs, rset.Columns, rset.Row = defs, Columns, Row
+	cfg := rset.stmt.Cfg()
+	if rset.stmt.noPrefetch(cfg) {
+		// fetch only the rows the caller consumes (see NoPrefetchForUpdate)
+		fetchLen = 1
+	}
 	rset.fetchLen = fetchLen
 
-	cfg := rset.stmt.Cfg()
 	//rset.logF(logCfg.Rset.Open, "cfg=%#v", cfg)
-	stmt.RLock()
-	gcts := stmt.gcts
-	stmt.RUnlock()
+	rset.stmt.RLock()
+	gcts := rset.stmt.gcts
+	rset.stmt.RUnlock()
+	if rset.gcts != nil {
+		gcts = rset.gcts
+	}
+	var gct GoColumnType
 	for n := range defs {
-		ocipar := params[n].param
-		ociTypeCode := params[n].typeCode
-		columnSize := params[n].columnSize
+		if project != nil && !project[n] {
+			continue
+		}
+		ociTypeCode := rset.Columns[n].Type
+		columnSize := rset.Columns[n].Length
 
 		switch ociTypeCode {
 		case C.SQLT_NUM, C.SQLT_INT: // TimesTen may return an SQLT_INT
 			// NUMBER
-			// Get precision
-			var precision C.sb2
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&precision), nil, C.OCI_ATTR_PRECISION)
-			if err != nil {
-				return err
-			}
-			// Get scale (the number of decimal places)
-			var scale C.sb1
-			err = rset.paramAttr(ocipar, unsafe.Pointer(&scale), nil, C.OCI_ATTR_SCALE)
-			if err != nil {
-				return err
-			}
-			rset.Columns[n].Precision = precision
-			rset.Columns[n].Scale = scale
+			precision, scale := rset.Columns[n].Precision, rset.Columns[n].Scale
 			if gcts == nil || n >= len(gcts) || gcts[n] == D {
-				gct = cfg.numericColumnType(int(precision), int(scale))
+				if cfg.NumberSmartString {
+					gct = smartNumericColumnType(int(precision), int(scale))
+				} else {
+					gct = cfg.numericColumnType(int(precision), int(scale))
+				}
 			} else {
 				err = checkNumericColumn(gcts[n], rset.Columns[n].Name)
 				if err != nil {
@@ -613,13 +1187,16 @@ Loop:
 				}
 				gct = gcts[n]
 			}
-			isNullable := false
-			if gct == OraT {
-				isNullable = true
+			if gct == TNls {
+				defs[n], err = rset.defineString(n, nlsTimeLen, S, false)
+				if err != nil {
+					return err
+				}
+				break
 			}
 			def := rset.getDef(defIdxDate).(*defDate)
 			defs[n] = def
-			err = def.define(n+1, isNullable, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -641,13 +1218,16 @@ Loop:
 				}
 				gct = gcts[n]
 			}
-			isNullable := false
-			if gct == OraT {
-				isNullable = true
+			if gct == TNls {
+				defs[n], err = rset.defineString(n, nlsTimeLen, S, false)
+				if err != nil {
+					return err
+				}
+				break
 			}
 			def := rset.getDef(defIdxTime).(*defTime)
 			defs[n] = def
-			err = def.define(n+1, isNullable, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -732,7 +1312,7 @@ Loop:
 			}
 
 			// longBufferSize: Use a moderate default buffer size; 2GB max buffer may not be feasible on all clients
-			defs[n], err = rset.defineString(n, stmt.Cfg().longBufferSize, gct, false)
+			defs[n], err = rset.defineString(n, cfg.longBufferSize, gct, false)
 			if err != nil {
 				return err
 			}
@@ -752,7 +1332,7 @@ Loop:
 
 			def := rset.getDef(defIdxLob).(*defLob)
 			defs[n] = def
-			err = def.define(n+1, C.SQLT_CLOB, gct, rset)
+			err = def.define(n+1, C.SQLT_CLOB, rset.Columns[n].charsetForm, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -771,7 +1351,7 @@ Loop:
 			}
 			def := rset.getDef(defIdxLob).(*defLob)
 			defs[n] = def
-			err = def.define(n+1, C.SQLT_BLOB, gct, rset)
+			err = def.define(n+1, C.SQLT_BLOB, C.SQLCS_IMPLICIT, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -780,19 +1360,15 @@ Loop:
 			if gcts == nil || n >= len(gcts) || gcts[n] == D {
 				gct = cfg.raw
 			} else {
-				err = checkBinColumn(gcts[n])
+				err = checkRawColumn(gcts[n])
 				if err != nil {
 					return err
 				}
 				gct = gcts[n]
 			}
-			isNullable := false
-			if gct == OraBin {
-				isNullable = true
-			}
 			def := rset.getDef(defIdxRaw).(*defRaw)
 			defs[n] = def
-			err = def.define(n+1, int(columnSize), isNullable, rset)
+			err = def.define(n+1, int(columnSize), gct, rset)
 			if err != nil {
 				return err
 			}
@@ -819,16 +1395,30 @@ Loop:
 				return err
 			}
 		case C.SQLT_INTERVAL_YM:
+			gct = D
+			if gcts != nil && n < len(gcts) {
+				if err = checkIntervalColumn(gcts[n], Months); err != nil {
+					return err
+				}
+				gct = gcts[n]
+			}
 			def := rset.getDef(defIdxIntervalYM).(*defIntervalYM)
 			defs[n] = def
-			err = def.define(n+1, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
 		case C.SQLT_INTERVAL_DS:
+			gct = D
+			if gcts != nil && n < len(gcts) {
+				if err = checkIntervalColumn(gcts[n], Dur); err != nil {
+					return err
+				}
+				gct = gcts[n]
+			}
 			def := rset.getDef(defIdxIntervalDS).(*defIntervalDS)
 			defs[n] = def
-			err = def.define(n+1, rset)
+			err = def.define(n+1, gct, rset)
 			if err != nil {
 				return err
 			}
@@ -848,6 +1438,29 @@ Loop:
 			if err != nil {
 				return err
 			}
+		case C.SQLT_NTY:
+			// XMLType, by its serialized image
+			if env := rset.stmt.Env(); !env.isObject {
+				return errF("column %s of type %s needs an environment opened with DrvCfg.Object (OCI_OBJECT)", rset.Columns[n].Name, rset.Columns[n].TypeName)
+			}
+			if !strings.EqualFold(rset.Columns[n].TypeName, XMLTypeName) {
+				return errF("column %s of type %s is not supported: only %s columns can be fetched of the named types", rset.Columns[n].Name, rset.Columns[n].TypeName, XMLTypeName)
+			}
+			if gcts == nil || n >= len(gcts) || gcts[n] == D {
+				gct = opaqueGct(rset.Columns[n].TypeName)
+			} else {
+				err = checkOpaqueColumn(gcts[n])
+				if err != nil {
+					return err
+				}
+				gct = gcts[n]
+			}
+			def := rset.getDef(defIdxOpaque).(*defOpaque)
+			defs[n] = def
+			err = def.define(n+1, cfg.longBufferSize, gct, rset)
+			if err != nil {
+				return err
+			}
 		case C.SQLT_RSET:
 			def := rset.getDef(defIdxRset).(*defRset)
 			defs[n] = def
@@ -868,7 +1481,16 @@ func (rset *Rset) defineString(n int, columnSize uint32, gct GoColumnType, rTrim
 	if gct == OraS {
 		isNullable = true
 	}
-	rTrim = rTrim && rset.stmt.Cfg().RTrimChar
+	cfg := rset.stmt.Cfg()
+	rTrim = rTrim && cfg.RTrimChar
+	if charSize := uint32(rset.Columns[n].CharSize); charSize > 0 {
+		// size by characters, as the column size is in the db charset's bytes
+		if cfg.MaxCharBytes > 0 {
+			columnSize = charSize * uint32(cfg.MaxCharBytes)
+		} else if size := charSize * uint32(rset.env.maxBytesPerChar()); size > columnSize {
+			columnSize = size
+		}
+	}
 	D := rset.getDef(defIdxString).(*defString)
 	return D, D.define(n+1, int(columnSize), isNullable, rTrim, rset)
 }
@@ -940,25 +1562,71 @@ func (rset *Rset) defineNumeric(n int, gct GoColumnType) (def, error) {
 	}).define(n+1, nullable, rset)
 }
 
-// paramAttr gets an attribute from the parameter handle.
-func (rset *Rset) paramAttr(ocipar *C.OCIParam, attrup unsafe.Pointer, attrSizep *C.ub4, attrType C.ub4) error {
-	if attrSizep == nil {
-		attrSizep = new(C.ub4)
+// setColProperties sets the identity flags from OCI_ATTR_COL_PROPERTIES.
+func (col *Column) setColProperties(props uint64) {
+	col.Identity = props&C.OCI_ATTR_COL_PROPERTY_IS_IDENTITY != 0
+	col.GeneratedAlways = props&C.OCI_ATTR_COL_PROPERTY_IS_GEN_ALWAYS != 0
+	col.GeneratedOnNull = props&C.OCI_ATTR_COL_PROPERTY_IS_GEN_BY_DEF_ON_NULL != 0
+}
+
+// describeDomain fills the SQL domain and the annotations of col
+// from the parameter handle (Oracle 23 and later).
+func (rset *Rset) describeDomain(ocipar *C.OCIParam, col *Column) error {
+	var name *C.char
+	var nameLen C.ub4
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&name), &nameLen, C.OCI_ATTR_DOMAIN_SCHEMA); err != nil {
+		return err
 	}
-	env := rset.env
-	r := C.OCIAttrGet(
-		unsafe.Pointer(ocipar), //const void     *trgthndlp,
-		C.OCI_DTYPE_PARAM,      //ub4            trghndltyp,
-		attrup,                 //void           *attributep,
-		attrSizep,              //ub4            *sizep,
-		attrType,               //ub4            attrtype,
-		env.ocierr)             //OCIError       *errhp );
-	if r == C.OCI_ERROR {
-		return env.ociError()
+	col.DomainSchema = C.GoStringN(name, C.int(nameLen))
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&name), &nameLen, C.OCI_ATTR_DOMAIN_NAME); err != nil {
+		return err
+	}
+	col.DomainName = C.GoStringN(name, C.int(nameLen))
+
+	var num C.ub4
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&num), nil, C.OCI_ATTR_NUM_ANNOTATIONS); err != nil {
+		return err
+	}
+	if num == 0 {
+		return nil
+	}
+	var annots *C.OCIParam
+	if err := rset.paramAttr(ocipar, unsafe.Pointer(&annots), nil, C.OCI_ATTR_LIST_ANNOTATIONS); err != nil {
+		return err
+	}
+	defer C.OCIDescriptorFree(unsafe.Pointer(annots), C.OCI_DTYPE_PARAM)
+	col.Annotations = make(map[string]string, int(num))
+	for i := 1; i <= int(num); i++ {
+		var annot *C.OCIParam
+		if r := C.OCIParamGet(
+			unsafe.Pointer(annots),                    //const void        *hndlp,
+			C.OCI_DTYPE_PARAM,                         //ub4               htype,
+			rset.env.ocierr,                           //OCIError          *errhp,
+			(*unsafe.Pointer)(unsafe.Pointer(&annot)), //void              **parmdpp,
+			C.ub4(i), //ub4               pos );
+		); r == C.OCI_ERROR {
+			return rset.env.ociError()
+		}
+		var key, value *C.char
+		var keyLen, valueLen C.ub4
+		err := rset.paramAttr(annot, unsafe.Pointer(&key), &keyLen, C.OCI_ATTR_ANNOTATION_KEY)
+		if err == nil {
+			err = rset.paramAttr(annot, unsafe.Pointer(&value), &valueLen, C.OCI_ATTR_ANNOTATION_VALUE)
+		}
+		C.OCIDescriptorFree(unsafe.Pointer(annot), C.OCI_DTYPE_PARAM)
+		if err != nil {
+			return err
+		}
+		col.Annotations[C.GoStringN(key, C.int(keyLen))] = C.GoStringN(value, C.int(valueLen))
 	}
 	return nil
 }
 
+// paramAttr gets an attribute from the parameter handle.
+func (rset *Rset) paramAttr(ocipar *C.OCIParam, attrup unsafe.Pointer, attrSizep *C.ub4, attrType C.ub4) error {
+	return rset.env.paramAttr(ocipar, attrup, attrSizep, attrType)
+}
+
 // attr gets an attribute from the statement handle.
 func (rset *Rset) attr(attrup unsafe.Pointer, attrSize C.ub4, attrType C.ub4) error {
 	env := rset.env
@@ -989,11 +1657,7 @@ func (rset *Rset) log(enabled bool, v ...interface{}) {
 	if !logCfg.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		logCfg.Logger.Infof("%v %v", rset.sysName(), callInfo(1))
-	} else {
-		logCfg.Logger.Infof("%v %v %v", rset.sysName(), callInfo(1), fmt.Sprint(v...))
-	}
+	logCfg.emit(LogEvent{Component: "Rset", Operation: callOp(1), SysName: rset.sysName(), SQL: rset.sql(), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Rset system name and caller info.
@@ -1002,9 +1666,17 @@ func (rset *Rset) logF(enabled bool, format string, v ...interface{}) {
 	if !logCfg.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		logCfg.Logger.Infof("%v %v", rset.sysName(), callInfo(1))
-	} else {
-		logCfg.Logger.Infof("%v %v %v", rset.sysName(), callInfo(1), fmt.Sprintf(format, v...))
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
+	}
+	logCfg.emit(LogEvent{Component: "Rset", Operation: callOp(1), SysName: rset.sysName(), SQL: rset.sql(), Msg: msg})
+}
+
+// sql returns the SQL text of the Rset's statement, if there's any.
+func (rset *Rset) sql() string {
+	if rset == nil || rset.stmt == nil {
+		return ""
 	}
+	return rset.stmt.sql
 }
@@ -37,6 +37,15 @@ type RsetCfg struct {
 	// The is default is '1'.
 	TrueRune rune
 
+	// TimeZoneRegion makes TIMESTAMP WITH TIME ZONE values stored with
+	// a time zone region name (i.e. America/New_York) returned in the named
+	// time.Location, so DST-aware arithmetic is correct.
+	// If false, or the region is unknown, the zone is collapsed to a fixed
+	// offset.
+	//
+	// The default is true.
+	TimeZoneRegion bool
+
 	// Err is the error from the last Set... method.
 	Err error
 }
@@ -67,6 +76,7 @@ func NewRsetCfg() RsetCfg {
 	c.longRaw = Bin
 
 	c.TrueRune = '1'
+	c.TimeZoneRegion = true
 	return c
 }
 
@@ -271,7 +281,10 @@ func (c RsetCfg) BinaryFloat() GoColumnType {
 }
 
 // SetFloat sets a GoColumnType associated to an Oracle select-list
-// FLOAT column.
+// FLOAT column: FLOAT(p) (a NUMBER with binary precision p),
+// and NUMBER without precision and scale.
+//
+// Set it to N or OraN to get the digits as a string, without rounding.
 //
 // Valid values are I64, I32, I16, I8, U64, U32, U16, U8, F64, F32, OraI64,
 // OraI32, OraI16, OraI8, OraU64, OraU32, OraU16, OraU8, OraF64, OraF32,
@@ -306,7 +319,7 @@ func (c RsetCfg) Float() GoColumnType {
 // SetDate sets a GoColumnType associated to an Oracle select-list
 // DATE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, TUnix, TUnixMs and TNls.
 //
 // Returns an error if a non-time GoColumnType is specified.
 func (c RsetCfg) SetDate(gct GoColumnType) RsetCfg {
@@ -337,7 +350,7 @@ func (c RsetCfg) Date() GoColumnType {
 // SetTimestamp sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, TUnix, TUnixMs and TNls.
 //
 // Returns an error if a non-time GoColumnType is specified.
 func (c RsetCfg) SetTimestamp(gct GoColumnType) RsetCfg {
@@ -367,7 +380,7 @@ func (c RsetCfg) Timestamp() GoColumnType {
 // SetTimestampTz sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP WITH TIME ZONE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, TUnix, TUnixMs and TNls.
 //
 // Returns an error if a non-time GoColumnType is specified.
 func (c RsetCfg) SetTimestampTz(gct GoColumnType) RsetCfg {
@@ -398,7 +411,7 @@ func (c RsetCfg) TimestampTz() GoColumnType {
 // SetTimestampLtz sets a GoColumnType associated to an Oracle select-list
 // TIMESTAMP WITH LOCAL TIME ZONE column.
 //
-// Valid values are T and OraT.
+// Valid values are T, OraT, TUnix, TUnixMs and TNls.
 //
 // Returns an error if a non-time GoColumnType is specified.
 func (c RsetCfg) SetTimestampLtz(gct GoColumnType) RsetCfg {
@@ -619,11 +632,12 @@ func (c RsetCfg) Blob() GoColumnType {
 // SetRaw sets a GoColumnType associated to an Oracle select-list
 // RAW column.
 //
-// Valid values are Bits and OraBits.
+// Valid values are Bits and OraBits, and S and OraS for the uppercase hex
+// string representation (as SQL*Plus shows SYS_GUID() values).
 //
 // Returns an error if a non-string GoColumnType is specified.
 func (c RsetCfg) SetRaw(gct GoColumnType) RsetCfg {
-	if err := checkBinColumn(gct); err != nil {
+	if err := checkRawColumn(gct); err != nil {
 		if c.Err == nil {
 			c.Err = err
 		}
@@ -678,6 +692,26 @@ func (c RsetCfg) LongRaw() GoColumnType {
 	return c.longRaw
 }
 
+// smartNumericColumnType returns the GoColumnType for the NUMBER/INTEGER
+// column with StmtCfg.NumberSmartString: the native numeric type which
+// holds all its values exactly, or N.
+func smartNumericColumnType(precision, scale int) GoColumnType {
+	switch {
+	case precision == 0: // unconstrained NUMBER, or unknown
+	case scale == -127: // FLOAT(p), with binary precision
+		if precision <= 49 { // 15 decimal digits
+			return F64
+		}
+	case scale <= 0:
+		if precision-scale <= 18 {
+			return I64
+		}
+	case precision <= 15:
+		return F64
+	}
+	return N
+}
+
 // numericColumnType returns the GoColumnType for the NUMBER/INTEGER
 // column, based on precision and scale.
 //
@@ -692,6 +726,9 @@ func (c RsetCfg) numericColumnType(precision, scale int) (gct GoColumnType) {
 	// if the scale is positive, then it is a NUMBER(precision, scale);
 	// otherwise, it's an int.
 	if precision != 0 {
+		if scale == -127 { // FLOAT(p), with binary precision
+			return c.float
+		}
 		if scale == 0 {
 			if precision <= 19 {
 				return c.numberInt
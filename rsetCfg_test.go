@@ -18,6 +18,8 @@ func TestNumericColumnType(t *testing.T) {
 		{6, 3, F64},
 		{3, 0, I64},
 		{0, -127, F32},
+		{126, -127, F32}, // FLOAT
+		{10, -127, F32},  // FLOAT(10)
 		{0, 0, N},
 	} {
 		got := c.numericColumnType(tc.precision, tc.scale)
@@ -27,3 +29,42 @@ func TestNumericColumnType(t *testing.T) {
 		}
 	}
 }
+
+// TestSmartNumericColumnType tests smartNumericColumnType.
+func TestSmartNumericColumnType(t *testing.T) {
+	for i, tc := range []struct {
+		precision, scale int
+		want             GoColumnType
+	}{
+		{0, -127, N}, // NUMBER
+		{0, 0, N},
+		{18, 0, I64},
+		{19, 0, N},
+		{38, 0, N},
+		{5, -2, I64},
+		{17, -2, N},
+		{15, 2, F64},
+		{16, 2, N},
+		{49, -127, F64}, // FLOAT(49)
+		{126, -127, N},  // FLOAT
+	} {
+		if got := smartNumericColumnType(tc.precision, tc.scale); got != tc.want {
+			t.Errorf("%d. (%d,%d) got %s, want %s.",
+				i, tc.precision, tc.scale, GctName(got), GctName(tc.want))
+		}
+	}
+}
+
+func TestColumnSetColProperties(t *testing.T) {
+	for props, want := range map[uint64][3]bool{
+		0:   {false, false, false},
+		0x3: {true, true, false},
+		0x5: {true, false, true},
+	} {
+		var col Column
+		col.setColProperties(props)
+		if got := [3]bool{col.Identity, col.GeneratedAlways, col.GeneratedOnNull}; got != want {
+			t.Errorf("%#x: got %v, wanted %v", props, got, want)
+		}
+	}
+}
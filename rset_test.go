@@ -0,0 +1,57 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScanValue(t *testing.T) {
+	var (
+		i64  int64
+		pi64 *int64
+		oi64 Int64
+		str  string
+	)
+	for i, tc := range []struct {
+		dest interface{}
+		v    interface{}
+		null bool
+		want interface{}
+	}{
+		{dest: &i64, v: int64(1), want: int64(1)},
+		{dest: &i64, v: Int64{Value: 2}, want: int64(2)},
+		{dest: &i64, v: int32(3), want: int64(3)},
+		{dest: &i64, v: nil, null: true},
+		{dest: &i64, v: Int64{IsNull: true}, null: true},
+		{dest: &str, v: String{IsNull: true}, null: true},
+		{dest: &pi64, v: Int64{IsNull: true}, want: (*int64)(nil)},
+		{dest: &pi64, v: nil, want: (*int64)(nil)},
+		{dest: &oi64, v: Int64{IsNull: true}, want: Int64{IsNull: true}},
+		{dest: &str, v: String{Value: "a"}, want: "a"},
+	} {
+		err := scanValue(reflect.ValueOf(tc.dest).Elem(), tc.v)
+		if tc.null {
+			if _, ok := err.(*ErrNullValue); !ok {
+				t.Errorf("%d. got %v, wanted ErrNullValue", i, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d. %v", i, err)
+			continue
+		}
+		if got := reflect.ValueOf(tc.dest).Elem().Interface(); got != tc.want {
+			t.Errorf("%d. got %#v, wanted %#v", i, got, tc.want)
+		}
+	}
+	if err := scanValue(reflect.ValueOf(&pi64).Elem(), int64(4)); err != nil || pi64 == nil || *pi64 != 4 {
+		t.Errorf("got %v (%v), wanted 4", pi64, err)
+	}
+	if err := scanValue(reflect.ValueOf(&str).Elem(), int64(65)); err == nil {
+		t.Errorf("number converted to string %q", str)
+	}
+}
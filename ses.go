@@ -7,13 +7,16 @@ package ora
 /*
 #include <oci.h>
 #include <stdlib.h>
+#include "version.h"
 */
 import "C"
 import (
 	"bytes"
 	"container/list"
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,6 +30,29 @@ type SesCfg struct {
 	Password string
 	Mode     SessionMode
 
+	// ShardingKey and SuperShardingKey are the column values of the
+	// (super) sharding key, for routing the session to the right shard
+	// of a sharded database. The values may be string, []byte, int, int32
+	// or int64.
+	//
+	// They need a session pool (CPool, SPool or DRCPool) and Oracle Client 12.2.
+	ShardingKey, SuperShardingKey []interface{}
+
+	// Edition is the edition (for edition-based redefinition) the session
+	// resolves the database objects against. It must be a simple
+	// (unquoted) identifier; it is set as OCI_ATTR_EDITION at open time.
+	//
+	// The default is empty, which means the database default edition.
+	Edition string
+
+	// Tag requests a pooled session tagged with Tag (see Ses.SetTag),
+	// i.e. which already has the session state (ALTER SESSION settings,
+	// current schema) the tag stands for. If there's no such session in
+	// the pool, another one is returned, and Ses.TagFound reports false.
+	//
+	// It needs a session pool (SPool or DRCPool).
+	Tag string
+
 	StmtCfg
 }
 
@@ -199,13 +225,16 @@ type Ses struct {
 
 	cfg atomic.Value
 	// protects that open/close should not happen at once
-	cmu       sync.Mutex
-	id        uint64
-	env       atomic.Value // cached
-	srv       *Srv
-	ocisvcctx *C.OCISvcCtx
-	ocises    *C.OCISession
-	isLocked  bool
+	cmu sync.Mutex
+	id  uint64
+	// uncommittedRows is the number of rows of the executions pending due to
+	// CommitEvery; 64-bit aligned for atomic access
+	uncommittedRows uint64
+	env             atomic.Value // cached
+	srv             *Srv
+	ocisvcctx       *C.OCISvcCtx
+	ocises          *C.OCISession
+	isLocked        bool
 
 	openStmts *stmtList
 	openTxs   *txList
@@ -213,6 +242,30 @@ type Ses struct {
 	insteadClose func(ses *Ses) error
 	timezone     *time.Location
 
+	// commitEvery and uncommitted are for CommitEvery
+	commitEvery, uncommitted int32
+
+	// tagFound is whether the session is got by SesCfg.Tag,
+	// releaseTag is the tag set by SetTag.
+	tagFound   bool
+	releaseTag string
+
+	// openedAt is the time the session has been opened, for Pool's MaxLifetime
+	openedAt time.Time
+
+	// pinnedStmts are the SQL texts pinned in the statement cache by PinStmt
+	pinnedStmts map[string]struct{}
+	// cachedStmts are the unpinned SQL texts let into the statement cache
+	// while pinnedStmts is not empty, stmtCacheSize is the cache size then.
+	cachedStmts   map[string]struct{}
+	stmtCacheSize int
+
+	// instName and dbName cache InstanceName and DBName
+	instName, dbName string
+
+	// sesUser and proxyUser cache CurrentUser and ProxyUser
+	sesUser, proxyUser string
+
 	sysNamer
 }
 
@@ -300,6 +353,11 @@ func (ses *Ses) close() (err error) {
 		ses.ocises = nil
 		ses.openStmts.clear()
 		ses.openTxs.clear()
+		ses.tagFound, ses.releaseTag = false, ""
+		ses.openedAt = time.Time{}
+		ses.unpinStmts()
+		ses.instName, ses.dbName = "", ""
+		ses.sesUser, ses.proxyUser = "", ""
 		ses.Unlock()
 		_drv.sesPool.Put(ses)
 
@@ -320,7 +378,11 @@ func (ses *Ses) close() (err error) {
 	openTxs, openStmts := ses.openTxs, ses.openStmts
 	env, srv := ses.Env(), ses.srv
 	ocises, ocisvcctx := ses.ocises, ses.ocisvcctx
+	releaseTag := ses.releaseTag
 	ses.RUnlock()
+	if err := ses.settleBatch(); err != nil {
+		errs.PushBack(err)
+	}
 	openTxs.closeAll(errs)
 	openStmts.closeAll(errs) // close statements
 
@@ -333,12 +395,19 @@ func (ses *Ses) close() (err error) {
 			ocises,        //OCISession      *usrhp,
 			C.OCI_DEFAULT) //ub4             mode );
 	} else {
+		var cTag *C.OraText
+		mode := C.ub4(C.OCI_DEFAULT)
+		if releaseTag != "" {
+			cTag = (*C.OraText)(unsafe.Pointer(C.CString(releaseTag)))
+			defer C.free(unsafe.Pointer(cTag))
+			mode = C.OCI_SESSRLS_RETAG
+		}
 		r = C.OCISessionRelease(
-			ocisvcctx,     //OCISvcCtx       *svchp,
-			env.ocierr,    //OCIError        *errhp,
-			nil,           //OraText         *tag,
-			0,             //ub4             tag_len,
-			C.OCI_DEFAULT, //ub4             mode );
+			ocisvcctx,              //OCISvcCtx       *svchp,
+			env.ocierr,             //OCIError        *errhp,
+			cTag,                   //OraText         *tag,
+			C.ub4(len(releaseTag)), //ub4             tag_len,
+			mode,                   //ub4             mode );
 		)
 	}
 	if r == C.OCI_ERROR {
@@ -379,6 +448,52 @@ func (ses *Ses) PrepAndExeP(sql string, params ...interface{}) (rowsAffected uin
 	return ses.prepAndExe(sql, true, params...)
 }
 
+// Result is the result of ExeResult, like database/sql's Result,
+// which *DrvExecResult implements.
+type Result interface {
+	LastInsertId() (int64, error)
+	RowsAffected() (int64, error)
+}
+
+// ExeResult prepares and executes a SQL statement like PrepAndExe, returning
+// both the number of rows affected and the last insert id.
+//
+// The last insert id is returned by an INSERT with a
+// "RETURNING ... /*LastInsertId*/ INTO :x" clause (see
+// StmtCfg.DetectLastInsertId): ExeResult binds the :x placeholder, so
+// params must not contain a value for it. It is 0 for other statements.
+func (ses *Ses) ExeResult(sql string, params ...interface{}) (result Result, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	ses.log(_drv.Cfg().Log.Ses.PrepAndExe)
+	if err = ses.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	stmt, err := ses.Prep(sql)
+	if err != nil {
+		return nil, errE(err)
+	}
+	defer stmt.Close()
+	var res DrvExecResult
+	stmt.RLock()
+	hasLastInsertId := stmt.hasLastInsertId
+	stmt.RUnlock()
+	if hasLastInsertId && stmt.Cfg().DetectLastInsertId {
+		params = append(params[:len(params):len(params)], &res.lastInsertId)
+	}
+	var lastInsertId int64
+	if res.rowsAffected, lastInsertId, err = stmt.exe(params, false); err != nil {
+		return nil, errE(err)
+	}
+	if lastInsertId != 0 { // captured by exe under the database/sql Env
+		res.lastInsertId = lastInsertId
+	}
+	return &res, nil
+}
+
 // prepAndExe prepares and executes a SQL statement returning the number of rows
 // affected and a possible error.
 func (ses *Ses) prepAndExe(sql string, isAssocArray bool, params ...interface{}) (rowsAffected uint64, err error) {
@@ -444,6 +559,28 @@ func (ses *Ses) PrepAndQry(sql string, params ...interface{}) (rset *Rset, err e
 
 // Prep prepares a sql statement returning a *Stmt and possible error.
 func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
+	return ses.prep(sql, isLastInsertIdMarked(sql), gcts)
+}
+
+// PrepFrom prepares the statement described by template, returning
+// a *Stmt and possible error.
+//
+// The Stmt uses the template's StmtCfg, if that is not zero.
+func (ses *Ses) PrepFrom(template *PreparedSQL) (*Stmt, error) {
+	if template == nil {
+		return nil, er("template may not be nil.")
+	}
+	stmt, err := ses.prep(template.sql, template.lastInsertIdMarked, template.gcts)
+	if err != nil {
+		return nil, err
+	}
+	if !template.cfg.IsZero() {
+		stmt.SetCfg(template.cfg)
+	}
+	return stmt, nil
+}
+
+func (ses *Ses) prep(sql string, lastInsertIdMarked bool, gcts []GoColumnType) (stmt *Stmt, err error) {
 	if ses == nil {
 		return nil, er("ses may not be nil.")
 	}
@@ -458,7 +595,23 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 		return nil, errE(err)
 	}
 	ocistmt := (*C.OCIStmt)(nil)
-	cSql := C.CString(sql) // prepare sql text with statement handle
+	prepSql := sql
+	cfg := ses.Cfg().StmtCfg
+	if cfg.MaxRows > 0 && cfg.FetchFirstMaxRows {
+		// fetch one more row, to know whether the result is truncated
+		prepSql = appendFetchFirst(sql, cfg.MaxRows+1)
+	}
+	hasBlockRowCount := cfg.BlockRowCount && isPLSQLBlock(sql)
+	if hasBlockRowCount {
+		prepSql = wrapBlockRowCount(sql)
+	}
+	cacheKey, cached := ses.stmtCacheKey(sql)
+	var cKey *C.OraText
+	if cacheKey != "" {
+		cKey = (*C.OraText)(unsafe.Pointer(C.CString(cacheKey)))
+		defer C.free(unsafe.Pointer(cKey))
+	}
+	cSql := C.CString(prepSql) // prepare sql text with statement handle
 	ses.RLock()
 	env := ses.Env()
 	r := C.OCIStmtPrepare2(
@@ -466,9 +619,9 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 		&ocistmt,                           // OCIStmt       *stmtp,
 		env.ocierr,                         // OCIError      *errhp,
 		(*C.OraText)(unsafe.Pointer(cSql)), // const OraText *stmt,
-		C.ub4(len(sql)),                    // ub4           stmt_len,
-		nil,                                // const OraText *key,
-		C.ub4(0),                           // ub4           keylen,
+		C.ub4(len(prepSql)),                // ub4           stmt_len,
+		cKey,                               // const OraText *key,
+		C.ub4(len(cacheKey)),               // ub4           keylen,
 		C.OCI_NTV_SYNTAX,                   // ub4           language,
 		C.OCI_DEFAULT)                      // ub4           mode );
 	ses.RUnlock()
@@ -493,6 +646,7 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	ses.RUnlock()
 	stmt.sql = sql
 	stmt.gcts = gcts
+	stmt.cacheKey, stmt.uncached = cacheKey, !cached
 	if stmt.id == 0 {
 		stmt.id = _drv.stmtId.nextId()
 	}
@@ -503,6 +657,16 @@ func (ses *Ses) Prep(sql string, gcts ...GoColumnType) (stmt *Stmt, err error) {
 	}
 	stmt.Lock()
 	stmt.stmtType = *((*C.ub2)(st))
+	stmt.hasLastInsertId = stmt.stmtType == C.OCI_STMT_INSERT && lastInsertIdMarked
+	stmt.isForUpdate = stmt.stmtType == C.OCI_STMT_SELECT && isForUpdate(sql)
+	stmt.hasBlockRowCount = hasBlockRowCount
+	stmt.Unlock()
+	isReturning, err := stmt.readIsReturning()
+	if err != nil {
+		stmt.logF(_drv.Cfg().Log.Ses.Prep, "OCI_ATTR_STMT_IS_RETURNING: %v", err)
+	}
+	stmt.Lock()
+	stmt.isReturning = isReturning
 	stmt.Unlock()
 
 	C.free(unsafe.Pointer(st))
@@ -584,6 +748,198 @@ func (ses *Ses) Ins(tbl string, columnPairs ...interface{}) (err error) {
 	return nil
 }
 
+// batchInsertAllRows is the maximum number of rows BatchInsert inserts
+// with one INSERT ALL statement.
+const batchInsertAllRows = 32
+
+// BatchInsert inserts rows into the cols columns of table,
+// returning the number of rows inserted and a possible error.
+//
+// Each row must have a value for each column; a nil value is NULL.
+// The values are bound, never interpolated into the statement text.
+//
+// The table and column names are written into the statement text, so they
+// must be SQL names: simple (e.g. emp_no) or quoted (e.g. "Emp No"), the
+// table optionally prefixed with a schema (e.g. hr.emp); anything else is
+// rejected.
+//
+// Up to 32 rows are inserted by one "INSERT ALL INTO ... SELECT 1 FROM DUAL"
+// statement. More rows are inserted with an array-bound single-row INSERT,
+// when every column holds integers, floats, strings, time.Times or bools
+// (of the same kind within a column); otherwise, in INSERT ALL chunks.
+//
+// There's no Stmt.BatchInsert: the statement text depends on the number of
+// rows, so it can't be prepared in advance.
+func (ses *Ses) BatchInsert(table string, cols []string, rows [][]interface{}) (rowsAffected uint64, err error) {
+	ses.log(_drv.Cfg().Log.Ses.Ins)
+	if err = ses.checkClosed(); err != nil {
+		return 0, errE(err)
+	}
+	if table == "" {
+		return 0, errF("table is empty.")
+	}
+	if !isSqlName(table, true) {
+		return 0, errF("table %q is not a SQL name.", table)
+	}
+	if len(cols) == 0 {
+		return 0, errF("cols is empty.")
+	}
+	for _, col := range cols {
+		if !isSqlName(col, false) {
+			return 0, errF("column %q is not a SQL name.", col)
+		}
+	}
+	for i, row := range rows {
+		if len(row) != len(cols) {
+			return 0, errF("row %d has %d values, wanted %d.", i, len(row), len(cols))
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if len(rows) > batchInsertAllRows {
+		if params, ok := batchColumns(cols, rows); ok {
+			return ses.PrepAndExe(batchInsertSql(table, cols, 1), params...)
+		}
+	}
+	for len(rows) > 0 {
+		chunk := rows
+		if len(chunk) > batchInsertAllRows {
+			chunk = chunk[:batchInsertAllRows]
+		}
+		rows = rows[len(chunk):]
+		params := make([]interface{}, 0, len(chunk)*len(cols))
+		for _, row := range chunk {
+			params = append(params, row...)
+		}
+		n, err := ses.PrepAndExe(batchInsertSql(table, cols, len(chunk)), params...)
+		rowsAffected += n
+		if err != nil {
+			return rowsAffected, errE(err)
+		}
+	}
+	return rowsAffected, nil
+}
+
+// batchInsertSql returns an INSERT statement for n rows of the cols of
+// table: a plain single-row INSERT for n == 1, an INSERT ALL otherwise.
+func batchInsertSql(table string, cols []string, n int) string {
+	buf := new(bytes.Buffer)
+	colList := strings.Join(cols, ", ")
+	if n == 1 {
+		buf.WriteString("INSERT")
+	} else {
+		buf.WriteString("INSERT ALL")
+	}
+	var pos int
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(buf, " INTO %s (%s) VALUES (", table, colList)
+		for j := range cols {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			pos++
+			fmt.Fprintf(buf, ":%d", pos)
+		}
+		buf.WriteString(")")
+	}
+	if n > 1 {
+		buf.WriteString(" SELECT 1 FROM DUAL")
+	}
+	return buf.String()
+}
+
+// batchColumns transposes rows into one nullable slice ([]Int64, []Float64,
+// []String, []Time or []Bool) per column, to be array-bound.
+// It reports false if a column holds any other type, or mixed kinds.
+func batchColumns(cols []string, rows [][]interface{}) ([]interface{}, bool) {
+	params := make([]interface{}, len(cols))
+	for j := range cols {
+		var kind string
+		for _, row := range rows {
+			var k string
+			switch row[j].(type) {
+			case nil:
+				continue
+			case int, int8, int16, int32, int64, uint8, uint16, uint32:
+				k = "int"
+			case float32, float64:
+				k = "float"
+			case string:
+				k = "string"
+			case time.Time:
+				k = "time"
+			case bool:
+				k = "bool"
+			default:
+				return nil, false
+			}
+			if kind != "" && kind != k {
+				return nil, false
+			}
+			kind = k
+		}
+		switch kind {
+		case "int":
+			a := make([]Int64, len(rows))
+			for i, row := range rows {
+				if row[j] == nil {
+					a[i].IsNull = true
+					continue
+				}
+				a[i].Value = reflect.ValueOf(row[j]).Convert(reflect.TypeOf(int64(0))).Int()
+			}
+			params[j] = a
+		case "float":
+			a := make([]Float64, len(rows))
+			for i, row := range rows {
+				if row[j] == nil {
+					a[i].IsNull = true
+					continue
+				}
+				a[i].Value = reflect.ValueOf(row[j]).Float()
+			}
+			params[j] = a
+		case "string":
+			a := make([]String, len(rows))
+			for i, row := range rows {
+				if row[j] == nil {
+					a[i].IsNull = true
+					continue
+				}
+				a[i].Value = row[j].(string)
+			}
+			params[j] = a
+		case "time":
+			a := make([]Time, len(rows))
+			for i, row := range rows {
+				if row[j] == nil {
+					a[i].IsNull = true
+					continue
+				}
+				a[i].Value = row[j].(time.Time)
+			}
+			params[j] = a
+		case "bool":
+			a := make([]Bool, len(rows))
+			for i, row := range rows {
+				if row[j] == nil {
+					a[i].IsNull = true
+					continue
+				}
+				a[i].Value = row[j].(bool)
+			}
+			params[j] = a
+		default: // all NULL
+			params[j] = make([]String, len(rows))
+			for i := range rows {
+				params[j].([]String)[i].IsNull = true
+			}
+		}
+	}
+	return params, true
+}
+
 // Upd composes, prepares and executes a sql UPDATE statement returning a
 // possible error.
 //
@@ -763,6 +1119,65 @@ func (ses *Ses) StartTx(opts ...TxOption) (tx *Tx, err error) {
 	return tx, nil
 }
 
+// StartTxContext starts an Oracle transaction configured by cfg,
+// returning a *Tx and possible error.
+//
+// If ctx is done before the transaction is started, ctx.Err() is returned
+// when the (short, uninterrupted) OCITransStart call finished; a transaction
+// started nevertheless is rolled back.
+func (ses *Ses) StartTxContext(ctx context.Context, cfg TxCfg) (*Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var tx *Tx
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		tx, err = ses.StartTx(cfg.options()...)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return tx, err
+	case <-ctx.Done():
+		// the transaction may have been started nevertheless
+		if err := <-done; err == nil {
+			tx.Rollback()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// WithConsistentSnapshot runs f in a read-only transaction, so all the
+// queries of f see the data as of the same point in time (SCN), as with
+// SET TRANSACTION READ ONLY.
+//
+// The transaction is committed when f returns nil, and rolled back when it
+// returns an error (returned as is) or panics. f must not modify data, nor
+// start or end transactions on ses.
+func (ses *Ses) WithConsistentSnapshot(f func(*Ses) error) (err error) {
+	tx, err := ses.StartTx(TxCfg{ReadOnly: true}.options()...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if value := recover(); value != nil {
+			tx.Rollback()
+			panic(value)
+		}
+	}()
+	if err = f(ses); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			ses.logF(_drv.Cfg().Log.Ses.StartTx, "rollback of the snapshot: %v", rbErr)
+		}
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
 // Ping returns nil when an Oracle server is contacted; otherwise, an error.
 func (ses *Ses) Ping() (err error) {
 	ses.log(_drv.Cfg().Log.Ses.Ping)
@@ -783,7 +1198,7 @@ func (ses *Ses) Ping() (err error) {
 		C.OCI_DEFAULT) //ub4           mode );
 	ses.RUnlock()
 	if r == C.OCI_ERROR {
-		return errE(env.ociError())
+		return errE(ses.setLostIf(env.ociError()))
 	}
 	return nil
 }
@@ -815,6 +1230,271 @@ func (ses *Ses) NumStmt() int {
 	return openStmts.len()
 }
 
+// CommitEvery makes the auto-committed executions of the session commit
+// only at every n-th successful execution, instead of each one, to spare
+// commit round-trips in loops. A commit error is returned by the execution
+// at the batch boundary.
+//
+// The pending executions are committed by Commit, Close, or the next call
+// of CommitEvery. n <= 1 restores committing every execution.
+// Close resets it, also for a session put back into its Pool.
+//
+// If an execution fails after a partial DML (i.e. a chunk of an array DML)
+// or is cancelled, it is rolled back with the pending executions, and a
+// *BatchRollbackError tells the number of rows lost.
+//
+// It has effect only when StmtCfg.IsAutoCommitting is true and no explicit
+// transaction is open (see StartTx).
+func (ses *Ses) CommitEvery(n int) error {
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	if n < 1 {
+		n = 1
+	}
+	var err error
+	if atomic.LoadInt32(&ses.uncommitted) > 0 {
+		err = ses.commit()
+	}
+	atomic.StoreInt32(&ses.commitEvery, int32(n))
+	return err
+}
+
+// Commit commits the executions pending due to CommitEvery.
+//
+// Returns an error if an explicit transaction is open; use Tx.Commit then.
+func (ses *Ses) Commit() error {
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	if ses.InTransaction() {
+		return er("Ses.Commit within an explicit transaction; use Tx.Commit.")
+	}
+	return ses.commit()
+}
+
+// commit commits the current transaction of the session.
+func (ses *Ses) commit() error {
+	ses.log(_drv.Cfg().Log.Tx.Commit)
+	atomic.StoreInt32(&ses.uncommitted, 0)
+	atomic.StoreUint64(&ses.uncommittedRows, 0)
+	ses.RLock()
+	env := ses.Env()
+	r := C.OCITransCommit(
+		ses.ocisvcctx, //OCISvcCtx    *svchp,
+		env.ocierr,    //OCIError     *errhp,
+		C.OCI_DEFAULT) //ub4          flags );
+	ses.RUnlock()
+	if r == C.OCI_ERROR {
+		return errE(ses.setLostIf(env.ociError()))
+	}
+	return nil
+}
+
+// rollback rolls back the current transaction of the session.
+func (ses *Ses) rollback() error {
+	ses.log(_drv.Cfg().Log.Tx.Rollback)
+	atomic.StoreInt32(&ses.uncommitted, 0)
+	atomic.StoreUint64(&ses.uncommittedRows, 0)
+	ses.RLock()
+	env := ses.Env()
+	r := C.OCITransRollback(
+		ses.ocisvcctx, //OCISvcCtx    *svchp,
+		env.ocierr,    //OCIError     *errhp,
+		C.OCI_DEFAULT) //ub4          flags );
+	ses.RUnlock()
+	if r == C.OCI_ERROR {
+		return errE(ses.setLostIf(env.ociError()))
+	}
+	return nil
+}
+
+// HasOpenTransaction reports whether the session has a transaction in
+// progress on the server side, i.e. uncommitted changes, with or without
+// StartTx.
+//
+// It needs Oracle Client 12.1 or later (OCI_ATTR_TRANSACTION_IN_PROGRESS),
+// and costs no round-trip.
+func (ses *Ses) HasOpenTransaction() (bool, error) {
+	if err := ses.checkClosed(); err != nil {
+		return false, errE(err)
+	}
+	if C.OCI_ATTR_TRANSACTION_IN_PROGRESS == 0 {
+		return false, er("HasOpenTransaction needs Oracle Client 12.1 or later.")
+	}
+	var inProgress C.boolean
+	ses.RLock()
+	env := ses.Env()
+	r := C.OCIAttrGet(
+		unsafe.Pointer(ses.ocises),         //const void     *trgthndlp,
+		C.OCI_HTYPE_SESSION,                //ub4            trghndltyp,
+		unsafe.Pointer(&inProgress),        //void           *attributep,
+		nil,                                //ub4            *sizep,
+		C.OCI_ATTR_TRANSACTION_IN_PROGRESS, //ub4            attrtype,
+		env.ocierr)                         //OCIError       *errhp );
+	ses.RUnlock()
+	if r == C.OCI_ERROR {
+		return false, errE(env.ociError())
+	}
+	return inProgress == C.TRUE, nil
+}
+
+// batchCommit counts an execution of rows rows which is not auto-committed
+// due to CommitEvery, and commits when the batch is full.
+func (ses *Ses) batchCommit(rows uint64) error {
+	atomic.AddUint64(&ses.uncommittedRows, rows)
+	if atomic.AddInt32(&ses.uncommitted, 1) < atomic.LoadInt32(&ses.commitEvery) {
+		return nil
+	}
+	return ses.commit()
+}
+
+// settleBatch commits the executions pending due to CommitEvery, and resets
+// CommitEvery, before the session is closed or put back into its pool.
+func (ses *Ses) settleBatch() error {
+	var err error
+	if atomic.LoadInt32(&ses.uncommitted) > 0 && !ses.InTransaction() {
+		err = ses.commit()
+	}
+	atomic.StoreInt32(&ses.commitEvery, 0)
+	atomic.StoreInt32(&ses.uncommitted, 0)
+	atomic.StoreUint64(&ses.uncommittedRows, 0)
+	return err
+}
+
+// BatchRollbackError is returned by an execution which failed or has been
+// cancelled, and rolled back with the executions pending due to CommitEvery,
+// which are lost.
+type BatchRollbackError struct {
+	// Err is the error of the execution.
+	Err error
+	// Rows is the number of rows of the rolled back pending executions.
+	Rows uint64
+}
+
+func (e *BatchRollbackError) Error() string {
+	return fmt.Sprintf("%v; rolled back with %d rows of the executions pending due to CommitEvery", e.Err, e.Rows)
+}
+
+// TagFound reports whether the session has been got from the session pool
+// by the requested SesCfg.Tag, so its session state need not be set again.
+func (ses *Ses) TagFound() bool {
+	ses.RLock()
+	defer ses.RUnlock()
+	return ses.tagFound
+}
+
+// SetTag sets the tag the session is released to the session pool with,
+// on Close. The tag should describe the session state (e.g. "schema=X"),
+// so a later OpenSes with the same SesCfg.Tag can skip setting it again.
+//
+// Observed only for sessions of a session pool (SPool or DRCPool).
+func (ses *Ses) SetTag(tag string) {
+	ses.Lock()
+	ses.releaseTag = tag
+	ses.Unlock()
+}
+
+// PinStmt pins sql in the session's statement cache (see
+// SrvCfg.StmtCacheSize), so frequently used statements are not evicted
+// by one-off ones. It is observed by the following Prep calls.
+//
+// Pinned statements are prepared and released with their SQL text as the
+// statement cache key (tag). As OCI evicts the least recently used
+// statement of a full cache, regardless of the keys, while any statement
+// is pinned, the other statements of the Ses are put back into the cache
+// on Close only while it has room for them besides the pinned ones.
+// The cache is grown to hold all the pinned statements.
+//
+// Statements cached before the first PinStmt are not accounted for, so pin
+// the statements right after opening the Ses.
+//
+// The pins last till the Ses is closed; pooled sessions keep their cache,
+// but not their pins.
+func (ses *Ses) PinStmt(sql string) error {
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	ses.Lock()
+	if ses.pinnedStmts == nil {
+		ses.pinnedStmts = make(map[string]struct{})
+	}
+	ses.pinnedStmts[sql] = struct{}{}
+	delete(ses.cachedStmts, sql)
+	n := len(ses.pinnedStmts)
+	ses.Unlock()
+	return ses.growStmtCache(n)
+}
+
+// UnpinStmt undoes PinStmt: sql is cached (and evicted) like any other
+// statement.
+func (ses *Ses) UnpinStmt(sql string) {
+	ses.Lock()
+	delete(ses.pinnedStmts, sql)
+	if len(ses.pinnedStmts) == 0 {
+		ses.cachedStmts = nil
+	}
+	ses.Unlock()
+}
+
+// unpinStmts drops all the pins of PinStmt. Must be called with ses locked.
+func (ses *Ses) unpinStmts() {
+	ses.pinnedStmts, ses.cachedStmts, ses.stmtCacheSize = nil, nil, 0
+}
+
+// stmtCacheKey returns the statement cache key of sql, and whether the
+// statement is to be put back into the cache on release, as of PinStmt.
+//
+// While any statement is pinned, an unpinned one is cached only if the
+// cache has room for it besides the pinned ones, so OCI won't evict those.
+func (ses *Ses) stmtCacheKey(sql string) (key string, cached bool) {
+	ses.Lock()
+	defer ses.Unlock()
+	if len(ses.pinnedStmts) == 0 {
+		return "", true
+	}
+	if _, ok := ses.pinnedStmts[sql]; ok {
+		return sql, true
+	}
+	if _, ok := ses.cachedStmts[sql]; ok {
+		return "", true
+	}
+	if len(ses.pinnedStmts)+len(ses.cachedStmts) >= ses.stmtCacheSize {
+		return "", false
+	}
+	if ses.cachedStmts == nil {
+		ses.cachedStmts = make(map[string]struct{})
+	}
+	ses.cachedStmts[sql] = struct{}{}
+	return "", true
+}
+
+// growStmtCache sets the statement cache size (OCI_ATTR_STMTCACHESIZE)
+// to n, if it is smaller, and records it for stmtCacheKey.
+func (ses *Ses) growStmtCache(n int) error {
+	var size C.ub4
+	ses.Lock()
+	defer ses.Unlock()
+	env := ses.Env()
+	r := C.OCIAttrGet(
+		unsafe.Pointer(ses.ocisvcctx), //const void     *trgthndlp,
+		C.OCI_HTYPE_SVCCTX,            //ub4            trghndltyp,
+		unsafe.Pointer(&size),         //void           *attributep,
+		nil,                           //ub4            *sizep,
+		C.OCI_ATTR_STMTCACHESIZE,      //ub4            attrtype,
+		env.ocierr)                    //OCIError       *errhp );
+	if r == C.OCI_ERROR {
+		return errE(env.ociError())
+	}
+	if int(size) >= n {
+		ses.stmtCacheSize = int(size)
+		return nil
+	}
+	size = C.ub4(n)
+	ses.stmtCacheSize = n
+	return env.setAttr(unsafe.Pointer(ses.ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&size), C.ub4(0), C.OCI_ATTR_STMTCACHESIZE)
+}
+
 // NumTx returns the number of open Oracle transactions.
 func (ses *Ses) NumTx() int {
 	ses.RLock()
@@ -823,12 +1503,169 @@ func (ses *Ses) NumTx() int {
 	return openTxs.len()
 }
 
+// InTransaction returns true when an explicit transaction started with StartTx
+// is open on the session; otherwise, false.
+//
+// Statements executed within an explicit transaction are not auto-committed,
+// regardless of StmtCfg.IsAutoCommitting.
+func (ses *Ses) InTransaction() bool {
+	return ses.NumTx() > 0
+}
+
 // IsOpen returns true when a session is open; otherwise, false.
 //
 // Calling Close will cause Ses.IsOpen to return false. Once closed, a session
 // cannot be re-opened. Call Srv.OpenSes to open a new session.
+//
+// IsOpen returns false after an error which means that the connection
+// is lost (see IsConnLost), too; such a session must be closed.
 func (ses *Ses) IsOpen() bool {
-	return ses.checkClosed() == nil
+	if ses.checkClosed() != nil {
+		return false
+	}
+	ses.RLock()
+	srv := ses.srv
+	ses.RUnlock()
+	return srv.IsOpen()
+}
+
+// setLostIf marks the Srv of ses as lost if err means that the connection
+// is lost, and returns err.
+//
+// The instance name of the session (see InstanceName) is added to err,
+// to tell which RAC node the failure is related to.
+func (ses *Ses) setLostIf(err error) error {
+	if ses == nil || err == nil {
+		return err
+	}
+	if inst, instErr := ses.InstanceName(); instErr == nil {
+		setErrInstance(err, inst)
+	}
+	ses.RLock()
+	srv := ses.srv
+	ses.RUnlock()
+	return srv.setLostIf(err)
+}
+
+// InstanceName returns the name of the database instance the session is
+// connected to (OCI_ATTR_INSTNAME), i.e. the RAC node serving it.
+//
+// It costs no round-trip, and needs Oracle Client 11.2 or later.
+func (ses *Ses) InstanceName() (string, error) {
+	return ses.serverAttr(&ses.instName, C.OCI_ATTR_INSTNAME)
+}
+
+// DBName returns the name of the database the session is connected to
+// (OCI_ATTR_DBNAME).
+//
+// It costs no round-trip, and needs Oracle Client 11.2 or later.
+func (ses *Ses) DBName() (string, error) {
+	return ses.serverAttr(&ses.dbName, C.OCI_ATTR_DBNAME)
+}
+
+// CurrentUser returns the name of the user the session runs as
+// (SYS_CONTEXT('USERENV', 'SESSION_USER')): with proxy authentication,
+// the client user, not the proxy.
+//
+// It costs a round-trip at the first call only.
+func (ses *Ses) CurrentUser() (string, error) {
+	if err := ses.loadUsers(); err != nil {
+		return "", err
+	}
+	ses.RLock()
+	defer ses.RUnlock()
+	return ses.sesUser, nil
+}
+
+// ProxyUser returns the name of the proxy user which opened the session
+// on behalf of CurrentUser (SYS_CONTEXT('USERENV', 'PROXY_USER')),
+// or an empty string without proxy authentication.
+//
+// It costs a round-trip at the first call only.
+func (ses *Ses) ProxyUser() (string, error) {
+	if err := ses.loadUsers(); err != nil {
+		return "", err
+	}
+	ses.RLock()
+	defer ses.RUnlock()
+	return ses.proxyUser, nil
+}
+
+// loadUsers queries the session and proxy user names, if not cached yet.
+func (ses *Ses) loadUsers() error {
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	ses.RLock()
+	loaded := ses.sesUser != ""
+	ses.RUnlock()
+	if loaded {
+		return nil
+	}
+	rset, err := ses.PrepAndQry("SELECT SYS_CONTEXT('USERENV', 'SESSION_USER'), SYS_CONTEXT('USERENV', 'PROXY_USER') FROM DUAL", OraS, OraS)
+	if err != nil {
+		return errE(err)
+	}
+	if !rset.Next() {
+		if err = rset.Err(); err == nil {
+			err = er("no session user.")
+		}
+		return errE(err)
+	}
+	sesUser, proxyUser := rset.Row[0].(String), rset.Row[1].(String)
+	for rset.Next() { // exhaust, to close the statement
+	}
+	ses.Lock()
+	ses.sesUser, ses.proxyUser = sesUser.Value, proxyUser.Value
+	ses.Unlock()
+	return nil
+}
+
+// serverAttr returns the text attribute of the server handle of the
+// session, cached in *cache.
+func (ses *Ses) serverAttr(cache *string, attr C.ub4) (string, error) {
+	if err := ses.checkClosed(); err != nil {
+		return "", errE(err)
+	}
+	if attr == 0 {
+		return "", er("needs Oracle Client 11.2 or later.")
+	}
+	ses.RLock()
+	s := *cache
+	ses.RUnlock()
+	if s != "" {
+		return s, nil
+	}
+	var ocisrv unsafe.Pointer
+	var value *C.OraText
+	var n C.ub4
+	ses.RLock()
+	env := ses.Env()
+	r := C.OCIAttrGet(
+		unsafe.Pointer(ses.ocisvcctx), //const void     *trgthndlp,
+		C.OCI_HTYPE_SVCCTX,            //ub4            trghndltyp,
+		unsafe.Pointer(&ocisrv),       //void           *attributep,
+		nil,                           //ub4            *sizep,
+		C.OCI_ATTR_SERVER,             //ub4            attrtype,
+		env.ocierr)                    //OCIError       *errhp );
+	if r != C.OCI_ERROR {
+		r = C.OCIAttrGet(
+			ocisrv,                 //const void     *trgthndlp,
+			C.OCI_HTYPE_SERVER,     //ub4            trghndltyp,
+			unsafe.Pointer(&value), //void           *attributep,
+			&n,                     //ub4            *sizep,
+			attr,                   //ub4            attrtype,
+			env.ocierr)             //OCIError       *errhp );
+	}
+	ses.RUnlock()
+	if r == C.OCI_ERROR {
+		return "", errE(env.ociError())
+	}
+	s = C.GoStringN((*C.char)(unsafe.Pointer(value)), C.int(n))
+	ses.Lock()
+	*cache = s
+	ses.Unlock()
+	return s, nil
 }
 
 // checkClosed returns an error if Ses is closed. No locking occurs.
@@ -940,17 +1777,88 @@ func (ses *Ses) SetAction(module, action string) error {
 	return nil
 }
 
+// CopyLob copies amount bytes (characters for CLOBs) of src, starting at the
+// 0-based srcOffset, to dst at dstOffset, entirely on the server side.
+// A non-positive amount copies src till its end.
+//
+// Both Lobs must have been fetched from the database, dst for update
+// (within a transaction, with SELECT ... FOR UPDATE).
+func (ses *Ses) CopyLob(dst, src *Lob, amount, dstOffset, srcOffset int64) error {
+	if dst == nil || src == nil {
+		return errNew("nil Lob")
+	}
+	if err := ses.checkClosed(); err != nil {
+		return errE(err)
+	}
+	dl, ok := dst.Reader.(lobLocator)
+	if !ok {
+		return errF("dst Lob (%T) has no LOB locator", dst.Reader)
+	}
+	sl, ok := src.Reader.(lobLocator)
+	if !ok {
+		return errF("src Lob (%T) has no LOB locator", src.Reader)
+	}
+	return dl.edit(func(_ *Ses, dstLoc *C.OCILobLocator) error {
+		if dl == sl { // the same LOB, already locked
+			return lobCopy(ses, dstLoc, dstLoc, amount, dstOffset, srcOffset)
+		}
+		return sl.edit(func(_ *Ses, srcLoc *C.OCILobLocator) error {
+			return lobCopy(ses, dstLoc, srcLoc, amount, dstOffset, srcOffset)
+		})
+	})
+}
+
+// GetModuleAction returns the MODULE and ACTION of the session, as seen by
+// the server (e.g. in V$SESSION), so they can be saved and restored around
+// a nested SetAction.
+//
+// It costs a server round-trip.
+func (ses *Ses) GetModuleAction() (module, action string, err error) {
+	stmt, err := ses.Prep("SELECT SYS_CONTEXT('USERENV', 'MODULE'), SYS_CONTEXT('USERENV', 'ACTION') FROM DUAL", S, S)
+	if err != nil {
+		return "", "", errE(err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	if err != nil {
+		return "", "", errE(err)
+	}
+	if !rset.Next() {
+		if err = rset.Err(); err == nil {
+			return "", "", er("no module returned from database")
+		}
+		return "", "", errE(err)
+	}
+	module, _ = rset.Row[0].(string)
+	action, _ = rset.Row[1].(string)
+	return module, action, nil
+}
+
+// setContainer switches the session to the pluggable database pdb,
+// which must be a valid identifier.
+func (ses *Ses) setContainer(pdb string) error {
+	if _, err := ses.PrepAndExe("ALTER SESSION SET CONTAINER = " + pdb); err != nil {
+		if cd, ok := err.(interface {
+			Code() int
+		}); ok && cd.Code() == 65011 {
+			return errE(&ORAError{
+				code:    65011,
+				prefix:  fmt.Sprintf("pluggable database %q does not exist", pdb),
+				message: err.Error(),
+			})
+		}
+		return errE(err)
+	}
+	return nil
+}
+
 // log writes a message with an Ses system name and caller info.
 func (ses *Ses) log(enabled bool, v ...interface{}) {
 	Log := _drv.Cfg().Log
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", ses.sysName(), callInfo(2))
-	} else {
-		Log.Logger.Infof("%v %v %v", ses.sysName(), callInfo(2), fmt.Sprint(v...))
-	}
+	Log.emit(LogEvent{Component: "Ses", Operation: callOp(2), SysName: ses.sysName(), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Ses system name and caller info.
@@ -959,9 +1867,9 @@ func (ses *Ses) logF(enabled bool, format string, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", ses.sysName(), callInfo(2))
-	} else {
-		Log.Logger.Infof("%v %v %v", ses.sysName(), callInfo(2), fmt.Sprintf(format, v...))
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Component: "Ses", Operation: callOp(2), SysName: ses.sysName(), Msg: msg})
 }
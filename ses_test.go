@@ -0,0 +1,49 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchInsertSql(t *testing.T) {
+	for n, want := range map[int]string{
+		1: "INSERT INTO t (a, b) VALUES (:1, :2)",
+		2: "INSERT ALL INTO t (a, b) VALUES (:1, :2) INTO t (a, b) VALUES (:3, :4) SELECT 1 FROM DUAL",
+	} {
+		if got := batchInsertSql("t", []string{"a", "b"}, n); got != want {
+			t.Errorf("%d: got %q, wanted %q", n, got, want)
+		}
+	}
+}
+
+func TestBatchColumns(t *testing.T) {
+	cols := []string{"a", "b", "c"}
+	params, ok := batchColumns(cols, [][]interface{}{
+		{1, "x", nil},
+		{nil, "y", nil},
+		{int64(3), nil, nil},
+	})
+	if !ok {
+		t.Fatal("not array-bindable")
+	}
+	want := []interface{}{
+		[]Int64{{Value: 1}, {IsNull: true}, {Value: 3}},
+		[]String{{Value: "x"}, {Value: "y"}, {IsNull: true}},
+		[]String{{IsNull: true}, {IsNull: true}, {IsNull: true}},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("got %#v, wanted %#v", params, want)
+	}
+	for _, rows := range [][][]interface{}{
+		{{1, "x", nil}, {"2", "y", nil}},
+		{{1, "x", Int64{}}},
+	} {
+		if _, ok := batchColumns(cols, rows); ok {
+			t.Errorf("%v: wanted not array-bindable", rows)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+/*
+#include <stdlib.h>
+#include <oci.h>
+#include "version.h"
+*/
+import "C"
+import "unsafe"
+
+// setShardingKey encodes the values as sharding key columns,
+// and sets them on the authInfo handle.
+//
+// The returned free func must be called after OCISessionGet.
+func (srv *Srv) setShardingKey(authInfo unsafe.Pointer, values []interface{}, isSuper bool) (free func(), err error) {
+	n := len(values)
+	if n == 0 {
+		return func() {}, nil
+	}
+	cols := (*[1 << 16]unsafe.Pointer)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(unsafe.Pointer(nil)))))[:n:n]
+	lengths := (*[1 << 16]C.ub4)(C.malloc(C.size_t(n) * C.sizeof_ub4))[:n:n]
+	types := (*[1 << 16]C.ub2)(C.malloc(C.size_t(n) * C.sizeof_ub2))[:n:n]
+	var key unsafe.Pointer
+	free = func() {
+		C.shardingKeyFree(key)
+		for _, p := range cols {
+			if p != nil {
+				C.free(p)
+			}
+		}
+		C.free(unsafe.Pointer(&cols[0]))
+		C.free(unsafe.Pointer(&lengths[0]))
+		C.free(unsafe.Pointer(&types[0]))
+	}
+	for i := range cols {
+		cols[i] = nil
+	}
+	for i, v := range values {
+		if cols[i], lengths[i], types[i], err = shardingKeyColumn(v); err != nil {
+			free()
+			return nil, err
+		}
+	}
+	var super C.int
+	if isSuper {
+		super = 1
+	}
+	r := C.shardingKeyAdd(
+		srv.env.ocienv, //OCIEnv     *env,
+		srv.env.ocierr, //OCIError   *err,
+		authInfo,       //void       *authInfo,
+		super,          //int        isSuper,
+		&cols[0],       //void       **values,
+		&lengths[0],    //ub4        *lengths,
+		&types[0],      //ub2        *types,
+		C.ub4(n),       //ub4        n,
+		&key)           //void       **keyp
+	switch r {
+	case C.OCI_SUCCESS, C.OCI_SUCCESS_WITH_INFO:
+		return free, nil
+	case C.OCI_INVALID_HANDLE:
+		free()
+		return nil, errNew("sharding keys need Oracle Client 12.2")
+	}
+	err = srv.env.ociError()
+	free()
+	return nil, err
+}
+
+// shardingKeyColumn returns the value in C memory, its length and type.
+func shardingKeyColumn(v interface{}) (unsafe.Pointer, C.ub4, C.ub2, error) {
+	switch x := v.(type) {
+	case string:
+		if x == "" {
+			break
+		}
+		return C.CBytes([]byte(x)), C.ub4(len(x)), C.SQLT_CHR, nil
+	case []byte:
+		if len(x) == 0 {
+			break
+		}
+		return C.CBytes(x), C.ub4(len(x)), C.SQLT_BIN, nil
+	case int:
+		return shardingKeyInt(int64(x))
+	case int32:
+		return shardingKeyInt(int64(x))
+	case int64:
+		return shardingKeyInt(x)
+	}
+	return nil, 0, 0, errF("unsupported sharding key column %#v (%T)", v, v)
+}
+
+func shardingKeyInt(i int64) (unsafe.Pointer, C.ub4, C.ub2, error) {
+	p := C.malloc(8)
+	*(*int64)(p) = i
+	return p, 8, C.SQLT_INT, nil
+}
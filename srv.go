@@ -12,8 +12,10 @@ import "C"
 import (
 	"container/list"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -25,12 +27,75 @@ type SrvCfg struct {
 
 	Pool PoolCfg
 
+	// ServerGroup specifies the server group (OCI_ATTR_SERVER_GROUP) of the
+	// connection, to route the sessions to a service (i.e. on RAC).
+	//
+	// Only observed for non-pooled (NoPool) connections: OpenSrv returns an
+	// error if it is set with another Pool.Type.
+	ServerGroup string
+
+	// CompatMode makes OpenSes skip the optional session attributes
+	// (driver name, default LOB prefetch size, statement cache size),
+	// for old (i.e. 10g) servers which don't support them.
+	//
+	// The optional attributes are set on a best-effort basis even without
+	// CompatMode: failures are logged, and do not fail OpenSes.
+	//
+	// The default is false.
+	CompatMode bool
+
+	// StmtCacheSize is the number of statements cached by OCI per session,
+	// keyed by the SQL text, so preparing the same SQL again reuses the
+	// cached statement. With a session pool (SPool, DRCPool), the cache
+	// stays with the pooled session, and is reused when the session is
+	// handed out again.
+	//
+	// The default is 0, which disables the statement cache.
+	StmtCacheSize uint32
+
+	// PDBName is the pluggable database to switch the new sessions to, with
+	// "ALTER SESSION SET CONTAINER", in a multitenant container database.
+	// It must be a simple (unquoted) identifier; a nonexistent PDB fails
+	// OpenSes with ORA-65011.
+	//
+	// The default is "", which keeps the container of the connection.
+	PDBName string
+
+	// ConnectTimeout limits the time OpenSrv waits for the connection to the
+	// server (OCIServerAttach), i.e. for an unreachable listener. After the
+	// timeout, OCIBreak is called on the server handle, and OpenSrv returns
+	// a *ConnectTimeoutError without waiting for the attach any longer; the
+	// abandoned attach is detached and freed when it returns, and Env.Close
+	// waits for it.
+	//
+	// It is independent of the statement timeouts, and only observed for
+	// non-pooled (NoPool) connections.
+	//
+	// The default is 0, which means no timeout.
+	ConnectTimeout time.Duration
+
 	// StmtCfg configures new Stmts.
 	StmtCfg
 }
 
 func (c SrvCfg) IsZero() bool { return c.StmtCfg.IsZero() }
 
+// ConnectTimeoutError is returned by OpenSrv when the connection to the
+// server is not established within SrvCfg.ConnectTimeout.
+//
+// After is the SrvCfg.ConnectTimeout which elapsed.
+type ConnectTimeoutError struct {
+	Dblink string
+	After  time.Duration
+}
+
+func (e *ConnectTimeoutError) Error() string {
+	return fmt.Sprintf("ora: connect to %q timed out after %s", e.Dblink, e.After)
+}
+
+// Timeout reports whether the error is a timeout, like net.Error.
+func (e *ConnectTimeoutError) Timeout() bool { return true }
+
 // LogSrvCfg represents Srv logging configuration values.
 type LogSrvCfg struct {
 	// Close determines whether the Srv.Close method is logged.
@@ -68,6 +133,10 @@ type Srv struct {
 	env    *Env
 	ocisrv *C.OCIServer
 	isUTF8 int32
+	// lost is set when the connection to the server is lost
+	lost int32
+	// major is the cached major version of the server, see majorVersion
+	major int32
 
 	ocipool        unsafe.Pointer
 	ociPoolName    *C.OraText
@@ -151,6 +220,7 @@ func (srv *Srv) close() (err error) {
 		srv.ociPoolName = nil
 		srv.ociPoolNameLen = 0
 		srv.poolType = NoPool
+		atomic.StoreInt32(&srv.major, 0)
 		srv.Unlock()
 		_drv.srvPool.Put(srv)
 
@@ -220,6 +290,17 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 
 	credentialType := C.ub4(C.OCI_CRED_EXT)
 
+	if cfg.Tag != "" && srv.poolType != SPool && srv.poolType != DRCPool {
+		return nil, er("session tags need a session pool (SPool or DRCPool).")
+	}
+
+	compatMode := srv.Cfg().CompatMode
+	pdbName := srv.Cfg().PDBName
+	if pdbName != "" {
+		if err = checkIdentifier(pdbName); err != nil {
+			return nil, errE(err)
+		}
+	}
 	var ocises, authInfo unsafe.Pointer
 	poolType := NoPool
 	if (srv.poolType == CPool && cfg.Mode != SysDba && cfg.Mode != SysOper) ||
@@ -230,7 +311,23 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 		}
 		credentialType = C.OCI_SESSGET_CREDEXT
 		ocises = authInfo
+		for _, sk := range []struct {
+			values  []interface{}
+			isSuper bool
+		}{{cfg.ShardingKey, false}, {cfg.SuperShardingKey, true}} {
+			if len(sk.values) == 0 {
+				continue
+			}
+			free, err := srv.setShardingKey(authInfo, sk.values, sk.isSuper)
+			if err != nil {
+				return nil, errE(err)
+			}
+			defer free()
+		}
 	} else {
+		if len(cfg.ShardingKey) != 0 || len(cfg.SuperShardingKey) != 0 {
+			return nil, er("sharding keys need a session pool (CPool, SPool or DRCPool).")
+		}
 		if err = srv.checkClosed(); err != nil {
 			return nil, errE(err)
 		}
@@ -240,24 +337,26 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 		}
 
 		//srv.logF(true, "CRED_EXT? %t username=%q", credentialType == C.OCI_CRED_EXT, username)
-		// set driver name on the session handle
-		// driver name is specified to aid diagnostics; max 9 single-byte characters
-		// driver name will be visible in V$SESSION_CONNECT_INFO or GV$SESSION_CONNECT_INFO as CLIENT_DRIVER
-		drvName := fmt.Sprintf("GO%s", Version)
-		cDrvName := C.CString(drvName)
-		defer C.free(unsafe.Pointer(cDrvName))
-		if err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION,
-			unsafe.Pointer(cDrvName), C.ub4(len(drvName)), C.OCI_ATTR_DRIVER_NAME,
-		); err != nil {
-			return nil, errE(err)
-		}
-		// http://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci07lob.htm#CHDDHFAB
-		// Set LOB prefetch size to chunk size
-		lobPrefetchSize := C.ub4(lobChunkSize)
-		if err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION,
-			unsafe.Pointer(&lobPrefetchSize), C.ub4(0), C.OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE,
-		); err != nil {
-			return nil, errE(err)
+		if !compatMode {
+			// set driver name on the session handle
+			// driver name is specified to aid diagnostics; max 9 single-byte characters
+			// driver name will be visible in V$SESSION_CONNECT_INFO or GV$SESSION_CONNECT_INFO as CLIENT_DRIVER
+			drvName := fmt.Sprintf("GO%s", Version)
+			cDrvName := C.CString(drvName)
+			defer C.free(unsafe.Pointer(cDrvName))
+			if err := srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION,
+				unsafe.Pointer(cDrvName), C.ub4(len(drvName)), C.OCI_ATTR_DRIVER_NAME,
+			); err != nil {
+				srv.logF(_drv.Cfg().Log.Srv.OpenSes, "skip driver name: %v", err)
+			}
+			// http://docs.oracle.com/cd/B28359_01/appdev.111/b28395/oci07lob.htm#CHDDHFAB
+			// Set LOB prefetch size to chunk size
+			lobPrefetchSize := C.ub4(lobChunkSize)
+			if err := srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION,
+				unsafe.Pointer(&lobPrefetchSize), C.ub4(0), C.OCI_ATTR_DEFAULT_LOBPREFETCH_SIZE,
+			); err != nil {
+				srv.logF(_drv.Cfg().Log.Srv.OpenSes, "skip LOB prefetch size: %v", err)
+			}
 		}
 	}
 
@@ -283,6 +382,20 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 		}
 	}
 
+	if cfg.Edition != "" {
+		if err = checkIdentifier(cfg.Edition); err != nil {
+			return nil, errE(err)
+		}
+		// unquoted identifiers are stored in upper case
+		edition := strings.ToUpper(cfg.Edition)
+		cEdition := C.CString(edition)
+		defer C.free(unsafe.Pointer(cEdition))
+		err = srv.env.setAttr(ocises, C.OCI_HTYPE_SESSION, unsafe.Pointer(cEdition), C.ub4(len(edition)), C.OCI_ATTR_EDITION)
+		if err != nil {
+			return nil, errE(err)
+		}
+	}
+
 	// allocate service context handle
 	ocisvcctx, err := srv.env.allocOciHandle(C.OCI_HTYPE_SVCCTX)
 	if err != nil {
@@ -315,8 +428,16 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 		}
 	}
 
+	var tagFound C.boolean
 	switch poolType {
 	case CPool, SPool, DRCPool:
+		var cTag *C.OraText
+		var retTag *C.OraText
+		var retTagLen C.ub4
+		if cfg.Tag != "" {
+			cTag = (*C.OraText)(unsafe.Pointer(C.CString(cfg.Tag)))
+			defer C.free(unsafe.Pointer(cTag))
+		}
 		srv.RLock()
 		r = C.OCISessionGet(
 			srv.env.ocienv,                              //OCIEnv    *envhp,
@@ -325,11 +446,11 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 			(*C.OCIAuthInfo)(authInfo),                  //OCIAuthInfo       *authInfop,
 			srv.ociPoolName,                             //OraText           *dbName,
 			srv.ociPoolNameLen,                          //ub4               dbName_len,
-			nil,                                         //CONST OraText     *tagInfo,
-			0,                                           //ub4               tagInfo_len,
-			nil,                                         //OraText           **retTagInfo,
-			nil,                                         //ub4               *retTagInfo_len,
-			nil,                                         //boolean           *found,
+			cTag,                                        //CONST OraText     *tagInfo,
+			C.ub4(len(cfg.Tag)),                         //ub4               tagInfo_len,
+			&retTag,                                     //OraText           **retTagInfo,
+			&retTagLen,                                  //ub4               *retTagInfo_len,
+			&tagFound,                                   //boolean           *found,
 			mode,                                        //ub4           mode );
 		)
 		srv.RUnlock()
@@ -366,28 +487,39 @@ func (srv *Srv) OpenSes(cfg SesCfg) (ses *Ses, err error) {
 			return nil, errE(err)
 		}
 	}
-	// set stmt cache size to zero
-	// https://docs.oracle.com/database/121/LNOCI/oci09adv.htm#LNOCI16655
-	stmtCacheSize := C.ub4(0)
-	err = srv.env.setAttr(unsafe.Pointer(ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&stmtCacheSize), C.ub4(0), C.OCI_ATTR_STMTCACHESIZE)
-	if err != nil {
-		return nil, errE(err)
+	if !compatMode {
+		// set stmt cache size, zero by default
+		// https://docs.oracle.com/database/121/LNOCI/oci09adv.htm#LNOCI16655
+		stmtCacheSize := C.ub4(srv.Cfg().StmtCacheSize)
+		if err := srv.env.setAttr(unsafe.Pointer(ocisvcctx), C.OCI_HTYPE_SVCCTX, unsafe.Pointer(&stmtCacheSize), C.ub4(0), C.OCI_ATTR_STMTCACHESIZE); err != nil {
+			srv.logF(_drv.Cfg().Log.Srv.OpenSes, "skip statement cache size: %v", err)
+		}
 	}
 
 	ses = _drv.sesPool.Get().(*Ses) // set *Ses
 	ses.cmu.Lock()
-	defer ses.cmu.Unlock()
 	ses.Lock()
 	ses.env.Store(srv.env)
 	ses.srv = srv
 	ses.ocisvcctx = (*C.OCISvcCtx)(ocisvcctx)
 	ses.ocises = (*C.OCISession)(ocises)
+	ses.tagFound = tagFound == C.TRUE
+	ses.releaseTag = ""
+	ses.openedAt = time.Now()
 	if ses.id == 0 {
 		ses.id = _drv.sesId.nextId()
 	}
 	ses.Unlock()
 	ses.SetCfg(cfg)
 	srv.openSess.add(ses)
+	ses.cmu.Unlock()
+
+	if pdbName != "" {
+		if err = ses.setContainer(pdbName); err != nil {
+			ses.Close()
+			return nil, err
+		}
+	}
 
 	return ses, nil
 }
@@ -416,6 +548,34 @@ func (srv *Srv) Version() (ver string, err error) {
 	return C.GoString(&buf[0]), nil
 }
 
+// majorVersion returns the major version of the server (e.g. 12 or 23),
+// or 0 if it cannot be determined.
+//
+// It requires the server have at least one open session.
+func (srv *Srv) majorVersion() int {
+	if major := atomic.LoadInt32(&srv.major); major != 0 {
+		return int(major)
+	}
+	var buf [512]C.char
+	var release C.ub4
+	srv.RLock()
+	r := C.OCIServerRelease(
+		unsafe.Pointer(srv.ocisrv),            //void         *hndlp,
+		srv.env.ocierr,                        //OCIError     *errhp,
+		(*C.OraText)(unsafe.Pointer(&buf[0])), //OraText      *bufp,
+		C.ub4(len(buf)),                       //ub4          bufsz,
+		C.OCI_HTYPE_SERVER,                    //ub1          hndltype,
+		&release)                              //ub4          *version );
+	srv.RUnlock()
+	if r == C.OCI_ERROR {
+		srv.logF(_drv.Cfg().Log.Srv.Version, "OCIServerRelease: %v", srv.env.ociError())
+		return 0
+	}
+	major := int32(release>>24) & 0xFF
+	atomic.StoreInt32(&srv.major, major)
+	return int(major)
+}
+
 // NumSes returns the number of open Oracle sessions.
 func (srv *Srv) NumSes() int {
 	if srv == nil {
@@ -439,8 +599,22 @@ func (srv *Srv) IsUTF8() bool {
 //
 // Calling Close will cause Srv.IsOpen to return false. Once closed, a server cannot
 // be re-opened. Call Env.OpenSrv to open a new server.
+//
+// IsOpen returns false after an error which means that the connection
+// is lost (see IsConnLost), too; such a server must be closed.
 func (srv *Srv) IsOpen() bool {
-	return srv.checkClosed() == nil
+	return srv.checkClosed() == nil && atomic.LoadInt32(&srv.lost) == 0
+}
+
+// setLostIf marks srv as lost if err means that the connection is lost,
+// and returns err.
+func (srv *Srv) setLostIf(err error) error {
+	if srv != nil && IsConnLost(err) {
+		if atomic.CompareAndSwapInt32(&srv.lost, 0, 1) {
+			srv.logF(_drv.Cfg().Log.Srv.Close, "connection lost: %v", err)
+		}
+	}
+	return err
 }
 
 // checkClosed returns an error if Srv is closed. No locking occurs.
@@ -472,11 +646,7 @@ func (srv *Srv) log(enabled bool, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", srv.sysName(), callInfo(1))
-	} else {
-		Log.Logger.Infof("%v %v %v", srv.sysName(), callInfo(1), fmt.Sprint(v...))
-	}
+	Log.emit(LogEvent{Component: "Srv", Operation: callOp(1), SysName: srv.sysName(), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Srv system name and caller info.
@@ -485,9 +655,9 @@ func (srv *Srv) logF(enabled bool, format string, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", srv.sysName(), callInfo(1))
-	} else {
-		Log.Logger.Infof("%v %v %v", srv.sysName(), callInfo(1), fmt.Sprintf(format, v...))
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Component: "Srv", Operation: callOp(1), SysName: srv.sysName(), Msg: msg})
 }
@@ -14,7 +14,9 @@ import (
 	"bytes"
 	"container/list"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"strings"
 	"sync"
@@ -56,11 +58,22 @@ func NewLogStmtCfg() LogStmtCfg {
 	return c
 }
 
+// ErrStmtInUse is returned when a Stmt is executed while another goroutine
+// is executing it.
+var ErrStmtInUse = errors.New("ora: Stmt is being executed by another goroutine")
+
 // Stmt represents an Oracle statement.
+//
+// The bind buffers are stored in the Stmt, so a Stmt must not be executed
+// from several goroutines at once: Exe and Qry return ErrStmtInUse instead.
+// Prepare a Stmt for each goroutine to execute the same SQL concurrently.
 type Stmt struct {
 	sync.RWMutex
 
 	id uint64
+	// rowsProcessed is the number of rows processed by the running
+	// execution, for RowsProcessedSoFar; 64-bit aligned for atomic access
+	rowsProcessed uint64
 	// protects that open/close should not happen at once
 	cmu                 sync.Mutex
 	cfg                 atomic.Value
@@ -72,7 +85,17 @@ type Stmt struct {
 	gcts                []GoColumnType
 	bnds                []bnd
 	hasPtrBind          bool
+	hasLastInsertId     bool
+	isForUpdate         bool
+	hasBlockRowCount    bool // wrapped by wrapBlockRowCount
+	cacheKey            string // statement cache key, for Ses.PinStmt
+	uncached            bool   // not to be put back into the statement cache
+	isReturning         bool
 	stringPtrBufferSize int
+	executing           int32 // guards against concurrent executions
+	// stale is set when an execution failed as a dependent object has been
+	// invalidated, to purge the statement from the statement cache on close
+	stale bool
 	bindInfo
 
 	openRsets *rsetList
@@ -155,13 +178,24 @@ func (stmt *Stmt) close() (err error) {
 		// See https://docs.oracle.com/database/121/LNOCI/oci09adv.htm#LNOCI16655
 		stmt.Lock()
 		env := stmt.Env()
+		mode := C.ub4(C.OCI_DEFAULT)
+		if stmt.stale || stmt.uncached { // purge it from the statement cache
+			mode = C.OCI_STRLS_CACHE_DELETE
+		}
+		var cKey *C.OraText
+		if stmt.cacheKey != "" {
+			cKey = (*C.OraText)(unsafe.Pointer(C.CString(stmt.cacheKey)))
+		}
 		r := C.OCIStmtRelease(
-			stmt.ocistmt,  // OCIStmt        *stmthp
-			env.ocierr,    // OCIError       *errhp,
-			nil,           // const OraText  *key
-			C.ub4(0),      // ub4 keylen
-			C.OCI_DEFAULT, // ub4 mode
+			stmt.ocistmt,              // OCIStmt        *stmthp
+			env.ocierr,                // OCIError       *errhp,
+			cKey,                      // const OraText  *key
+			C.ub4(len(stmt.cacheKey)), // ub4 keylen
+			mode,                      // ub4 mode
 		)
+		if cKey != nil {
+			C.free(unsafe.Pointer(cKey))
+		}
 		stmt.Unlock()
 		if r == C.OCI_ERROR {
 			errs.PushBack(errE(env.ociError()))
@@ -178,6 +212,12 @@ func (stmt *Stmt) close() (err error) {
 		stmt.gcts = nil
 		stmt.bnds = nil
 		stmt.hasPtrBind = false
+		stmt.hasLastInsertId = false
+		stmt.isForUpdate = false
+		stmt.hasBlockRowCount = false
+		stmt.cacheKey, stmt.uncached = "", false
+		stmt.isReturning = false
+		stmt.stale = false
 		stmt.bindInfo = bindInfo{}
 		stmt.openRsets.clear()
 		_drv.stmtPool.Put(stmt)
@@ -218,6 +258,153 @@ func (stmt *Stmt) Exe(params ...interface{}) (rowsAffected uint64, err error) {
 	return rowsAffected, err
 }
 
+// RowsProcessedSoFar returns the number of rows processed by the running
+// (or the last) execution of the statement. It is safe to call from another
+// goroutine while Exe or ExeContext is in flight.
+//
+// OCI reports the row count (OCI_ATTR_UB8_ROW_COUNT) only when an execution
+// returns, so the count is updated after each chunk of an array DML executed
+// with a WithProgress context, and stays 0 during a single-row UPDATE or
+// DELETE until it finishes; the server-side progress of such a statement is
+// shown by V$SESSION_LONGOPS.
+func (stmt *Stmt) RowsProcessedSoFar() uint64 {
+	return atomic.LoadUint64(&stmt.rowsProcessed)
+}
+
+// ExeNoCommit executes a SQL statement like Exe, but without committing it,
+// even if StmtCfg.IsAutoCommitting is set (OCI_DEFAULT execution mode), and
+// without counting it towards CommitEvery.
+//
+// The changes are committed by the next auto-committed execution on the
+// session, or Ses.Commit, so a statement can be chained with a follow-up
+// without starting a transaction with StartTx.
+func (stmt *Stmt) ExeNoCommit(params ...interface{}) (rowsAffected uint64, err error) {
+	ctx := context.WithValue(context.Background(), noCommitKey, true)
+	rowsAffected, _, err = stmt.exeC(ctx, params, false)
+	return rowsAffected, err
+}
+
+// ExeContext executes a SQL statement like Exe, but breaks the execution
+// (OCIBreak) when ctx is cancelled.
+//
+// If a DML is cancelled when it is auto-committed (there's no transaction
+// started with StartTx), the rows it has applied already (i.e. the first
+// iterations of an array DML) are rolled back, with the uncommitted
+// executions of a CommitEvery batch (reported by a *BatchRollbackError).
+// Inside a transaction, rolling back is the caller's responsibility.
+//
+// Array DML is executed in chunks, reporting progress, with a ctx
+// returned by WithProgress.
+func (stmt *Stmt) ExeContext(ctx context.Context, params ...interface{}) (rowsAffected uint64, err error) {
+	if stmt == nil {
+		return 0, er("stmt may not be nil.")
+	}
+	if err = ctx.Err(); err != nil {
+		return 0, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			stmt.RLock()
+			ses := stmt.ses
+			stmt.RUnlock()
+			ses.Break()
+		}
+	}()
+	rowsAffected, _, err = stmt.exeC(ctx, params, false)
+	return rowsAffected, err
+}
+
+// ExeMerge executes a PL/SQL block which merges rows, returning the number of
+// inserted and updated rows separately.
+//
+// A MERGE reports only the total number of merged rows in SQL%ROWCOUNT, so
+// the block counts the rows of the two branches itself, and assigns them to
+// the :ora_inserted and :ora_updated placeholders, which ExeMerge binds as
+// OUT parameters. The exact pattern is a MERGE per branch, each followed by
+// its SQL%ROWCOUNT:
+//
+//	BEGIN
+//	  MERGE INTO target t USING (SELECT :1 id, :2 val FROM DUAL) s
+//	    ON (t.id = s.id)
+//	    WHEN MATCHED THEN UPDATE SET t.val = s.val;
+//	  :ora_updated := SQL%ROWCOUNT;
+//	  MERGE INTO target t USING (SELECT :1 id, :2 val FROM DUAL) s
+//	    ON (t.id = s.id)
+//	    WHEN NOT MATCHED THEN INSERT (id, val) VALUES (s.id, s.val);
+//	  :ora_inserted := SQL%ROWCOUNT;
+//	END;
+//
+// The params are bound to the other placeholders, in order; as in any PL/SQL
+// block, a placeholder name used more than once is bound once.
+func (stmt *Stmt) ExeMerge(params ...interface{}) (inserted, updated uint64, err error) {
+	if stmt == nil {
+		return 0, 0, er("stmt may not be nil.")
+	}
+	if err = stmt.checkClosed(); err != nil {
+		return 0, 0, errE(err)
+	}
+	stmt.RLock()
+	stmtType := stmt.stmtType
+	stmt.RUnlock()
+	if stmtType != C.OCI_STMT_BEGIN && stmtType != C.OCI_STMT_DECLARE {
+		return 0, 0, errF("ExeMerge needs a PL/SQL block, not statement type %d.", stmtType)
+	}
+	bindNames, _, duplicates, err := stmt.getBindInfo()
+	if err != nil {
+		return 0, 0, errE(err)
+	}
+	var ins, upd int64
+	args, err := mergeCountArgs(bindNames, duplicates, params, &ins, &upd)
+	if err != nil {
+		return 0, 0, errE(err)
+	}
+	if _, err = stmt.Exe(args...); err != nil {
+		return 0, 0, errE(err)
+	}
+	return uint64(ins), uint64(upd), nil
+}
+
+// The placeholders of the ExeMerge counts.
+const (
+	mergeInsertedName = "ora_inserted"
+	mergeUpdatedName  = "ora_updated"
+)
+
+// mergeCountArgs returns the arguments of the distinct placeholders of an
+// ExeMerge block: inserted and updated for the count placeholders, and the
+// params, in order, for the others.
+func mergeCountArgs(bindNames []string, duplicates []bool, params []interface{}, inserted, updated *int64) ([]interface{}, error) {
+	args := make([]interface{}, 0, len(params)+2)
+	var hasInserted, hasUpdated bool
+	for i, name := range bindNames {
+		if duplicates[i] {
+			continue
+		}
+		switch {
+		case strings.EqualFold(name, mergeInsertedName):
+			args, hasInserted = append(args, inserted), true
+		case strings.EqualFold(name, mergeUpdatedName):
+			args, hasUpdated = append(args, updated), true
+		default:
+			if len(params) == 0 {
+				return nil, errF("no parameter for placeholder :%s", name)
+			}
+			args, params = append(args, params[0]), params[1:]
+		}
+	}
+	if !hasInserted || !hasUpdated {
+		return nil, errF("the block must assign the :%s and :%s placeholders", mergeInsertedName, mergeUpdatedName)
+	}
+	if len(params) > 0 {
+		return nil, errF("%d parameters more than placeholders", len(params))
+	}
+	return args, nil
+}
+
 // ExeP executes an (PL/)SQL statement on an Oracle server returning the number of
 // rows affected and a possible error.
 //
@@ -227,6 +414,161 @@ func (stmt *Stmt) ExeP(params ...interface{}) (rowsAffected uint64, err error) {
 	return rowsAffected, err
 }
 
+// ExeStructs executes the statement for each element of rows in a single
+// batch: rows must be a slice of structs (or of pointers to structs),
+// and each field is bound as an array (a slice) of its values.
+//
+// The exported fields are bound in declaration order, unless a field has
+// an `ora:"name"` tag: then each placeholder (e.g. :name) is bound to the
+// field with the same tag, or (without a tag) the same name,
+// case-insensitively. Fields tagged `ora:"-"` are skipped.
+//
+// The fields must have a type which can be bound as a slice, such as int64,
+// float64, string, time.Time, or the nullable types (ora.Int64, ora.String...).
+func (stmt *Stmt) ExeStructs(rows interface{}) (rowsAffected uint64, err error) {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		return 0, errF("rows is %T, not a slice of structs", rows)
+	}
+	et := rv.Type().Elem()
+	isPtr := et.Kind() == reflect.Ptr
+	if isPtr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return 0, errF("rows is %T, not a slice of structs", rows)
+	}
+	if rv.Len() == 0 {
+		return 0, nil
+	}
+	fields, err := stmt.structBindFields(et)
+	if err != nil {
+		return 0, err
+	}
+	params := make([]interface{}, len(fields))
+	for i, f := range fields {
+		col := reflect.MakeSlice(reflect.SliceOf(et.Field(f).Type), rv.Len(), rv.Len())
+		for j := 0; j < rv.Len(); j++ {
+			row := rv.Index(j)
+			if isPtr {
+				if row.IsNil() {
+					return 0, errF("rows[%d] is nil", j)
+				}
+				row = row.Elem()
+			}
+			col.Index(j).Set(row.Field(f))
+		}
+		params[i] = col.Interface()
+	}
+	rowsAffected, _, err = stmt.exe(params, false)
+	return rowsAffected, err
+}
+
+// structBindFields returns the indexes of the fields of the struct type t,
+// in the order of the binds, as described at ExeStructs.
+func (stmt *Stmt) structBindFields(t reflect.Type) ([]int, error) {
+	var fields []int
+	names := make(map[string]int, t.NumField())
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("ora"); tag == "-" {
+			continue
+		} else if tag != "" {
+			name, tagged = tag, true
+		}
+		fields = append(fields, i)
+		names[strings.ToUpper(name)] = i
+	}
+	if !tagged {
+		return fields, nil
+	}
+	bindNames, _, duplicates, err := stmt.getBindInfo()
+	if err != nil {
+		return nil, err
+	}
+	fields = fields[:0]
+	for i, bindName := range bindNames {
+		if duplicates[i] {
+			continue
+		}
+		f, ok := names[strings.ToUpper(bindName)]
+		if !ok {
+			return nil, errF("no field of %s for placeholder :%s", t, bindName)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// ExeReturningResults executes a PL/SQL block like ExeP, and returns the
+// cursors it has returned with DBMS_SQL.RETURN_RESULT (implicit results)
+// as Rsets, in the order they have been returned.
+//
+// The Rsets belong to the Stmt: they're closed with it, so keep the Stmt
+// open while reading them.
+//
+// Implicit results require Oracle 12.1 or later; with older clients
+// no Rsets are returned.
+func (stmt *Stmt) ExeReturningResults(params ...interface{}) (rowsAffected uint64, rsets []*Rset, err error) {
+	rowsAffected, _, err = stmt.exe(params, true)
+	if err != nil {
+		return rowsAffected, nil, err
+	}
+	rsets, err = stmt.implicitResults()
+	if err != nil {
+		for _, rset := range rsets {
+			rset.closeWithRemove()
+		}
+		return rowsAffected, nil, errE(err)
+	}
+	return rowsAffected, rsets, nil
+}
+
+// implicitResults opens the implicit results of the executed statement.
+func (stmt *Stmt) implicitResults() (rsets []*Rset, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	env := stmt.Env()
+	for {
+		var result unsafe.Pointer
+		var isSelect C.ub4
+		stmt.RLock()
+		r := C.nextImplicitResult(
+			stmt.ocistmt, //OCIStmt     *stmtp,
+			env.ocierr,   //OCIError    *err,
+			&result,      //void        **result,
+			&isSelect)    //ub4         *isSelect
+		stmt.RUnlock()
+		if r == C.OCI_NO_DATA {
+			return rsets, nil
+		}
+		if r == C.OCI_ERROR {
+			return rsets, env.ociError("OCIStmtGetNextResult")
+		}
+		if isSelect == 0 {
+			continue
+		}
+		// the result handle is owned by stmt, and freed with it
+		rset := &Rset{env: env, id: _drv.rsetId.nextId()}
+		if err = rset.open(stmt, (*C.OCIStmt)(result)); err != nil {
+			rset.close()
+			return rsets, err
+		}
+		stmt.RLock()
+		stmt.openRsets.add(rset)
+		stmt.RUnlock()
+		rsets = append(rsets, rset)
+	}
+}
+
 // Parse the statement, and return the syntax errors - WITHOUT executing it.
 // Rejects ALTER statements, as they're executed anyway by Oracle...
 func (stmt *Stmt) Parse() (err error) {
@@ -246,7 +588,70 @@ func (stmt *Stmt) Parse() (err error) {
 	if stmt.stmtType == C.OCI_STMT_ALTER || stmt.stmtType == 0 {
 		return er("parsing ALTER statement is perilous!")
 	}
-	// Execute statement on Oracle server
+	if _, err = stmt.parseOnly(1); err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// Validate makes the server parse and check the statement, without
+// executing it or doing any work (OCI_PARSE_ONLY with zero iterations).
+//
+// The returned *ParseError holds the offset of the error in the statement
+// text. Rejects DDL (CREATE, DROP, ALTER) statements, as Oracle executes
+// them on parse.
+//
+// Contrary to Rset describing, no select-list metadata is returned.
+func (stmt *Stmt) Validate() (err error) {
+	if stmt == nil {
+		return er("stmt may not be nil.")
+	}
+	defer func() {
+		if value := recover(); value != nil {
+			err = errR(value)
+		}
+	}()
+	stmt.log(_drv.Cfg().Log.Stmt.Exe)
+	err = stmt.checkClosed()
+	if err != nil {
+		return errE(err)
+	}
+	switch stmt.stmtType {
+	case 0, C.OCI_STMT_CREATE, C.OCI_STMT_DROP, C.OCI_STMT_ALTER:
+		return errF("validating DDL (statement type %d) would execute it", stmt.stmtType)
+	}
+	offset, err := stmt.parseOnly(0)
+	if err != nil {
+		return &ParseError{Err: err, Offset: offset}
+	}
+	return nil
+}
+
+// ParseError is returned by Stmt.Validate.
+type ParseError struct {
+	Err error
+	// Offset is the position of the error in the statement text,
+	// as reported by OCI_ATTR_PARSE_ERROR_OFFSET.
+	Offset int
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%v (at offset %d)", e.Err, e.Offset)
+}
+
+// Code returns the Oracle error code of the underlying error.
+func (e *ParseError) Code() int {
+	if cd, ok := e.Err.(interface {
+		Code() int
+	}); ok {
+		return cd.Code()
+	}
+	return 0
+}
+
+// parseOnly executes the statement in OCI_PARSE_ONLY mode, returning
+// the parse error offset on error.
+func (stmt *Stmt) parseOnly(iters C.ub4) (offset int, err error) {
 	stmt.RLock()
 	env := stmt.Env()
 	stmt.ses.RLock()
@@ -254,21 +659,84 @@ func (stmt *Stmt) Parse() (err error) {
 		stmt.ses.ocisvcctx, //OCISvcCtx           *svchp,
 		stmt.ocistmt,       //OCIStmt             *stmtp,
 		env.ocierr,         //OCIError            *errhp,
-		C.ub4(1),           //ub4                 iters,
+		iters,              //ub4                 iters,
 		C.ub4(0),           //ub4                 rowoff,
 		nil,                //const OCISnapshot   *snap_in,
 		nil,                //OCISnapshot         *snap_out,
 		C.OCI_PARSE_ONLY)   //ub4                 mode );
 	stmt.ses.RUnlock()
 	stmt.RUnlock()
-	if r == C.OCI_ERROR {
-		return errE(env.ociError())
+	if r != C.OCI_ERROR {
+		return 0, nil
 	}
-	return nil
+	var errOffset C.ub2
+	C.OCIAttrGet(
+		unsafe.Pointer(env.ocierr),    //const void     *trgthndlp,
+		C.OCI_HTYPE_ERROR,             //ub4            trghndltyp,
+		unsafe.Pointer(&errOffset),    //void           *attributep,
+		nil,                           //ub4            *sizep,
+		C.OCI_ATTR_PARSE_ERROR_OFFSET, //ub4            attrtype,
+		env.ocierr)                    //OCIError       *errhp );
+	return int(errOffset), env.ociError()
 }
 
 var spcRpl = strings.NewReplacer("\t", " ", "   ", " ", "  ", " ")
 
+// isLastInsertIdMarked reports whether the INSERT statement returns
+// the identity with a "RETURNING ... /*LASTINSERTID*/ INTO" clause.
+func isLastInsertIdMarked(sql string) bool {
+	lastIndex := strings.LastIndex(sql, ")")
+	sqlEnd := strings.ToUpper(spcRpl.Replace(sql[lastIndex+1:]))
+	i := strings.LastIndex(sqlEnd, "RETURNING")
+	return i >= 0 && strings.Contains(sqlEnd[i:], " /*LASTINSERTID*/ INTO ")
+}
+
+// isForUpdate reports whether the query has a FOR UPDATE clause.
+func isForUpdate(sql string) bool {
+	upper := strings.ToUpper(spcRpl.Replace(strings.Replace(sql, "\n", " ", -1)))
+	return strings.Contains(upper, " FOR UPDATE")
+}
+
+// isPLSQLBlock reports whether sql is an anonymous PL/SQL block.
+func isPLSQLBlock(sql string) bool {
+	upper := strings.ToUpper(strings.TrimSpace(sql))
+	for _, kw := range []string{"BEGIN", "DECLARE"} {
+		if strings.HasPrefix(upper, kw) && len(upper) > len(kw) &&
+			strings.ContainsRune(" \t\r\n", rune(upper[len(kw)])) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockRowCountName is the placeholder of SQL%ROWCOUNT added by
+// wrapBlockRowCount.
+const blockRowCountName = "ora_rowcount"
+
+// wrapBlockRowCount wraps the PL/SQL block into another one, which returns
+// SQL%ROWCOUNT after it in its last placeholder (see StmtCfg.BlockRowCount).
+func wrapBlockRowCount(sql string) string {
+	// new lines, to end a trailing comment
+	return "BEGIN\n" + sql + "\n:" + blockRowCountName + " := SQL%ROWCOUNT;\nEND;"
+}
+
+// appendFetchFirst appends a "FETCH FIRST n ROWS ONLY" clause to a query,
+// if it has no row limiting or FOR UPDATE clause already.
+func appendFetchFirst(sql string, n int) string {
+	upper := strings.ToUpper(spcRpl.Replace(strings.Replace(sql, "\n", " ", -1)))
+	trimmed := strings.TrimSpace(upper)
+	if !strings.HasPrefix(trimmed, "SELECT") && !strings.HasPrefix(trimmed, "WITH") {
+		return sql
+	}
+	for _, clause := range []string{" FETCH FIRST ", " FETCH NEXT ", " OFFSET ", " FOR UPDATE"} {
+		if strings.Contains(upper, clause) {
+			return sql
+		}
+	}
+	// new line, to end a trailing comment
+	return fmt.Sprintf("%s\nFETCH FIRST %d ROWS ONLY", sql, n)
+}
+
 // exe executes a SQL statement on an Oracle server returning rowsAffected, lastInsertId and error.
 func (stmt *Stmt) exe(params []interface{}, isAssocArray bool) (rowsAffected uint64, lastInsertId int64, err error) {
 	return stmt.exeC(context.Background(), params, isAssocArray)
@@ -280,11 +748,16 @@ func (stmt *Stmt) exeC(ctx context.Context, params []interface{}, isAssocArray b
 	if err = ctx.Err(); err != nil {
 		return
 	}
+	if err = stmt.acquire(); err != nil {
+		return 0, 0, err
+	}
+	defer stmt.release()
 	defer func() {
 		if value := recover(); value != nil {
 			err = errR(value)
 		}
 	}()
+	atomic.StoreUint64(&stmt.rowsProcessed, 0)
 	stmt.log(_drv.Cfg().Log.Stmt.Exe)
 	err = stmt.checkClosed()
 	if err != nil {
@@ -295,89 +768,348 @@ func (stmt *Stmt) exeC(ctx context.Context, params []interface{}, isAssocArray b
 	}
 	// for case of inserting and returning identity for database/sql package
 	stmt.RLock()
-	pkgEnvInsert := stmt.Env().isPkgEnv && stmt.stmtType == C.OCI_STMT_INSERT
+	pkgEnvInsert := stmt.hasLastInsertId && stmt.Env().isPkgEnv
+	hasBlockRowCount := stmt.hasBlockRowCount
 	stmt.RUnlock()
-	if pkgEnvInsert {
-		lastIndex := strings.LastIndex(stmt.sql, ")")
-		sqlEnd := spcRpl.Replace(stmt.sql[lastIndex+1 : len(stmt.sql)])
-		sqlEnd = strings.ToUpper(sqlEnd)
+	if pkgEnvInsert && stmt.Cfg().DetectLastInsertId {
 		// add *int64 arg to capture identity
-		if i := strings.LastIndex(sqlEnd, "RETURNING"); i >= 0 && strings.Contains(sqlEnd[i:], " /*LASTINSERTID*/ INTO ") {
-			params[len(params)-1] = &lastInsertId
-		}
+		params[len(params)-1] = &lastInsertId
+	}
+	var blockRowCount Int64
+	if hasBlockRowCount {
+		// the last placeholder is SQL%ROWCOUNT; don't touch the caller's slice
+		params = append(params[:len(params):len(params)], &blockRowCount)
 	}
-	iterations, err := stmt.bind(params, isAssocArray) // bind parameters
+	iterations, err := stmt.bind(ctx, params, isAssocArray) // bind parameters
 	if err != nil {
 		return 0, 0, errE(err)
 	}
 	if stmt.stmtType == C.OCI_STMT_SELECT {
-		err = stmt.setPrefetchSize() // set prefetch size
+		err = stmt.setPrefetchSize(ctx) // set prefetch size
 		if err != nil {
 			return 0, 0, errE(err)
 		}
 	}
 	mode := C.ub4(C.OCI_DEFAULT) // determine auto-commit state; don't auto-comit if there's an explicit user transaction occuring
-	var autoCommit bool
-	if stmt.Cfg().IsAutoCommitting {
+	var autoCommit, batchCommit bool
+	if stmt.Cfg().IsAutoCommitting && !ctxNoCommit(ctx) {
 		stmt.RLock()
 		n := stmt.ses.openTxs.len()
 		stmt.RUnlock()
 		if n == 0 {
-			mode = C.OCI_COMMIT_ON_SUCCESS
-			autoCommit = true
+			if atomic.LoadInt32(&stmt.ses.commitEvery) > 1 { // commit in batches
+				batchCommit = true
+			} else {
+				mode = C.OCI_COMMIT_ON_SUCCESS
+				autoCommit = true
+			}
 		}
 	}
-	stmt.logF(_drv.Cfg().Log.Stmt.Exe, "iterations=%d autoCommit=%t", iterations, autoCommit)
-	// Execute statement on Oracle server
+	stmt.logF(_drv.Cfg().Log.Stmt.Exe, "iterations=%d autoCommit=%t batchCommit=%t", iterations, autoCommit, batchCommit)
 	stmt.RLock()
 	env := stmt.Env()
-	stmt.ses.RLock()
-	r := C.OCIStmtExecute(
-		stmt.ses.ocisvcctx, //OCISvcCtx           *svchp,
-		stmt.ocistmt,       //OCIStmt             *stmtp,
-		env.ocierr,         //OCIError            *errhp,
-		C.ub4(iterations),  //ub4                 iters,
-		C.ub4(0),           //ub4                 rowoff,
-		nil,                //const OCISnapshot   *snap_in,
-		nil,                //OCISnapshot         *snap_out,
-		mode)               //ub4                 mode );
-	stmt.ses.RUnlock()
 	stmtType, hasPtrBind := stmt.stmtType, stmt.hasPtrBind
 	stmt.RUnlock()
-	stmt.logF(_drv.Cfg().Log.Stmt.Exe, "returned %d, hasPtrBind=%t", r, hasPtrBind)
-	if r == C.OCI_ERROR {
-		return 0, 0, errE(env.ociError())
+	// Execute statement on Oracle server, in chunks with WithProgress:
+	// the chunks execute parts of the bound arrays (by rowoff),
+	// and only the last one commits.
+	chunk := iterations
+	every, progress := ctxProgress(ctx)
+	chunked := progress != nil && every > 0 && isDML(stmtType) && !hasPtrBind
+	if chunked && every < iterations {
+		chunk = every
 	}
-	// Get rowsAffected based on statement type
-	switch stmtType {
-	case C.OCI_STMT_SELECT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT:
-		ra, err := stmt.attr(C.ROW_COUNT_LENGTH, C.OCI_ATTR_UB8_ROW_COUNT)
-		if err != nil {
+	for rowoff := uint32(0); ; rowoff += chunk {
+		iters, chunkMode := chunk, mode
+		last := rowoff+chunk >= iterations
+		if last {
+			iters = iterations - rowoff
+		} else {
+			chunkMode = C.OCI_DEFAULT
+		}
+		stmt.RLock()
+		stmt.ses.RLock()
+		r := C.OCIStmtExecute(
+			stmt.ses.ocisvcctx, //OCISvcCtx           *svchp,
+			stmt.ocistmt,       //OCIStmt             *stmtp,
+			env.ocierr,         //OCIError            *errhp,
+			C.ub4(iters),       //ub4                 iters,
+			C.ub4(rowoff),      //ub4                 rowoff,
+			nil,                //const OCISnapshot   *snap_in,
+			nil,                //OCISnapshot         *snap_out,
+			chunkMode)          //ub4                 mode );
+		stmt.ses.RUnlock()
+		stmt.RUnlock()
+		stmt.logF(_drv.Cfg().Log.Stmt.Exe, "returned %d, hasPtrBind=%t", r, hasPtrBind)
+		if r == C.OCI_ERROR {
+			err = stmt.setStaleIf(stmt.ses.setLostIf(env.ociError()))
+			if (autoCommit || batchCommit) && isDML(stmtType) && (ctx.Err() != nil || rowoff > 0) {
+				// cancelled, or a later chunk failed: roll back the partially
+				// applied DML, as there's no explicit transaction to do it
+				err = stmt.rollbackExe(err)
+			}
+			if ctxErr := stmt.canceledErr(ctx, err); ctxErr != nil {
+				return 0, 0, ctxErr
+			}
 			return 0, 0, errE(err)
 		}
-		rowsAffected = uint64(*((*C.ROW_COUNT_TYPE)(ra)))
-		C.free(ra)
-		//case C.OCI_STMT_CREATE, C.OCI_STMT_DROP, C.OCI_STMT_ALTER, C.OCI_STMT_BEGIN:
-	default:
-		if r == C.OCI_NO_DATA {
-			return 0, 0, errE(env.ociError())
+		// Get rowsAffected based on statement type
+		switch stmtType {
+		case C.OCI_STMT_SELECT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE, C.OCI_STMT_INSERT:
+			ra, err := stmt.attr(C.ROW_COUNT_LENGTH, C.OCI_ATTR_UB8_ROW_COUNT)
+			if err != nil {
+				return 0, 0, errE(err)
+			}
+			rowsAffected += uint64(*((*C.ROW_COUNT_TYPE)(ra)))
+			C.free(ra)
+			atomic.StoreUint64(&stmt.rowsProcessed, rowsAffected)
+			//case C.OCI_STMT_CREATE, C.OCI_STMT_DROP, C.OCI_STMT_ALTER, C.OCI_STMT_BEGIN:
+		default:
+			if r == C.OCI_NO_DATA {
+				return 0, 0, errE(env.ociError())
+			}
+			//fmt.Printf("stmtType=%d\n", stmt.stmtType)
+		}
+		if !chunked {
+			break
+		}
+		progress(int(rowoff+iters), int(iterations))
+		if last {
+			break
+		}
+		if err = ctx.Err(); err != nil {
+			if autoCommit || batchCommit {
+				err = stmt.rollbackExe(err)
+			}
+			return 0, 0, err
+		}
+	}
+	if batchCommit {
+		if err = stmt.ses.batchCommit(rowsAffected); err != nil {
+			return rowsAffected, lastInsertId, errE(err)
 		}
-		//fmt.Printf("stmtType=%d\n", stmt.stmtType)
 	}
-	if hasPtrBind { // Set any bind pointers
+	if hasPtrBind && (stmt.IsReturning() || !isDML(stmtType)) { // Set any bind pointers
 		err = stmt.setBindPtrs()
 		if err != nil {
 			return rowsAffected, lastInsertId, errE(err)
 		}
 	}
+	if hasBlockRowCount && !blockRowCount.IsNull {
+		rowsAffected = uint64(blockRowCount.Value)
+	}
 	return rowsAffected, lastInsertId, nil
 }
 
+// rollbackExe rolls back the partially applied execution which failed with
+// err, with the executions pending due to CommitEvery, returning err, or a
+// *BatchRollbackError if pending rows have been lost.
+func (stmt *Stmt) rollbackExe(err error) error {
+	lost := atomic.LoadUint64(&stmt.ses.uncommittedRows)
+	if rbErr := stmt.ses.rollback(); rbErr != nil {
+		stmt.logF(_drv.Cfg().Log.Stmt.Exe, "rollback after cancel: %v", rbErr)
+		return err
+	}
+	if lost == 0 {
+		return err
+	}
+	return &BatchRollbackError{Err: err, Rows: lost}
+}
+
+// setStaleIf marks stmt as stale if err means that an object the statement
+// depends on has been invalidated (i.e. by DDL) or recompiled, so Close
+// purges it from the statement cache, and the next Prep of the same SQL
+// prepares it anew. Returns err.
+func (stmt *Stmt) setStaleIf(err error) error {
+	if !isStaleErr(err) {
+		return err
+	}
+	stmt.Lock()
+	stmt.stale = true
+	stmt.Unlock()
+	return err
+}
+
+// isStaleErr reports whether err is ORA-04061 (existing state has been
+// invalidated), ORA-04062 (timestamp or signature has been changed),
+// ORA-04065 (not executed, altered or dropped) or ORA-04068 (existing state
+// of packages has been discarded).
+func isStaleErr(err error) bool {
+	cd, ok := err.(interface {
+		Code() int
+	})
+	if !ok {
+		return false
+	}
+	switch cd.Code() {
+	case 4061, 4062, 4065, 4068:
+		return true
+	}
+	return false
+}
+
+// canceledErr returns ctx.Err() if err is ORA-01013 (user requested cancel),
+// caused by a Break as ctx is done, unless the KeepCancelErr of the
+// statement's config is set; otherwise nil.
+func (stmt *Stmt) canceledErr(ctx context.Context, err error) error {
+	if stmt.Cfg().KeepCancelErr {
+		return nil
+	}
+	return ctxCancelErr(ctx, err)
+}
+
+// ctxCancelErr returns ctx.Err() if err is ORA-01013 and ctx is done;
+// otherwise nil.
+func ctxCancelErr(ctx context.Context, err error) error {
+	cd, ok := err.(interface {
+		Code() int
+	})
+	if !ok || cd.Code() != 1013 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// IsReturning reports whether the statement is a DML with a RETURNING clause.
+//
+// With Oracle Client older than 12.1, this can't be determined,
+// so every INSERT, UPDATE and DELETE is reported as returning.
+func (stmt *Stmt) IsReturning() bool {
+	stmt.RLock()
+	defer stmt.RUnlock()
+	return stmt.isReturning
+}
+
+// acquire marks the statement as executing, or returns ErrStmtInUse
+// if another goroutine is executing it.
+func (stmt *Stmt) acquire() error {
+	if !atomic.CompareAndSwapInt32(&stmt.executing, 0, 1) {
+		return ErrStmtInUse
+	}
+	return nil
+}
+
+// release marks the end of the execution started by acquire.
+func (stmt *Stmt) release() {
+	atomic.StoreInt32(&stmt.executing, 0)
+}
+
+// isDML reports whether the statement type is INSERT, UPDATE or DELETE.
+func isDML(stmtType C.ub2) bool {
+	switch stmtType {
+	case C.OCI_STMT_INSERT, C.OCI_STMT_UPDATE, C.OCI_STMT_DELETE:
+		return true
+	}
+	return false
+}
+
+// readIsReturning reads the OCI_ATTR_STMT_IS_RETURNING of DML statements.
+func (stmt *Stmt) readIsReturning() (bool, error) {
+	if !isDML(stmt.stmtType) {
+		return false, nil
+	}
+	if C.OCI_ATTR_STMT_IS_RETURNING == 0 { // unknown
+		return true, nil
+	}
+	p, err := stmt.attr(1, C.OCI_ATTR_STMT_IS_RETURNING)
+	if err != nil {
+		return true, err
+	}
+	isReturning := *((*C.ub1)(p)) != 0
+	C.free(p)
+	return isReturning, nil
+}
+
 // Qry runs a SQL query on an Oracle server returning a *Rset and possible error.
 func (stmt *Stmt) Qry(params ...interface{}) (*Rset, error) {
 	return stmt.qry(params)
 }
 
+// QryWithLockRetry runs a SELECT ... FOR UPDATE NOWAIT query, like Qry,
+// but retries it on ORA-00054 (resource busy), at most attempts times,
+// sleeping backoff before the first retry, and doubling it before each next.
+//
+// The ORA-00054 error is returned only after the last attempt.
+func (stmt *Stmt) QryWithLockRetry(attempts int, backoff time.Duration, params ...interface{}) (*Rset, error) {
+	for i := 1; ; i++ {
+		rset, err := stmt.qry(params)
+		if err == nil || i >= attempts {
+			return rset, err
+		}
+		if cd, ok := err.(interface {
+			Code() int
+		}); !ok || cd.Code() != 54 {
+			return rset, err
+		}
+		stmt.logF(_drv.Cfg().Log.Stmt.Qry, "ORA-00054, retry %d/%d in %s", i, attempts, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// QryPage runs the query of the statement for one page of its rows, like Qry:
+// at most limit rows, after skipping the first offset rows.
+//
+// It prepares a new statement with an
+// "OFFSET :ora_off ROWS FETCH NEXT :ora_lim ROWS ONLY" clause appended to the
+// query and the GoColumnTypes of the statement, and binds offset and limit
+// after params. The new statement is closed
+// with the returned Rset. The query should have an ORDER BY clause, for the
+// pages to be stable.
+//
+// The row limiting clause requires Oracle 12c or later; an error is returned
+// for older servers.
+func (stmt *Stmt) QryPage(offset, limit int, params ...interface{}) (*Rset, error) {
+	if stmt == nil {
+		return nil, er("stmt may not be nil.")
+	}
+	if offset < 0 || limit <= 0 {
+		return nil, errF("invalid page (offset=%d, limit=%d).", offset, limit)
+	}
+	if err := stmt.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	stmt.RLock()
+	ses, sql, stmtType, gcts := stmt.ses, stmt.sql, stmt.stmtType, stmt.gcts
+	stmt.RUnlock()
+	if stmtType != C.OCI_STMT_SELECT {
+		return nil, er("QryPage requires a query.")
+	}
+	if major := ses.srv.majorVersion(); major != 0 && major < 12 {
+		return nil, errF("QryPage requires Oracle 12c or later, the server is %d.", major)
+	}
+	pageStmt, err := ses.Prep(pageSql(sql), gcts...)
+	if err != nil {
+		return nil, errE(err)
+	}
+	pageStmt.SetCfg(stmt.Cfg())
+	args := make([]interface{}, 0, len(params)+2)
+	args = append(append(args, params...), int64(offset), int64(limit))
+	rset, err := pageStmt.Qry(args...)
+	if err != nil {
+		pageStmt.Close()
+		return nil, errE(err)
+	}
+	rset.autoClose = true
+	return rset, nil
+}
+
+// pageSql appends the row limiting clause of QryPage to the query.
+func pageSql(sql string) string {
+	// new line, to end a trailing comment
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(sql), ";")) +
+		"\nOFFSET :ora_off ROWS FETCH NEXT :ora_lim ROWS ONLY"
+}
+
+// QryContext runs a SQL query like Qry, with the options of ctx
+// (i.e. WithStmtCfg, WithPrefetch, WithCharset).
+func (stmt *Stmt) QryContext(ctx context.Context, params ...interface{}) (*Rset, error) {
+	if stmt == nil {
+		return nil, er("stmt may not be nil.")
+	}
+	return stmt.qryC(ctx, params)
+}
+
 // qry runs a SQL query on an Oracle server returning a *Rset and possible error.
 func (stmt *Stmt) qry(params []interface{}) (rset *Rset, err error) {
 	return stmt.qryC(context.Background(), params)
@@ -392,6 +1124,10 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
+	if err := stmt.acquire(); err != nil {
+		return nil, err
+	}
+	defer stmt.release()
 	if cfg, ok := ctxStmtCfg(ctx); ok {
 		stmt.SetCfg(cfg)
 	}
@@ -399,11 +1135,15 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	if err != nil {
 		return nil, errE(err)
 	}
-	_, err = stmt.bind(params, false) // bind parameters
+	charsetID, err := stmt.Env().charsetID(ctxCharset(ctx))
+	if err != nil {
+		return nil, errE(err)
+	}
+	_, err = stmt.bind(ctx, params, false) // bind parameters
 	if err != nil {
 		return nil, errE(err)
 	}
-	err = stmt.setPrefetchSize() // set prefetch size
+	err = stmt.setPrefetchSize(ctx) // set prefetch size
 	if err != nil {
 		return nil, errE(err)
 	}
@@ -425,7 +1165,11 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	hasPtrBind := stmt.hasPtrBind
 	stmt.RUnlock()
 	if r == C.OCI_ERROR {
-		return nil, errE(env.ociError())
+		err = stmt.setStaleIf(stmt.ses.setLostIf(env.ociError()))
+		if ctxErr := stmt.canceledErr(ctx, err); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, errE(err)
 	}
 	if hasPtrBind { // set any bind pointers
 		err = stmt.setBindPtrs()
@@ -442,6 +1186,7 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	if rset.id == 0 {
 		rset.id = _drv.rsetId.nextId()
 	}
+	rset.charsetID = charsetID
 	//rset.Unlock()
 	err = rset.open(stmt, stmt.ocistmt)
 	if err != nil {
@@ -455,6 +1200,44 @@ func (stmt *Stmt) qryC(ctx context.Context, params []interface{}) (rset *Rset, e
 	return rset, nil
 }
 
+// ResetBinds closes the binds of the previous execution, returning them to
+// their pools, so the next execution starts with fresh binds.
+//
+// Each execution calls it before binding its parameters, thus the values
+// returned through pointer binds (i.e. the Reader of a *Lob) are valid only
+// till the next execution of the statement.
+func (stmt *Stmt) ResetBinds() error {
+	if err := stmt.checkClosed(); err != nil {
+		return errE(err)
+	}
+	if err := stmt.acquire(); err != nil {
+		return err
+	}
+	defer stmt.release()
+	stmt.Lock()
+	defer stmt.Unlock()
+	if err := stmt.resetBinds(); err != nil {
+		return errE(err)
+	}
+	return nil
+}
+
+// resetBinds closes the binds, and clears hasPtrBind. No locking occurs.
+func (stmt *Stmt) resetBinds() (err error) {
+	for i, bind := range stmt.bnds {
+		if bind == nil {
+			continue
+		}
+		if closeErr := bind.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		stmt.bnds[i] = nil
+	}
+	stmt.bnds = stmt.bnds[:0]
+	stmt.hasPtrBind = false
+	return err
+}
+
 // setBindPtrs enables binds to set out pointers for some types such as time.Time, etc.
 func (stmt *Stmt) setBindPtrs() (err error) {
 	stmt.RLock()
@@ -489,8 +1272,15 @@ func (stmt *Stmt) putBnd(idx int, bnd bnd) {
 // or an array or slice of pointers to builtin value types.
 //
 // No locking occurs.
-func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint32, err error) {
+func (stmt *Stmt) bind(ctx context.Context, params []interface{}, isAssocArray bool) (iterations uint32, err error) {
 	stmt.logF(_drv.Cfg().Log.Stmt.Bind, "Params %d", len(params))
+	// close the binds of the previous execution
+	stmt.Lock()
+	if resetErr := stmt.resetBinds(); resetErr != nil {
+		stmt.logF(_drv.Cfg().Log.Stmt.Bind, "reset binds: %+v", resetErr)
+	}
+	bnds := stmt.bnds
+	stmt.Unlock()
 	// Create binds for each parameter; bind position is 1-based
 	if len(params) == 0 {
 		return 1, nil
@@ -502,9 +1292,6 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 		}
 	}()
 	iterations = 1
-	stmt.RLock()
-	bnds := stmt.bnds
-	stmt.RUnlock()
 	if cap(bnds) < len(params) {
 		bnds = make([]bnd, len(params))
 	} else {
@@ -517,6 +1304,22 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 		name, v := nameAndValue(params[n])
 		pos := namedPos{Ordinal: n + 1, Name: name}
 		//stmt.logF(_drv.Cfg().Log.Stmt.Bind, "params[%d]=(%v %T)", n, params[n], params[n])
+		var inOut *bndInOut
+		if inout, ok := v.(InOut); ok {
+			if v, inOut, err = inout.bindValue(); err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
+		}
+		if values, ok := v.([]interface{}); ok {
+			// bind as []T, if all the elements are T
+			if typed, ok := typedSlice(values); ok {
+				v = typed
+			}
+		} else if basic, ok := basicValue(v); ok {
+			// e.g. type Status int, by its underlying kind
+			v = basic
+		}
 		switch value := v.(type) {
 		case int64:
 			bnd := stmt.getBnd(bndIdxInt64).(*bndInt64)
@@ -947,7 +1750,7 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 						stmt.setNilBind(n, C.SQLT_BLOB)
 					} else {
 						bnds[n] = bnd
-						err = bnd.bindReader(bytes.NewReader(value), pos, stmt.Cfg().lobBufferSize, C.SQLT_BLOB, stmt)
+						err = bnd.bindReader(bytes.NewReader(value), pos, stmt.Cfg().lobBufferSize, C.SQLT_BLOB, 0, stmt)
 						if err != nil {
 							return iterations, err
 						}
@@ -1161,6 +1964,30 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			if err != nil {
 				return iterations, err
 			}
+		case Timestamp:
+			bnd := stmt.getBnd(bndIdxTime).(*bndTime)
+			bnds[n] = bnd
+			if err = bnd.bindAs(value.Time, C.SQLT_TIMESTAMP, pos, stmt); err != nil {
+				return iterations, err
+			}
+		case TimestampTZ:
+			bnd := stmt.getBnd(bndIdxTime).(*bndTime)
+			bnds[n] = bnd
+			if err = bnd.bindAs(value.Time, C.SQLT_TIMESTAMP_TZ, pos, stmt); err != nil {
+				return iterations, err
+			}
+		case EpochSeconds:
+			bnd := stmt.getBnd(bndIdxTime).(*bndTime)
+			bnds[n] = bnd
+			if err = bnd.bind(value.Time(), pos, stmt); err != nil {
+				return iterations, err
+			}
+		case EpochMillis:
+			bnd := stmt.getBnd(bndIdxTime).(*bndTime)
+			bnds[n] = bnd
+			if err = bnd.bind(value.Time(), pos, stmt); err != nil {
+				return iterations, err
+			}
 		case *time.Time:
 			bnd := stmt.getBnd(bndIdxTimePtr).(*bndTimePtr)
 			bnds[n] = bnd
@@ -1222,6 +2049,19 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			if iterations, err = bnd.bindOra(&value, pos, stmt, isAssocArray); err != nil {
 				return iterations, err
 			}
+		case []*time.Time:
+			// as DATE array, with the nil elements as NULL
+			dates := make([]Date, len(value))
+			for i, t := range value {
+				if t != nil {
+					dates[i] = AsDate(*t)
+				}
+			}
+			bnd := stmt.getBnd(bndIdxDateSlice).(*bndDateSlice)
+			bnds[n] = bnd
+			if iterations, err = bnd.bindOra(&dates, pos, stmt, isAssocArray); err != nil {
+				return iterations, err
+			}
 		case *[]Date:
 			bnd := stmt.getBnd(bndIdxDateSlice).(*bndDateSlice)
 			bnds[n] = bnd
@@ -1278,6 +2118,33 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 			stmt.hasPtrBind = true
+		case Rowid:
+			bnd := stmt.getBnd(bndIdxString).(*bndString)
+			bnds[n] = bnd
+			if err = bnd.bind(string(value), pos, stmt); err != nil {
+				return iterations, err
+			}
+		case *Rowid:
+			// e.g. the target of "RETURNING ROWID INTO :r",
+			// with room for universal ROWIDs, too
+			bnd := stmt.getBnd(bndIdxStringPtr).(*bndStringPtr)
+			bnds[n] = bnd
+			err = bnd.bind((*string)(value), nil, pos, rowidMaxLen, stmt)
+			if err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
+		case []Rowid:
+			// input only, so the strings are not copied back
+			strs := make([]string, len(value))
+			for i, v := range value {
+				strs[i] = string(v)
+			}
+			bnd := stmt.getBnd(bndIdxStringSlice).(*bndStringSlice)
+			bnds[n] = bnd
+			if iterations, err = bnd.bind(&strs, pos, stmt, isAssocArray); err != nil {
+				return iterations, err
+			}
 		case []String:
 			bnd := stmt.getBnd(bndIdxStringSlice).(*bndStringSlice)
 			bnds[n] = bnd
@@ -1344,6 +2211,15 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			}
 			iterations = uint32(len(value))
 			stmt.hasPtrBind = true
+		case []*bool:
+			bnd := stmt.getBnd(bndIdxBoolSlice).(*bndBoolSlice)
+			bnds[n] = bnd
+			err = bnd.bindPtr(value, pos, stmt.Cfg().FalseRune, stmt.Cfg().TrueRune, stmt)
+			if err != nil {
+				return iterations, err
+			}
+			iterations = uint32(len(value))
+			stmt.hasPtrBind = true
 
 		case Raw:
 			if value.IsNull {
@@ -1363,10 +2239,27 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			}
 			if value.Reader == nil {
 				stmt.setNilBind(n, sqlt)
+			} else {
+				csid, err := stmt.ses.srv.env.charsetID(value.Charset)
+				if err != nil {
+					return iterations, err
+				}
+				bnd := stmt.getBnd(bndIdxLob).(*bndLob)
+				bnds[n] = bnd
+				err = bnd.bindReader(value.Reader, pos, stmt.Cfg().lobBufferSize, sqlt, csid, stmt)
+				if err != nil {
+					return iterations, err
+				}
+			}
+		case FileBlob:
+			if value.R == nil {
+				stmt.setNilBind(n, C.SQLT_BLOB)
 			} else {
 				bnd := stmt.getBnd(bndIdxLob).(*bndLob)
 				bnds[n] = bnd
-				err = bnd.bindReader(value.Reader, pos, stmt.Cfg().lobBufferSize, sqlt, stmt)
+				// stream it, stopping at the cancellation of ctx
+				rdr := ctxReader{ctx: ctx, Reader: io.NewSectionReader(value.R, 0, value.Size)}
+				err = bnd.bindReader(rdr, pos, stmt.Cfg().lobBufferSize, C.SQLT_BLOB, 0, stmt)
 				if err != nil {
 					return iterations, err
 				}
@@ -1379,9 +2272,13 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 			if value == nil {
 				stmt.setNilBind(n, sqlt)
 			} else {
+				csid, err := stmt.ses.srv.env.charsetID(value.Charset)
+				if err != nil {
+					return iterations, err
+				}
 				bnd := stmt.getBnd(bndIdxLobPtr).(*bndLobPtr)
 				bnds[n] = bnd
-				err = bnd.bindLob(value, pos, stmt.Cfg().lobBufferSize, sqlt, stmt)
+				err = bnd.bindLob(value, pos, stmt.Cfg().lobBufferSize, sqlt, csid, stmt)
 				if err != nil {
 					return iterations, err
 				}
@@ -1392,7 +2289,15 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 		case [][]byte:
 			bnd := stmt.getBnd(bndIdxBinSlice).(*bndBinSlice)
 			bnds[n] = bnd
-			iterations, err = bnd.bind(value, nil, pos, stmt.Cfg().lobBufferSize, stmt, isAssocArray)
+			iterations, err = bnd.bindBytes(value, pos, stmt.Cfg().lobBufferSize, stmt, isAssocArray)
+			if err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
+		case []BlobBytes:
+			bnd := stmt.getBnd(bndIdxLobSlice).(*bndLobSlice)
+			bnds[n] = bnd
+			iterations, err = bnd.bindBytes(value, pos, stmt.Cfg().lobBufferSize, stmt, isAssocArray)
 			if err != nil {
 				return iterations, err
 			}
@@ -1413,9 +2318,14 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				return iterations, err
 			}
 			stmt.hasPtrBind = true
-
-			// FIXME(tgulacsi): []*Lob ?
-
+		case []*Lob:
+			bnd := stmt.getBnd(bndIdxLobSlice).(*bndLobSlice)
+			bnds[n] = bnd
+			iterations, err = bnd.bindLobPtrs(value, pos, stmt.Cfg().lobBufferSize, stmt, isAssocArray)
+			if err != nil {
+				return iterations, err
+			}
+			stmt.hasPtrBind = true
 		case IntervalYM:
 			if value.IsNull {
 				stmt.setNilBind(n, C.SQLT_INTERVAL_YM)
@@ -1465,6 +2375,15 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 					return iterations, err
 				}
 			}
+		case InCursor:
+			if !value.Rset.IsOpen() {
+				return iterations, errF("InCursor needs an open Rset.")
+			}
+			bnd := stmt.getBnd(bndIdxRset).(*bndRset)
+			bnds[n] = bnd
+			if err = bnd.bindIn(value.Rset, pos, stmt); err != nil {
+				return iterations, err
+			}
 		case *Rset:
 			bnd := stmt.getBnd(bndIdxRset).(*bndRset)
 			bnds[n] = bnd
@@ -1482,16 +2401,111 @@ func (stmt *Stmt) bind(params []interface{}, isAssocArray bool) (iterations uint
 				t := reflect.TypeOf(v)
 				if t.Kind() == reflect.Slice &&
 					t.Elem().Kind() == reflect.Interface {
-					return iterations, errF("Invalid bind parameter. ([]interface{}) (%v).", v)
+					return iterations, errF("Invalid bind parameter. ([]interface{} with nil or different types of elements) (%v).", v)
 				}
 				return iterations, errF("Invalid bind parameter (%v) (%T:%v).", t.Name(), v, v)
 			}
 		}
+		if inOut != nil && bnds[n] != nil {
+			inOut.bnd = bnds[n]
+			bnds[n] = inOut
+		}
 	}
 
 	return iterations, err
 }
 
+// typedSlice converts values into a slice of their common concrete type,
+// e.g. []interface{}{int64(1), int64(2)} into []int64{1, 2}.
+//
+// ok is false if values is empty, or has nil or different types of elements.
+func typedSlice(values []interface{}) (typed interface{}, ok bool) {
+	if len(values) == 0 || values[0] == nil {
+		return nil, false
+	}
+	t := reflect.TypeOf(values[0])
+	s := reflect.MakeSlice(reflect.SliceOf(t), len(values), len(values))
+	for i, v := range values {
+		if v == nil || reflect.TypeOf(v) != t {
+			return nil, false
+		}
+		s.Index(i).Set(reflect.ValueOf(v))
+	}
+	return s.Interface(), true
+}
+
+// oraPkgPath is the import path of this package.
+var oraPkgPath = reflect.TypeOf(Rowid("")).PkgPath()
+
+// basicValue converts v of a defined type (e.g. type Status int) of
+// another package, or of type int or uint, into the basic type of its
+// underlying kind (int64, uint64, float64, string, bool...), as
+// database/sql does.
+//
+// ok is false for the other values, which are bound by their own type.
+// The defined types of the standard library are not converted either, as
+// their kind is not their meaning (e.g. time.Duration is not a number of
+// nanoseconds in the database).
+func basicValue(v interface{}) (basic interface{}, ok bool) {
+	if v == nil {
+		return nil, false
+	}
+	t := reflect.TypeOf(v)
+	k := t.Kind()
+	switch path := t.PkgPath(); {
+	case path == "":
+		if k != reflect.Int && k != reflect.Uint {
+			return nil, false
+		}
+	case path == oraPkgPath, isStdPkg(path):
+		return nil, false
+	}
+	return kindValue(reflect.ValueOf(v))
+}
+
+// isStdPkg reports whether path is the import path of a standard library
+// package: its first element has no dot, and it's not package main.
+func isStdPkg(path string) bool {
+	if path == "main" {
+		return false
+	}
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		path = path[:i]
+	}
+	return !strings.Contains(path, ".")
+}
+
+// kindValue returns rv as the basic type of its kind, see basicValue.
+func kindValue(rv reflect.Value) (basic interface{}, ok bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Int32:
+		return int32(rv.Int()), true
+	case reflect.Int16:
+		return int16(rv.Int()), true
+	case reflect.Int8:
+		return int8(rv.Int()), true
+	case reflect.Uint, reflect.Uint64:
+		return rv.Uint(), true
+	case reflect.Uint32:
+		return uint32(rv.Uint()), true
+	case reflect.Uint16:
+		return uint16(rv.Uint()), true
+	case reflect.Uint8:
+		return uint8(rv.Uint()), true
+	case reflect.Float64:
+		return rv.Float(), true
+	case reflect.Float32:
+		return float32(rv.Float()), true
+	case reflect.String:
+		return rv.String(), true
+	case reflect.Bool:
+		return rv.Bool(), true
+	}
+	return nil, false
+}
+
 // NumRset returns the number of open Oracle result sets.
 func (stmt *Stmt) NumRset() int {
 	stmt.RLock()
@@ -1502,6 +2516,35 @@ func (stmt *Stmt) NumRset() int {
 type bindInfo struct {
 	BindNames, IndNames []string
 	Duplicates          []bool
+
+	// numInput caches NumInput (database/sql calls it before each
+	// execution), as it's fixed till the statement is closed.
+	numInput    int
+	hasNumInput bool
+}
+
+// cachedNumInput returns the cached NumInput, or computes it with f,
+// and caches it if f succeeds.
+func (stmt *Stmt) cachedNumInput(f func() (int, error)) int {
+	stmt.RLock()
+	n, ok := stmt.numInput, stmt.hasNumInput
+	stmt.RUnlock()
+	if ok {
+		return n
+	}
+	n, err := f()
+	if err != nil {
+		return -1
+	}
+	stmt.RLock()
+	if stmt.hasBlockRowCount { // added by wrapBlockRowCount
+		n--
+	}
+	stmt.RUnlock()
+	stmt.Lock()
+	stmt.numInput, stmt.hasNumInput = n, true
+	stmt.Unlock()
+	return n
 }
 
 func (stmt *Stmt) getBindInfo() (bindNames, indNames []string, duplicates []bool, err error) {
@@ -1560,8 +2603,11 @@ func (stmt *Stmt) getBindInfo() (bindNames, indNames []string, duplicates []bool
 			duplicates = append(duplicates, dups[i] > 0)
 		}
 		if found >= 0 {
+			if bindNames == nil { // no binds: cache that, too
+				bindNames, indNames, duplicates = []string{}, []string{}, []bool{}
+			}
 			stmt.Lock()
-			stmt.bindInfo = bindInfo{BindNames: bindNames, IndNames: indNames, Duplicates: duplicates}
+			stmt.bindInfo.BindNames, stmt.bindInfo.IndNames, stmt.bindInfo.Duplicates = bindNames, indNames, duplicates
 			stmt.Unlock()
 			return
 		}
@@ -1569,6 +2615,58 @@ func (stmt *Stmt) getBindInfo() (bindNames, indNames []string, duplicates []bool
 	}
 }
 
+// BindDirections returns the direction of each distinct bind placeholder
+// of the statement, in the order of OCIStmtGetBindInfo.
+//
+// OCI reports the placeholder names only, so the directions are inferred
+// from the SQL text: see BindDir for the possible values. For the
+// arguments of PL/SQL procedure calls, the procedure is described with
+// Ses.DescribeObject, which resolves standalone procedures and functions
+// only: the arguments of packaged (or otherwise undescribable) procedures
+// are BindDirUnknown.
+func (stmt *Stmt) BindDirections() ([]BindDir, error) {
+	if err := stmt.checkClosed(); err != nil {
+		return nil, errE(err)
+	}
+	bindNames, _, duplicates, err := stmt.getBindInfo()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(bindNames))
+	for i, name := range bindNames {
+		if !duplicates[i] {
+			names = append(names, name)
+		}
+	}
+	stmt.RLock()
+	ses, sql, stmtType := stmt.ses, stmt.sql, stmt.stmtType
+	stmt.RUnlock()
+	isPLSQL := stmtType == C.OCI_STMT_BEGIN || stmtType == C.OCI_STMT_DECLARE
+	descs := make(map[string]*ObjectDescription)
+	argDir := func(call bindCall) BindDir {
+		desc, ok := descs[call.proc]
+		if !ok {
+			// not a standalone procedure (e.g. packaged, or a keyword)
+			desc, _ = ses.DescribeObject(call.proc)
+			descs[call.proc] = desc
+		}
+		if desc == nil {
+			return BindDirUnknown
+		}
+		for _, arg := range desc.Arguments {
+			if arg.Position == 0 { // return value of a function
+				continue
+			}
+			if call.arg != "" && strings.EqualFold(arg.Name, call.arg) ||
+				call.arg == "" && int(arg.Position) == call.pos {
+				return arg.Dir
+			}
+		}
+		return BindDirUnknown
+	}
+	return bindDirections(sql, names, isPLSQL, argDir), nil
+}
+
 // SetGcts sets a slice of GoColumnType used in a Stmt.Qry *ora.Rset.
 //
 // SetGcts is optional.
@@ -1627,14 +2725,11 @@ func (stmt *Stmt) sysName() string {
 
 // log writes a message with an Stmt system name and caller info.
 func (stmt *Stmt) log(enabled bool, v ...interface{}) {
-	if !_drv.Cfg().Log.IsEnabled(enabled) {
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		_drv.Cfg().Log.Logger.Infof("%v %v", stmt.sysName(), callInfo(1))
-	} else {
-		_drv.Cfg().Log.Logger.Infof("%v %v %v", stmt.sysName(), callInfo(1), fmt.Sprint(v...))
-	}
+	Log.emit(LogEvent{Component: "Stmt", Operation: callOp(1), SysName: stmt.sysName(), SQL: stmt.sql, Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Stmt system name and caller info.
@@ -1643,20 +2738,35 @@ func (stmt *Stmt) logF(enabled bool, format string, v ...interface{}) {
 	if !Log.IsEnabled(enabled) {
 		return
 	}
-	if len(v) == 0 {
-		Log.Logger.Infof("%v %v", stmt.sysName(), callInfo(1))
-	} else {
-		Log.Logger.Infof("%v %v %v", stmt.sysName(), callInfo(1), fmt.Sprintf(format, v...))
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Component: "Stmt", Operation: callOp(1), SysName: stmt.sysName(), SQL: stmt.sql, Msg: msg})
 }
 
 // set prefetch size. No locking occurs.
-func (stmt *Stmt) setPrefetchSize() error {
+//
+// The row count given with WithPrefetch in ctx overrides the StmtCfg.
+func (stmt *Stmt) setPrefetchSize(ctx context.Context) error {
 	cfg := stmt.Cfg()
-	if cfg.prefetchRowCount > 0 {
+	if stmt.noPrefetch(cfg) {
+		if err := stmt.setAttr(0, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
+			return errE(err)
+		}
+		if err := stmt.setAttr(0, C.OCI_ATTR_PREFETCH_MEMORY); err != nil {
+			return errE(err)
+		}
+		return nil
+	}
+	prefetchRowCount := cfg.prefetchRowCount
+	if n, ok := ctxPrefetchRowCount(ctx); ok {
+		prefetchRowCount = n
+	}
+	if prefetchRowCount > 0 {
 		//fmt.Println("stmt.setPrefetchSize: prefetchRowCount ", stmt.Cfg().prefetchRowCount)
 		// set prefetch row count
-		if err := stmt.setAttr(cfg.prefetchRowCount, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
+		if err := stmt.setAttr(prefetchRowCount, C.OCI_ATTR_PREFETCH_ROWS); err != nil {
 			return errE(err)
 		}
 	} else if cfg.prefetchMemorySize > 0 {
@@ -1669,6 +2779,15 @@ func (stmt *Stmt) setPrefetchSize() error {
 	return nil
 }
 
+// noPrefetch reports whether the query must be fetched row by row,
+// as StmtCfg.NoPrefetchForUpdate is set for a FOR UPDATE query.
+func (stmt *Stmt) noPrefetch(cfg StmtCfg) bool {
+	stmt.RLock()
+	isForUpdate := stmt.isForUpdate
+	stmt.RUnlock()
+	return isForUpdate && cfg.NoPrefetchForUpdate
+}
+
 // attr gets an attribute from the statement handle. No locking occurs.
 func (stmt *Stmt) attr(attrSize C.ub4, attrType C.ub4) (unsafe.Pointer, error) {
 	attrup := C.malloc(C.size_t(attrSize))
@@ -1709,6 +2828,28 @@ func (stmt *Stmt) setAttr(attrValue uint32, attrType C.ub4) error {
 	return nil
 }
 
+// setBindMaxDataSize sets the server-side size of the string bind
+// (OCI_ATTR_MAXDATA_SIZE) to size bytes, if StmtCfg.StringBindBytes is set.
+// No locking occurs.
+func (stmt *Stmt) setBindMaxDataSize(ocibnd *C.OCIBind, size int) error {
+	if size <= 0 || !stmt.Cfg().StringBindBytes {
+		return nil
+	}
+	env := stmt.ses.srv.env
+	maxDataSize := C.sb4(size)
+	if C.OCIAttrSet(
+		unsafe.Pointer(ocibnd),       //void        *trgthndlp,
+		C.OCI_HTYPE_BIND,             //ub4         trghndltyp,
+		unsafe.Pointer(&maxDataSize), //void        *attributep,
+		0,                            //ub4         size,
+		C.OCI_ATTR_MAXDATA_SIZE,      //ub4         attrtype,
+		env.ocierr,                   //OCIError    *errhp );
+	) == C.OCI_ERROR {
+		return env.ociError("OCI_ATTR_MAXDATA_SIZE")
+	}
+	return nil
+}
+
 // setNilBind sets a nil bind. No locking occurs.
 func (stmt *Stmt) setNilBind(index int, sqlt C.ub2) (err error) {
 	bnd := _drv.bndPools[bndIdxNil].Get().(*bndNil)
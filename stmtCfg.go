@@ -30,6 +30,8 @@ type StmtCfg struct {
 
 	// RTrimChar makes returning from CHAR colums trim the blanks (spaces)
 	// from the end of the string, added by Oracle.
+	// It applies to CHAR and NCHAR (SQLT_AFC) columns only,
+	// VARCHAR2 and NVARCHAR2 values are returned untouched.
 	//
 	// The default is true.
 	RTrimChar bool
@@ -46,6 +48,139 @@ type StmtCfg struct {
 	// The is default is '1'.
 	TrueRune rune
 
+	// DetectLastInsertId makes INSERT statements with a
+	// "RETURNING ... /*LASTINSERTID*/ INTO :x" clause return the inserted
+	// identity as LastInsertId, under the database/sql driver.
+	// The statement text is checked once, at prepare time.
+	//
+	// The default is true.
+	DetectLastInsertId bool
+
+	// ReuseRow makes Rset.Next reuse the Rset.Row backing slice and the
+	// column value buffers between rows, to reduce allocations.
+	//
+	// Values in Rset.Row (strings and byte slices included) are only valid
+	// until the next call to Rset.Next; copy them if you want to retain them.
+	//
+	// The default is false.
+	ReuseRow bool
+
+	// MaxCharBytes overrides the maximum number of bytes per character used
+	// to size the define buffers of CHAR, NCHAR, VARCHAR2 and NVARCHAR2
+	// columns: the buffer is the column's length in characters
+	// (OCI_ATTR_CHAR_SIZE) times MaxCharBytes.
+	//
+	// Zero means the maximum of the client character set, which prevents
+	// ORA-01406 (fetched column value was truncated) for multibyte data,
+	// but may waste memory on wide columns.
+	//
+	// The default is 0.
+	MaxCharBytes int
+
+	// MaxRows caps the number of rows returned by a Rset: Rset.Next
+	// returns false after MaxRows rows, and closes the cursor.
+	// Rset.Truncated reports whether there were more rows.
+	//
+	// The default is 0, which means no limit.
+	MaxRows int
+
+	// FetchFirstMaxRows makes Ses.Prep append a
+	// "FETCH FIRST MaxRows+1 ROWS ONLY" clause to queries
+	// which have no row limiting clause, to limit the rows server-side
+	// (Oracle 12c and later). The one extra row tells whether the result
+	// has been truncated.
+	//
+	// Only the MaxRows of the Ses's StmtCfg is observed, at prepare time.
+	//
+	// The default is false.
+	FetchFirstMaxRows bool
+
+	// LobPrefetchSize is the number of bytes (characters for CLOBs) of the
+	// selected LOBs fetched together with the rows
+	// (OCI_ATTR_LOBPREFETCH_SIZE).
+	//
+	// LOBs not longer than LobPrefetchSize are read from the prefetched data,
+	// without further round-trips, when returned as string or []byte;
+	// longer ones are streamed through their locators.
+	//
+	// Zero disables LOB prefetching.
+	//
+	// The default is 4000.
+	LobPrefetchSize uint32
+
+	// TempLobCache is the cache parameter of OCILobCreateTemporary for the
+	// temporary LOBs created for binds (Lob, []byte as BLOB, FileBlob):
+	// whether their blocks are read into the buffer cache, which speeds up
+	// the temporary LOBs read back right away (i.e. *Lob binds, or LOBs
+	// processed by PL/SQL), but uses buffer cache for write-once LOBs.
+	//
+	// The default is true.
+	TempLobCache bool
+
+	// StringBindBytes makes the string binds (string, String, *string and
+	// the string slices) set the server-side size of the bind
+	// (OCI_ATTR_MAXDATA_SIZE) to the length of their buffer in bytes,
+	// instead of letting OCI size it in characters.
+	//
+	// Set it when multibyte strings are silently truncated on binding, as
+	// it happens on databases with character length semantics (or EXTENDED
+	// string sizes), e.g. with 2000 multibyte characters bound for a
+	// VARCHAR2(4000 BYTE) column.
+	//
+	// The default is false.
+	StringBindBytes bool
+
+	// NumberSmartString makes the NUMBER and FLOAT columns (without a
+	// GoColumnType given to Prep) defined by their precision and scale, so
+	// no value loses precision: NUMBER(p) with p <= 18 (and NUMBER(p,s)
+	// with a negative s, p-s <= 18) as I64, NUMBER(p,s) with p <= 15 and
+	// FLOAT(p) with p <= 49 (binary digits) as F64, and everything else,
+	// such as an unconstrained NUMBER, as an exact string (N).
+	//
+	// It overrides the NUMBER and FLOAT settings of RsetCfg.
+	//
+	// The default is false.
+	NumberSmartString bool
+
+	// BlockRowCount makes Ses.Prep wrap anonymous PL/SQL blocks (BEGIN ...
+	// or DECLARE ...) into another block, which returns SQL%ROWCOUNT after
+	// the block in an extra, last placeholder, so Exe returns the number of
+	// rows affected by the last SQL statement of the block, instead of 0.
+	//
+	// Only the BlockRowCount of the Ses's StmtCfg is observed, at prepare
+	// time; the extra placeholder is bound by Exe, and is not counted by
+	// NumInput.
+	//
+	// The default is false.
+	BlockRowCount bool
+
+	// NoPrefetchForUpdate makes SELECT ... FOR UPDATE queries fetch one row
+	// per round-trip, without prefetching (OCI_ATTR_PREFETCH_ROWS and
+	// OCI_ATTR_PREFETCH_MEMORY are set to zero), so only the rows actually
+	// returned by Rset.Next are fetched from the server.
+	//
+	// This matters with "FOR UPDATE SKIP LOCKED", which locks the rows as
+	// they are fetched: without it, the prefetched but unconsumed rows are
+	// locked, too. A plain FOR UPDATE locks all the selected rows when the
+	// query is executed, regardless of prefetching.
+	//
+	// It overrides PrefetchRowCount, PrefetchMemorySize and WithPrefetch
+	// for such queries only; the statement text is checked at prepare time.
+	//
+	// The default is false.
+	NoPrefetchForUpdate bool
+
+	// KeepCancelErr makes a statement executed with a ctx (ExeContext,
+	// QryContext and the database/sql Context methods) return the
+	// "ORA-01013: user requested cancel of current operation" error as is,
+	// when the call is interrupted by Break as ctx is done.
+	//
+	// Otherwise, ctx.Err() (context.Canceled or context.DeadlineExceeded)
+	// is returned in its place.
+	//
+	// The default is false.
+	KeepCancelErr bool
+
 	// Rset represents configuration options for an Rset struct.
 	RsetCfg
 
@@ -65,6 +200,9 @@ func NewStmtCfg() StmtCfg {
 
 	c.IsAutoCommitting = true
 	c.RTrimChar = true
+	c.DetectLastInsertId = true
+	c.LobPrefetchSize = 4000
+	c.TempLobCache = true
 	c.FalseRune = '0'
 	c.TrueRune = '1'
 	c.RsetCfg = NewRsetCfg()
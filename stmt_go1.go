@@ -13,14 +13,22 @@ package ora
 import "C"
 
 // NumInput returns the number of placeholders in a sql statement.
+//
+// The number is cached till the statement is closed.
 func (stmt *Stmt) NumInput() int {
-	bc, err := stmt.attr(4, C.OCI_ATTR_BIND_COUNT)
-	if err != nil {
+	n := stmt.cachedNumInput(func() (int, error) {
+		bc, err := stmt.attr(4, C.OCI_ATTR_BIND_COUNT)
+		if err != nil {
+			return 0, err
+		}
+		bindCount := int(*((*C.ub4)(bc)))
+		C.free(bc)
+		return bindCount, nil
+	})
+	if n < 0 {
 		return 0
 	}
-	bindCount := int(*((*C.ub4)(bc)))
-	C.free(bc)
-	return bindCount
+	return n
 }
 
 func nameAndValue(v interface{}) (string, interface{}) {
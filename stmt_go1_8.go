@@ -10,18 +10,24 @@ import "database/sql/driver"
 
 // NumInput returns the number of placeholders in a sql statement.
 //
-// This returns a constant -1, as named params can be less, then positional params.
+// The duplicate (named) placeholders are counted once. It returns -1 if
+// the number is unknown.
+//
+// The number is cached till the statement is closed.
 func (stmt *Stmt) NumInput() int {
-	if bindNames, _, duplicates, err := stmt.getBindInfo(); err == nil {
+	return stmt.cachedNumInput(func() (int, error) {
+		bindNames, _, duplicates, err := stmt.getBindInfo()
+		if err != nil {
+			return -1, err
+		}
 		n := len(bindNames)
 		for _, d := range duplicates {
 			if d {
 				n--
 			}
 		}
-		return n
-	}
-	return -1
+		return n, nil
+	})
 }
 
 func nameAndValue(v interface{}) (string, interface{}) {
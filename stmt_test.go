@@ -0,0 +1,194 @@
+// Copyright 2017 The Ora Authors. All rights reserved.
+// Use of this source code is governed by The MIT License
+// found in the accompanying LICENSE file.
+
+package ora
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMergeCountArgs(t *testing.T) {
+	var ins, upd int64
+	names := []string{"1", "ORA_UPDATED", "2", "1", "ORA_INSERTED"}
+	dups := []bool{false, false, false, true, false}
+	args, err := mergeCountArgs(names, dups, []interface{}{"a", "b"}, &ins, &upd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 4 || args[0] != "a" || args[1] != &upd || args[2] != "b" || args[3] != &ins {
+		t.Errorf("got %v", args)
+	}
+	for i, params := range [][]interface{}{{"a"}, {"a", "b", "c"}} {
+		if _, err := mergeCountArgs(names, dups, params, &ins, &upd); err == nil {
+			t.Errorf("%d. %d params: no error", i, len(params))
+		}
+	}
+	if _, err := mergeCountArgs(names[:3], dups[:3], []interface{}{"a", "b"}, &ins, &upd); err == nil {
+		t.Error("no :ora_inserted: no error")
+	}
+}
+
+func TestAppendFetchFirst(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT * FROM DUAL":                             "SELECT * FROM DUAL\nFETCH FIRST 3 ROWS ONLY",
+		"with x AS (SELECT 1 FROM DUAL) SELECT * FROM x": "with x AS (SELECT 1 FROM DUAL) SELECT * FROM x\nFETCH FIRST 3 ROWS ONLY",
+		"SELECT * FROM t FOR UPDATE":                     "SELECT * FROM t FOR UPDATE",
+		"SELECT * FROM t FETCH FIRST 1 ROWS ONLY":        "SELECT * FROM t FETCH FIRST 1 ROWS ONLY",
+		"UPDATE t SET a = 1":                             "UPDATE t SET a = 1",
+	} {
+		if got := appendFetchFirst(sql, 3); got != want {
+			t.Errorf("%q: got %q, wanted %q", sql, got, want)
+		}
+	}
+}
+
+func TestPageSql(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT * FROM t ORDER BY a":    "SELECT * FROM t ORDER BY a\nOFFSET :ora_off ROWS FETCH NEXT :ora_lim ROWS ONLY",
+		"SELECT * FROM t -- comment\n;": "SELECT * FROM t -- comment\nOFFSET :ora_off ROWS FETCH NEXT :ora_lim ROWS ONLY",
+	} {
+		if got := pageSql(sql); got != want {
+			t.Errorf("%q: got %q, wanted %q", sql, got, want)
+		}
+	}
+}
+
+func TestIsForUpdate(t *testing.T) {
+	for sql, want := range map[string]bool{
+		"SELECT * FROM t FOR UPDATE":              true,
+		"select * from t\nfor update skip locked": true,
+		"SELECT * FROM t\tFOR  UPDATE NOWAIT":     true,
+		"SELECT for_update FROM t":                false,
+		"SELECT * FROM t":                         false,
+	} {
+		if got := isForUpdate(sql); got != want {
+			t.Errorf("%q: got %t, wanted %t", sql, got, want)
+		}
+	}
+}
+
+func TestIsPLSQLBlock(t *testing.T) {
+	for sql, want := range map[string]bool{
+		"BEGIN NULL; END;":                   true,
+		"  begin\n  DELETE FROM t; END;":     true,
+		"DECLARE n NUMBER; BEGIN NULL; END;": true,
+		"BEGINNING":                          false,
+		"CALL p()":                           false,
+		"UPDATE t SET a = 1":                 false,
+	} {
+		if got := isPLSQLBlock(sql); got != want {
+			t.Errorf("%q: got %t, wanted %t", sql, got, want)
+		}
+	}
+}
+
+func TestTypedSlice(t *testing.T) {
+	typed, ok := typedSlice([]interface{}{int64(1), int64(2)})
+	if !ok {
+		t.Fatal("homogeneous slice not converted")
+	}
+	if s, isInt64s := typed.([]int64); !isInt64s || len(s) != 2 || s[0] != 1 || s[1] != 2 {
+		t.Errorf("got %#v, wanted []int64{1, 2}", typed)
+	}
+	for _, values := range [][]interface{}{
+		nil,
+		{int64(1), "a"},
+		{int64(1), int32(2)},
+		{int64(1), nil},
+		{nil, int64(1)},
+	} {
+		if typed, ok = typedSlice(values); ok {
+			t.Errorf("%#v: got %#v, wanted no conversion", values, typed)
+		}
+	}
+}
+
+func TestBasicValue(t *testing.T) {
+	for i, tc := range []struct {
+		in, want interface{}
+	}{
+		{7, int64(7)},
+		{uint(7), uint64(7)},
+	} {
+		got, ok := basicValue(tc.in)
+		if !ok || got != tc.want {
+			t.Errorf("%d. %#v: got %#v (%t), wanted %#v", i, tc.in, got, ok, tc.want)
+		}
+	}
+	for _, v := range []interface{}{
+		nil, int64(1), "a", Rowid("AAA"), Num("1"), time.Time{}, []int{1},
+		time.Second, time.March, os.ModePerm,
+	} {
+		if got, ok := basicValue(v); ok {
+			t.Errorf("%#v: got %#v, wanted no conversion", v, got)
+		}
+	}
+}
+
+func TestKindValue(t *testing.T) {
+	type status int
+	type code string
+	type ratio float32
+	for i, tc := range []struct {
+		in, want interface{}
+	}{
+		{status(3), int64(3)},
+		{code("x"), "x"},
+		{ratio(0.5), float32(0.5)},
+	} {
+		got, ok := kindValue(reflect.ValueOf(tc.in))
+		if !ok || got != tc.want {
+			t.Errorf("%d. %#v: got %#v (%t), wanted %#v", i, tc.in, got, ok, tc.want)
+		}
+	}
+}
+
+func TestIsStdPkg(t *testing.T) {
+	for path, want := range map[string]bool{
+		"time":                 true,
+		"net/http":             true,
+		"main":                 false,
+		"example.com/app":      false,
+		"gopkg.in/rana/ora.v4": false,
+	} {
+		if got := isStdPkg(path); got != want {
+			t.Errorf("%q: got %t, wanted %t", path, got, want)
+		}
+	}
+}
+
+func TestIsStaleErr(t *testing.T) {
+	for code, want := range map[int]bool{4061: true, 4062: true, 4065: true, 4068: true, 4063: false, 942: false} {
+		if got := isStaleErr(&oraErr{Underlying: &ORAError{code: code}}); got != want {
+			t.Errorf("ORA-%05d: got %t, wanted %t", code, got, want)
+		}
+	}
+	if isStaleErr(nil) {
+		t.Error("nil error is stale")
+	}
+}
+
+func TestCtxCancelErr(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cancelErr := &oraErr{Underlying: &ORAError{code: 1013}}
+	for i, tc := range []struct {
+		ctx  context.Context
+		err  error
+		want error
+	}{
+		{ctx: canceled, err: cancelErr, want: context.Canceled},
+		{ctx: context.Background(), err: cancelErr},
+		{ctx: canceled, err: &oraErr{Underlying: &ORAError{code: 942}}},
+		{ctx: canceled},
+	} {
+		if got := ctxCancelErr(tc.ctx, tc.err); got != tc.want {
+			t.Errorf("%d. got %v, wanted %v", i, got, tc.want)
+		}
+	}
+}
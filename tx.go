@@ -12,6 +12,7 @@ import "C"
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // LogTxCfg represents Tx logging configuration values.
@@ -35,6 +36,38 @@ func NewLogTxCfg() LogTxCfg {
 	return c
 }
 
+// TxCfg configures a transaction started by Ses.StartTxContext.
+type TxCfg struct {
+	// Serializable starts the transaction with the SERIALIZABLE
+	// isolation level.
+	//
+	// The default is false, which means READ COMMITTED.
+	Serializable bool
+
+	// ReadOnly starts a read-only transaction.
+	//
+	// The default is false.
+	ReadOnly bool
+
+	// Timeout is the time the transaction can be inactive,
+	// before it is automatically terminated by the server.
+	//
+	// The default is 0, which means 60 seconds.
+	Timeout time.Duration
+}
+
+// options returns the TxOptions of the StartTx call.
+func (c TxCfg) options() []TxOption {
+	var flags uint32
+	if c.Serializable {
+		flags |= C.OCI_TRANS_SERIALIZABLE
+	}
+	if c.ReadOnly {
+		flags |= C.OCI_TRANS_READONLY
+	}
+	return []TxOption{TxFlags(flags), TxTimeout(c.Timeout)}
+}
+
 // Tx represents an Oracle transaction associated with a session.
 //
 // Implements the driver.Tx interface.
@@ -150,22 +183,22 @@ func (tx *Tx) sysName() string {
 
 // log writes a message with an Tx system name and caller info.
 func (tx *Tx) log(enabled bool, v ...interface{}) {
-	if enabled {
-		if len(v) == 0 {
-			_drv.Cfg().Log.Logger.Infof("%v %v", tx.sysName(), callInfo(1))
-		} else {
-			_drv.Cfg().Log.Logger.Infof("%v %v %v", tx.sysName(), callInfo(1), fmt.Sprint(v...))
-		}
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
+		return
 	}
+	Log.emit(LogEvent{Component: "Tx", Operation: callOp(1), SysName: tx.sysName(), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with an Tx system name and caller info.
 func (tx *Tx) logF(enabled bool, format string, v ...interface{}) {
-	if enabled {
-		if len(v) == 0 {
-			_drv.Cfg().Log.Logger.Infof("%v %v", tx.sysName(), callInfo(1))
-		} else {
-			_drv.Cfg().Log.Logger.Infof("%v %v %v", tx.sysName(), callInfo(1), fmt.Sprintf(format, v...))
-		}
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
+		return
+	}
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Component: "Tx", Operation: callOp(1), SysName: tx.sysName(), Msg: msg})
 }
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -388,6 +389,60 @@ func (this *Time) UnmarshalJSON(p []byte) error {
 	return json.Unmarshal(p, &this.Value)
 }
 
+// Rowid is the character representation of an Oracle ROWID, as returned
+// for ROWID columns.
+//
+// Rowid and []Rowid bind parameters are sent as strings, and converted to
+// ROWID by the server, so a []Rowid (with parallel value slices) can drive
+// a bulk positioned array DML, such as "UPDATE t SET c = :1 WHERE ROWID = :2".
+//
+// A *Rowid bind parameter is an output bind, i.e. to capture the ROWID of
+// the affected row of a single-row DML with "RETURNING ROWID INTO :r";
+// it is set after the execution, and set to "" for NULL.
+type Rowid string
+
+// String returns the Rowid as is, i.e. the character form it was fetched
+// (or returned) in.
+func (this Rowid) String() string {
+	return string(this)
+}
+
+// EpochSeconds is a bind parameter of seconds elapsed since
+// 1970-01-01 00:00:00 UTC (Unix epoch).
+//
+// It is bound as a TIMESTAMP WITH TIME ZONE in UTC. Compared with a DATE
+// or TIMESTAMP column, Oracle takes the column's values to be in the session
+// time zone, as TUnix does on fetch: they match UTC only if the session time
+// zone is UTC.
+type EpochSeconds int64
+
+// Time returns the EpochSeconds as a time.Time in UTC.
+func (this EpochSeconds) Time() time.Time {
+	return time.Unix(int64(this), 0).UTC()
+}
+
+// Value returns the time.Time, for database/sql.
+func (this EpochSeconds) Value() (driver.Value, error) {
+	return this.Time(), nil
+}
+
+// EpochMillis is a bind parameter of milliseconds elapsed since
+// 1970-01-01 00:00:00 UTC (Unix epoch).
+//
+// The same time zone rules apply as for EpochSeconds.
+type EpochMillis int64
+
+// Time returns the EpochMillis as a time.Time in UTC.
+func (this EpochMillis) Time() time.Time {
+	ms := int64(this)
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+}
+
+// Value returns the time.Time, for database/sql.
+func (this EpochMillis) Value() (driver.Value, error) {
+	return this.Time(), nil
+}
+
 // Date is a nullable date, for low (second) precisions (OCIDate)
 type Date struct {
 	date.Date
@@ -396,6 +451,28 @@ type Date struct {
 var _ = (json.Marshaler)(Date{})
 var _ = (json.Unmarshaler)((*Date)(nil))
 
+// AsDate returns t to be bound as DATE, without fractional seconds and
+// time zone: the wall clock of t in its location is sent.
+func AsDate(t time.Time) Date { return Date{Date: date.FromTime(t)} }
+
+// Timestamp is a time.Time bound as TIMESTAMP, without time zone:
+// the wall clock of the time in its location is sent. See AsTimestamp.
+type Timestamp struct {
+	time.Time
+}
+
+// AsTimestamp returns t to be bound as TIMESTAMP.
+func AsTimestamp(t time.Time) Timestamp { return Timestamp{Time: t} }
+
+// TimestampTZ is a time.Time bound as TIMESTAMP WITH TIME ZONE, as
+// a time.Time is bound by default. See AsTimestampTZ.
+type TimestampTZ struct {
+	time.Time
+}
+
+// AsTimestampTZ returns t to be bound as TIMESTAMP WITH TIME ZONE.
+func AsTimestampTZ(t time.Time) TimestampTZ { return TimestampTZ{Time: t} }
+
 // String is a nullable string.
 type String struct {
 	IsNull bool
@@ -577,6 +654,11 @@ type Raw struct {
 	Value  []byte
 }
 
+// BlobBytes is a byte slice bound as a BLOB: a []BlobBytes binds an array
+// of BLOBs, through temporary LOBs, for values over the RAW bind limit
+// of [][]byte. A nil BlobBytes is NULL.
+type BlobBytes []byte
+
 // Equals returns true when the receiver and specified Raw are both null,
 // or when the receiver and specified Raw are both not null and Values are equal.
 func (this Raw) Equals(other Raw) bool {
@@ -603,6 +685,20 @@ func (this *Raw) UnmarshalJSON(p []byte) error {
 	return json.Unmarshal(p, &this.Value)
 }
 
+// FileBlob is a BLOB parameter streamed from R, the first Size bytes of it,
+// such as an *os.File: it is written into a temporary LOB chunk by chunk
+// (see StmtCfg.LobBufferSize) at bind time, without reading it into memory
+// as a whole.
+//
+// The streaming stops with the context's error, when the context of
+// Stmt.ExeContext is cancelled.
+//
+// A FileBlob with nil R is bound as NULL.
+type FileBlob struct {
+	R    io.ReaderAt
+	Size int64
+}
+
 // Lob Reader is sent to the DB on bind, if not nil.
 // The Reader can read the LOB if we bind a *Lob, Closer will close the LOB.
 // Set Lob.C = true to make this a CLOB reader!
@@ -610,6 +706,14 @@ type Lob struct {
 	io.Reader
 	io.Closer
 	C bool
+
+	// Charset is the character set of the bytes of Reader for a CLOB (C is
+	// true), i.e. "WE8MSWIN1252"; OCI converts them to the database
+	// character set.
+	//
+	// The default is "", which means AL32UTF8 (UTF-8), the client character
+	// set of the driver.
+	Charset string
 }
 
 func (this *Lob) Close() error {
@@ -626,6 +730,143 @@ func (this *Lob) Read(p []byte) (int, error) {
 	return this.Reader.Read(p)
 }
 
+// lobEditor is implemented by the LOB readers holding a LOB locator.
+type lobEditor interface {
+	Trim(newLen int64) error
+	Erase(offset, amount int64) error
+	Append(p []byte) (int, error)
+}
+
+// Trim truncates the underlying database LOB to newLen bytes (characters
+// for CLOBs), on the server side.
+//
+// The Lob must have been fetched from the database (within a transaction,
+// with SELECT ... FOR UPDATE), otherwise an error is returned.
+func (this *Lob) Trim(newLen int64) error {
+	if this == nil {
+		return errNew("nil Lob")
+	}
+	le, ok := this.Reader.(lobEditor)
+	if !ok {
+		return errF("Lob (%T) has no LOB locator", this.Reader)
+	}
+	return le.Trim(newLen)
+}
+
+// Erase erases amount bytes (characters for CLOBs) of the underlying
+// database LOB, starting at the 0-based offset, on the server side.
+// The erased range is filled with zero bytes (spaces for CLOBs).
+//
+// The same restrictions apply as for Trim.
+func (this *Lob) Erase(offset, amount int64) error {
+	if this == nil {
+		return errNew("nil Lob")
+	}
+	le, ok := this.Reader.(lobEditor)
+	if !ok {
+		return errF("Lob (%T) has no LOB locator", this.Reader)
+	}
+	return le.Erase(offset, amount)
+}
+
+// Append appends p to the end of the underlying database LOB (as text
+// for CLOBs), returning the number of bytes written. The server tracks
+// the position, so there's no need to know the current length.
+//
+// The same restrictions apply as for Trim.
+func (this *Lob) Append(p []byte) (int, error) {
+	if this == nil {
+		return 0, errNew("nil Lob")
+	}
+	le, ok := this.Reader.(lobEditor)
+	if !ok {
+		return 0, errF("Lob (%T) has no LOB locator", this.Reader)
+	}
+	return le.Append(p)
+}
+
+// Length returns the length of the underlying database LOB (with
+// OCILobGetLength2): characters for CLOBs and NCLOBs, bytes for BLOBs.
+//
+// It does not move the position of the reader, so it can be called before
+// reading the Lob, e.g. to size a buffer. After the Lob has been read to
+// its end (which releases the locator), it returns the length the LOB had
+// when the reading started.
+// For a Lob already read into memory (see Bytes), it returns the length
+// of its bytes.
+func (this *Lob) Length() (int64, error) {
+	if this == nil {
+		return 0, errNew("nil Lob")
+	}
+	switch r := this.Reader.(type) {
+	case interface {
+		lobLength() (int64, error)
+	}:
+		return r.lobLength()
+	case bytesPeeker:
+		return int64(len(r.PeekBytes())), nil
+	}
+	return 0, errF("Lob (%T) has no LOB locator", this.Reader)
+}
+
+// ChunkSize returns the chunk size of the underlying database LOB, as
+// reported by OCILobGetChunkSize: reads and writes in multiples of it are
+// the most efficient. It opens the LOB for reading, if it's not open yet.
+//
+// Returns 0 if it's unknown, e.g. the Lob has not been fetched from the
+// database.
+func (this *Lob) ChunkSize() int {
+	if this == nil {
+		return 0
+	}
+	if cs, ok := this.Reader.(interface {
+		ChunkSize() int
+	}); ok {
+		return cs.ChunkSize()
+	}
+	return 0
+}
+
+// lobOpener is implemented by the LOB readers holding a LOB locator.
+type lobOpener interface {
+	Open(mode LobOpenMode) error
+	CloseLob() error
+}
+
+// Open opens the underlying database LOB explicitly, in the given mode,
+// so a series of operations (e.g. Trim, Erase) don't open and close it
+// implicitly each time. Close it with CloseLob.
+//
+// A LOB opened only implicitly, for reading it (or by an OUT bind), is
+// reopened in mode. If the open fails, the Lob remains usable (and must be
+// closed).
+//
+// Returns an error if the LOB is already open with Open, or the Lob has not
+// been fetched from the database.
+func (this *Lob) Open(mode LobOpenMode) error {
+	if this == nil {
+		return errNew("nil Lob")
+	}
+	lo, ok := this.Reader.(lobOpener)
+	if !ok {
+		return errF("Lob (%T) has no LOB locator", this.Reader)
+	}
+	return lo.Open(mode)
+}
+
+// CloseLob closes the underlying database LOB opened with Open.
+// Contrary to Close, the Lob remains usable.
+func (this *Lob) CloseLob() error {
+	if this == nil {
+		return errNew("nil Lob")
+	}
+	lo, ok := this.Reader.(lobOpener)
+	if !ok {
+		return errF("Lob (%T) has no LOB locator", this.Reader)
+	}
+	return lo.CloseLob()
+}
+
 // Equals returns true when the receiver and specified Lob are both null,
 // or when they both not null and share the same Reader.
 func (this *Lob) Equals(other Lob) bool {
@@ -755,6 +996,11 @@ func (this IntervalYM) ShiftTime(t time.Time) time.Time {
 	return t.AddDate(int(this.Year), int(this.Month), 0)
 }
 
+// Months returns the IntervalYM as the total number of months.
+func (this IntervalYM) Months() int {
+	return int(this.Year)*12 + int(this.Month)
+}
+
 // IntervalDS represents a nullable INTERVAL DAY TO SECOND Oracle value.
 type IntervalDS struct {
 	IsNull     bool
@@ -785,6 +1031,24 @@ func (this IntervalDS) String() string {
 	return fmt.Sprintf("%02dd %02d:%02d:%02d.%d", this.Day, this.Hour, this.Minute, this.Second, this.Nanosecond)
 }
 
+// maxDurationDays is the number of whole days a time.Duration can hold.
+const maxDurationDays = int32(math.MaxInt64 / int64(24*time.Hour))
+
+// Duration returns the IntervalDS as a time.Duration.
+//
+// A time.Duration holds about 292 years, so intervals of 106751 days
+// or more are returned as an error.
+func (this IntervalDS) Duration() (time.Duration, error) {
+	if this.Day >= maxDurationDays || this.Day <= -maxDurationDays {
+		return 0, errF("interval of %d days overflows time.Duration", this.Day)
+	}
+	return time.Duration(this.Day)*24*time.Hour +
+		time.Duration(this.Hour)*time.Hour +
+		time.Duration(this.Minute)*time.Minute +
+		time.Duration(this.Second)*time.Second +
+		time.Duration(this.Nanosecond), nil
+}
+
 // ShiftTime returns a new Time with IntervalDS applied.
 func (this IntervalDS) ShiftTime(t time.Time) time.Time {
 	year, month, day := t.Date()
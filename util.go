@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"math"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 // checkNumericColumn returns nil when the column type is numeric; otherwise, an error.
@@ -33,10 +36,51 @@ func checkNumericColumn(gct GoColumnType, columnName string) error {
 // checkTimeColumn returns nil when the column type is time; otherwise, an error.
 func checkTimeColumn(gct GoColumnType) error {
 	switch gct {
-	case T, OraT:
+	case T, OraT, TUnix, TUnixMs, TNls:
 		return nil
 	}
-	return errF("Invalid go column type (%v) specified for time-based sql column. Expected go column type T or OraT.", GctName(gct))
+	return errF("Invalid go column type (%v) specified for time-based sql column. Expected go column type T, OraT, TUnix, TUnixMs or TNls.", GctName(gct))
+}
+
+// checkIntervalColumn returns nil when the column type is valid for an
+// INTERVAL column, Dur for DAY TO SECOND, Months for YEAR TO MONTH;
+// otherwise, an error.
+func checkIntervalColumn(gct GoColumnType, valid GoColumnType) error {
+	if gct == D || gct == valid {
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for interval sql column. Expected go column type D or %v.", GctName(gct), GctName(valid))
+}
+
+// checkIdentifier returns nil when name is a valid simple (unquoted)
+// Oracle identifier; otherwise, an error.
+func checkIdentifier(name string) error {
+	if name == "" || len(name) > 128 {
+		return errF("Invalid identifier %q: length must be between 1 and 128.", name)
+	}
+	for i, r := range name {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && ('0' <= r && r <= '9' || r == '_' || r == '$' || r == '#'):
+		default:
+			return errF("Invalid identifier %q: invalid character %q at %d.", name, r, i)
+		}
+	}
+	return nil
+}
+
+// isEpochColumn returns true when the column type is a Unix epoch int64.
+func isEpochColumn(gct GoColumnType) bool {
+	return gct == TUnix || gct == TUnixMs
+}
+
+// epochValue returns t as seconds (TUnix) or milliseconds (TUnixMs)
+// elapsed since 1970-01-01 00:00:00 UTC.
+func epochValue(t time.Time, gct GoColumnType) int64 {
+	if gct == TUnixMs {
+		return t.Unix()*1000 + int64(t.Nanosecond())/int64(time.Millisecond)
+	}
+	return t.Unix()
 }
 
 // checkStringColumn returns nil when the column type is string; otherwise, an error.
@@ -75,6 +119,59 @@ func checkBinColumn(gct GoColumnType) error {
 	return errF("Invalid go column type (%v) specified. Expected go column type Bits or OraBits.", GctName(gct))
 }
 
+// checkRawColumn returns nil when the column type is valid for a RAW column;
+// otherwise, an error.
+func checkRawColumn(gct GoColumnType) error {
+	switch gct {
+	case Bin, OraBin, S, OraS:
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for RAW column. Expected go column type Bin, OraBin, S or OraS.", GctName(gct))
+}
+
+func checkOpaqueColumn(gct GoColumnType) error {
+	switch gct {
+	case S, OraS, Bin, OraBin:
+		return nil
+	}
+	return errF("Invalid go column type (%v) specified for named (opaque) column. Expected go column type S, OraS, Bin or OraBin.", GctName(gct))
+}
+
+// isSqlName reports whether name is a simple or quoted SQL name,
+// optionally prefixed with a (simple or quoted) schema name if qualified.
+func isSqlName(name string, qualified bool) bool {
+	if qualified {
+		if i := sqlNameEnd(name); i > 0 && i < len(name) && name[i] == '.' {
+			name = name[i+1:]
+		}
+	}
+	return name != "" && sqlNameEnd(name) == len(name)
+}
+
+// sqlNameEnd returns the length of the simple or quoted SQL name at the
+// start of s; 0 if s doesn't start with one.
+func sqlNameEnd(s string) int {
+	if s == "" {
+		return 0
+	}
+	if s[0] == '"' {
+		i := strings.IndexByte(s[1:], '"')
+		if i <= 0 || strings.ContainsRune(s[1:i+1], 0) {
+			return 0
+		}
+		return i + 2
+	}
+	c := s[0]
+	if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+		return 0
+	}
+	i := 1
+	for i < len(s) && isBindNameChar(s[i]) {
+		i++
+	}
+	return i
+}
+
 func clear(buffer []byte, fill byte) {
 	for n := range buffer {
 		buffer[n] = fill
@@ -102,6 +199,13 @@ func callInfo(depth int) string {
 	// main.(*core).open
 	return fmt.Sprintf("[%v.%v]", method[m+1:n], method[n+2:])
 }
+
+// callOp returns the caller's method name, as callInfo, without the brackets.
+func callOp(depth int) string {
+	s := callInfo(depth + 1)
+	return s[1 : len(s)-1]
+}
+
 func errInfo(depth int) fmt.Stringer {
 	// get caller method name; remove main. prefix
 	pc, file, line, _ := runtime.Caller(depth + 1)
@@ -136,24 +240,24 @@ func (m methodInfo) String() string { return m.Type + "." + m.Method }
 
 // log writes a message with caller info.
 func log(enabled bool, v ...interface{}) {
-	if enabled {
-		if len(v) == 0 {
-			_drv.Cfg().Log.Logger.Infof("%v", callInfo(1))
-		} else {
-			_drv.Cfg().Log.Logger.Infof("%v %v", callInfo(1), fmt.Sprint(v...))
-		}
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
+		return
 	}
+	Log.emit(LogEvent{Operation: callOp(1), Msg: fmt.Sprint(v...)})
 }
 
 // log writes a formatted message with caller info.
 func logF(enabled bool, format string, v ...interface{}) {
-	if enabled {
-		if len(v) == 0 {
-			_drv.Cfg().Log.Logger.Infof("%v", callInfo(1))
-		} else {
-			_drv.Cfg().Log.Logger.Infof("%v %v", callInfo(1), fmt.Sprintf(format, v...))
-		}
+	Log := _drv.Cfg().Log
+	if !Log.IsEnabled(enabled) {
+		return
+	}
+	var msg string
+	if len(v) != 0 {
+		msg = fmt.Sprintf(format, v...)
 	}
+	Log.emit(LogEvent{Operation: callOp(1), Msg: msg})
 }
 
 // err creates an error with caller info.
@@ -166,16 +270,16 @@ func er(v ...interface{}) error {
 	if err == nil {
 		err = errors.New(fmt.Sprint(v...))
 	}
-	err = &oraErr{Caller: errInfo(1), Underlying: err}
-	_drv.Cfg().Log.Logger.Errorln(err)
-	return err
+	oe := &oraErr{Caller: errInfo(1), Underlying: err}
+	logErr(oe)
+	return oe
 }
 
 // errF creates a formatted error with caller info.
 func errF(format string, v ...interface{}) error {
 	//err := errors.New(fmt.Sprintf("%v %v", errInfo(1), fmt.Sprintf(format, v...)))
 	err := &oraErr{Caller: errInfo(1), Underlying: fmt.Errorf(format, v...)}
-	_drv.Cfg().Log.Logger.Errorln(err)
+	logErr(err)
 	return err
 }
 
@@ -197,7 +301,7 @@ func errR(v ...interface{}) error {
 		Underlying: errors.New(fmt.Sprint(v...)),
 		Trace:      getStack(),
 	}
-	_drv.Cfg().Log.Logger.Errorln(err)
+	logErr(err)
 	return err
 }
 
@@ -205,10 +309,21 @@ func errR(v ...interface{}) error {
 func errE(e error) error {
 	//err := errors.New(fmt.Sprintf("%v %v", errInfo(1), e.Error()))
 	err := &oraErr{Caller: errInfo(1), Underlying: e}
-	_drv.Cfg().Log.Logger.Errorln(err)
+	logErr(err)
 	return err
 }
 
+// logErr writes the error to the Logger, and passes it to OnEvent.
+func logErr(err *oraErr) {
+	Log := _drv.Cfg().Log
+	if Log.Logger != nil {
+		Log.Logger.Errorln(err)
+	}
+	if Log.OnEvent != nil {
+		Log.OnEvent(LogEvent{Operation: err.Caller.String(), Err: err})
+	}
+}
+
 type oraErr struct {
 	Caller     fmt.Stringer
 	Underlying error
@@ -247,6 +362,17 @@ func (e oraErr) Code() int {
 	return code
 }
 
+// Instance returns the database instance name of the underlying ORAError,
+// if known.
+func (e oraErr) Instance() string {
+	if inst, ok := e.Underlying.(interface {
+		Instance() string
+	}); ok {
+		return inst.Instance()
+	}
+	return ""
+}
+
 // DescribedColumn type for describing a column (see DescribeQuery).
 type DescribedColumn struct {
 	Column
@@ -454,3 +580,63 @@ func boundingPower(n int) int {
 	}
 	return i
 }
+
+// alignToChunk rounds size down to a multiple of chunk,
+// if it's at least chunk long.
+func alignToChunk(size, chunk int) int {
+	if chunk <= 0 || size < chunk {
+		return size
+	}
+	return size / chunk * chunk
+}
+
+// scnValue converts the value of a System Change Number column to uint64.
+func scnValue(v interface{}) (uint64, error) {
+	switch x := v.(type) {
+	case nil:
+		return 0, er("SCN is NULL.")
+	case uint64:
+		return x, nil
+	case int64:
+		if x >= 0 {
+			return uint64(x), nil
+		}
+	case float64:
+		if x >= 0 {
+			return uint64(x), nil
+		}
+	case Uint64:
+		if !x.IsNull {
+			return x.Value, nil
+		}
+		return scnValue(nil)
+	case Int64:
+		if x.IsNull {
+			return scnValue(nil)
+		}
+		return scnValue(x.Value)
+	case Float64:
+		if x.IsNull {
+			return scnValue(nil)
+		}
+		return scnValue(x.Value)
+	case OraNum:
+		if x.IsNull {
+			return scnValue(nil)
+		}
+		return scnValue(x.Value)
+	case Num:
+		return scnValue(string(x))
+	case OCINum:
+		return scnValue(x.String())
+	case string:
+		return strconv.ParseUint(x, 10, 64)
+	}
+	return 0, errF("SCN must be a non-negative integer, got %#v", v)
+}
+
+// isNonFinite reports whether f is an infinity or NaN,
+// which can't be represented as an Oracle NUMBER.
+func isNonFinite(f float64) bool {
+	return math.IsInf(f, 0) || math.IsNaN(f)
+}
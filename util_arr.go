@@ -17,6 +17,9 @@ type arrHlp struct {
 	alen       []C.ACTUAL_LENGTH_TYPE
 	rcode      []C.ub2
 	isAssocArr bool
+	// nullsSet is set when nullInds has been filled for the next bind,
+	// by presetNullInds.
+	nullsSet bool
 }
 
 type ociDef struct {
@@ -150,9 +153,35 @@ func (a *arrHlp) ensureBindArrLength(
 			a.rcode = a.rcode[:L]
 		}
 	}
+	// The indicators may be left over from a previous bind (or the pool),
+	// so each element is non-NULL, unless it has been preset.
+	if !a.nullsSet {
+		for i := range a.nullInds {
+			a.nullInds[i] = 0
+		}
+	}
+	a.nullsSet = false
 	return iterations, curlenp, needsAppend
 }
 
+// presetNullInds returns nullInds with the given length (and at least
+// the given capacity), for the caller to set the indicator of each element
+// before binding, as ensureBindArrLength keeps them.
+func (a *arrHlp) presetNullInds(length, capacity int) []C.sb2 {
+	a.Lock()
+	defer a.Unlock()
+	if capacity < length {
+		capacity = length
+	}
+	if cap(a.nullInds) < capacity {
+		a.nullInds = make([]C.sb2, length, capacity)
+	} else {
+		a.nullInds = a.nullInds[:length]
+	}
+	a.nullsSet = true
+	return a.nullInds
+}
+
 // IsAssocArr returns true if the bind uses PL/SQL Table.
 func (a arrHlp) IsAssocArr() bool {
 	return a.isAssocArr
@@ -169,6 +198,7 @@ func (a *arrHlp) close() error {
 	}
 	a.Lock()
 	defer a.Unlock()
+	a.nullsSet = false
 	if a.isAssocArr {
 		return nil
 	}
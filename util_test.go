@@ -21,3 +21,110 @@ func TestBoundingPower(t *testing.T) {
 		}
 	}
 }
+
+func TestAlignToChunk(t *testing.T) {
+	for i, tc := range [][3]int{
+		{0, 0, 0},
+		{100, 0, 100},
+		{100, 8132, 100},
+		{8132, 8132, 8132},
+		{1 << 20, 8132, 128 * 8132},
+		{1 << 20, 32528, 32 * 32528},
+	} {
+		got := alignToChunk(tc[0], tc[1])
+		if got != tc[2] {
+			t.Errorf("%d. (%d, %d) got %d, wanted %d.", i, tc[0], tc[1], got, tc[2])
+		}
+	}
+}
+
+func TestScnValue(t *testing.T) {
+	for i, tc := range []struct {
+		in   interface{}
+		want uint64
+		err  bool
+	}{
+		{in: float64(1234567), want: 1234567},
+		{in: int64(42), want: 42},
+		{in: uint64(1 << 40), want: 1 << 40},
+		{in: "9876543210", want: 9876543210},
+		{in: Num("12"), want: 12},
+		{in: OraNum{Value: "13"}, want: 13},
+		{in: Int64{Value: 14}, want: 14},
+		{in: nil, err: true},
+		{in: Int64{IsNull: true}, err: true},
+		{in: int64(-1), err: true},
+		{in: "abc", err: true},
+	} {
+		got, err := scnValue(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%d. (%#v) wanted error, got %d", i, tc.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d. (%#v): %v", i, tc.in, err)
+		} else if got != tc.want {
+			t.Errorf("%d. (%#v) got %d, wanted %d", i, tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEpochValue(t *testing.T) {
+	for i, ms := range []int64{0, 1, 999, 1000, 1500000000123, -1, -1001} {
+		tm := EpochMillis(ms).Time()
+		if got := epochValue(tm, TUnixMs); got != ms {
+			t.Errorf("%d. TUnixMs got %d, wanted %d", i, got, ms)
+		}
+		if got, want := epochValue(tm, TUnix), tm.Unix(); got != want {
+			t.Errorf("%d. TUnix got %d, wanted %d", i, got, want)
+		}
+	}
+	if got := epochValue(EpochSeconds(1500000000).Time(), TUnix); got != 1500000000 {
+		t.Errorf("EpochSeconds got %d", got)
+	}
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	for name, ok := range map[string]bool{
+		"ORA$BASE":  true,
+		"e_2017#1":  true,
+		"":          false,
+		"1edition":  false,
+		"_edition":  false,
+		"ed ition":  false,
+		"ed\"ition": false,
+		"a; DROP":   false,
+	} {
+		if err := checkIdentifier(name); (err == nil) != ok {
+			t.Errorf("%q: got %v, wanted ok=%t", name, err, ok)
+		}
+	}
+}
+
+func TestIsSqlName(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		qualified bool
+		want      bool
+	}{
+		{"emp", false, true},
+		{"Emp_No$1", false, true},
+		{`"Emp No"`, false, true},
+		{"hr.emp", true, true},
+		{`"HR"."Emp No"`, true, true},
+		{"hr.emp", false, false},
+		{"hr.emp.x", true, false},
+		{"", false, false},
+		{"1emp", false, false},
+		{`""`, false, false},
+		{`"a"b"`, false, false},
+		{"emp; DROP TABLE emp", false, false},
+		{"emp (a)", true, false},
+	} {
+		if got := isSqlName(tc.name, tc.qualified); got != tc.want {
+			t.Errorf("%q (qualified=%t): got %t, wanted %t", tc.name, tc.qualified, got, tc.want)
+		}
+	}
+}
@@ -115,3 +115,78 @@ decriptorAllocSlice(
 	}
 	return OCI_SUCCESS;
 }
+
+sword
+shardingKeyAdd(
+	OCIEnv *env,
+	OCIError *err,
+	void *authInfo,
+	int isSuper,
+	void **values,
+	ub4 *lengths,
+	ub2 *types,
+	ub4 n,
+	void **keyp
+) {
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(12,2)
+	sword rc;
+	ub4 i;
+	*keyp = NULL;
+	rc = OCIDescriptorAlloc(env, keyp, OCI_DTYPE_SHARDING_KEY, 0, 0);
+	if(rc != OCI_SUCCESS) {
+		return rc;
+	}
+	for(i=0; i < n; i++) {
+		rc = OCIShardingKeyColumnAdd(
+			(OCIShardingKey*)*keyp, //OCIShardingKey *shardingKey,
+			err,                    //OCIError       *errhp,
+			values[i],              //void           *col,
+			lengths[i],             //ub4            colLen,
+			types[i],               //ub2            colType,
+			OCI_DEFAULT);           //ub4            mode
+		if(rc == OCI_ERROR) {
+			return rc;
+		}
+	}
+	return OCIAttrSet(
+		authInfo,
+		OCI_HTYPE_AUTHINFO,
+		*keyp,
+		0,
+		isSuper ? OCI_ATTR_SUPER_SHARDING_KEY : OCI_ATTR_SHARDING_KEY,
+		err);
+#else
+	*keyp = NULL;
+	return OCI_INVALID_HANDLE;
+#endif
+}
+
+void
+shardingKeyFree(void *key) {
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(12,2)
+	if(key != NULL) {
+		OCIDescriptorFree(key, OCI_DTYPE_SHARDING_KEY);
+	}
+#endif
+}
+
+sword
+nextImplicitResult(
+	OCIStmt *stmtp,
+	OCIError *err,
+	void **result,
+	ub4 *isSelect
+) {
+#if ORACLE_VERSION_HEX >= ORACLE_VERSION(12,1)
+	sword rc;
+	ub4 rtype = 0;
+	*result = NULL;
+	rc = OCIStmtGetNextResult(stmtp, err, result, &rtype, OCI_DEFAULT);
+	*isSelect = rtype == OCI_RESULT_TYPE_SELECT;
+	return rc;
+#else
+	*result = NULL;
+	*isSelect = 0;
+	return OCI_NO_DATA;
+#endif
+}
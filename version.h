@@ -60,10 +60,49 @@
 	#define OCILOBWRITE                 OCILobWrite
 #endif
 
+// OCI_ATTR_STMT_IS_RETURNING is available since 12.1; 0 means unknown.
+#ifndef OCI_ATTR_STMT_IS_RETURNING
+	#define OCI_ATTR_STMT_IS_RETURNING  0
+#endif
+
+// OCI_ATTR_INSTNAME and OCI_ATTR_DBNAME are available since 11.2;
+// 0 means unknown.
+#ifndef OCI_ATTR_INSTNAME
+	#define OCI_ATTR_INSTNAME  0
+#endif
+#ifndef OCI_ATTR_DBNAME
+	#define OCI_ATTR_DBNAME  0
+#endif
+
+// OCI_ATTR_TRANSACTION_IN_PROGRESS is available since 12.1; 0 means unknown.
+#ifndef OCI_ATTR_TRANSACTION_IN_PROGRESS
+	#define OCI_ATTR_TRANSACTION_IN_PROGRESS  0
+#endif
+
+// The SQL domain and annotation describe attributes are available since 23;
+// 0 means unknown.
+#ifndef OCI_ATTR_DOMAIN_NAME
+	#define OCI_ATTR_DOMAIN_SCHEMA            0
+	#define OCI_ATTR_DOMAIN_NAME              0
+	#define OCI_ATTR_NUM_ANNOTATIONS          0
+	#define OCI_ATTR_LIST_ANNOTATIONS         0
+	#define OCI_ATTR_ANNOTATION_KEY           0
+	#define OCI_ATTR_ANNOTATION_VALUE         0
+#endif
+
+// OCI_ATTR_COL_PROPERTIES is available since 12.1; 0 means unknown.
+#ifndef OCI_ATTR_COL_PROPERTIES
+	#define OCI_ATTR_COL_PROPERTIES                      0
+	#define OCI_ATTR_COL_PROPERTY_IS_IDENTITY            0x01
+	#define OCI_ATTR_COL_PROPERTY_IS_GEN_ALWAYS          0x02
+	#define OCI_ATTR_COL_PROPERTY_IS_GEN_BY_DEF_ON_NULL  0x04
+#endif
+
 #define sof_DateTimep sizeof(OCIDateTime*)
 #define sof_Intervalp sizeof(OCIInterval*)
 #define sof_LobLocatorp sizeof(OCILobLocator*)
 #define sof_Stmtp sizeof(OCIStmt*)
+#define sof_Rowidp sizeof(OCIRowid*)
 
 sword
 bindByNameOrPos(
@@ -111,3 +150,36 @@ decriptorAllocSlice(
 	ub4 type,
 	size_t length
 );
+
+// shardingKeyAdd allocates a sharding key descriptor into *keyp,
+// adds the n columns to it, and sets it on the authInfo handle
+// as the (super, if isSuper is non-zero) sharding key.
+// Returns OCI_INVALID_HANDLE if the client does not support sharding.
+sword
+shardingKeyAdd(
+	OCIEnv *env,
+	OCIError *err,
+	void *authInfo,
+	int isSuper,
+	void **values,
+	ub4 *lengths,
+	ub2 *types,
+	ub4 n,
+	void **keyp
+);
+
+// shardingKeyFree frees the sharding key descriptor allocated by shardingKeyAdd.
+void
+shardingKeyFree(void *key);
+
+// nextImplicitResult returns the next implicit result (DBMS_SQL.RETURN_RESULT)
+// of the executed statement in *result, setting *isSelect to non-zero for
+// query results. Returns OCI_NO_DATA when there are no more results,
+// or if the client does not support implicit results.
+sword
+nextImplicitResult(
+	OCIStmt *stmtp,
+	OCIError *err,
+	void **result,
+	ub4 *isSelect
+);
@@ -206,6 +206,44 @@ func BenchmarkIter(b *testing.B) {
 	b.SetBytes(int64(i))
 }
 
+// go test -c && ./ora.v4.test -test.run=^$ -test.bench=ReuseRow -test.benchmem
+func BenchmarkIterReuseRow(b *testing.B) {
+	geoTableOnce.Do(func() {
+		if err := createGeoTable(); err != nil {
+			b.Fatal(err)
+		}
+	})
+	for _, reuse := range []bool{false, true} {
+		reuse := reuse
+		b.Run(fmt.Sprintf("reuse=%t", reuse), func(b *testing.B) {
+			stmt, err := testSes.Prep("SELECT location, device_name FROM " + geoTableName)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer stmt.Close()
+			cfg := stmt.Cfg()
+			cfg.ReuseRow = reuse
+			stmt.SetCfg(cfg)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; {
+				rset, err := stmt.Qry()
+				if err != nil {
+					b.Fatal(err)
+				}
+				for rset.Next() && i < b.N {
+					i++
+				}
+				if err := rset.Err(); err != nil {
+					b.Fatal(err)
+				}
+				// release the Rset of the stmt, even if it's not at its end
+				rset.Exhaust()
+			}
+		})
+	}
+}
+
 // BenchmarkMemory usage for querying rows.
 //
 // go test -c && ./ora.v4.test -test.run=^$ -test.bench=Memory -test.memprofilerate=1 -test.memprofile=/tmp/mem.prof && go tool pprof --alloc_space ora.v4.test /tmp/mem.prof
@@ -164,3 +164,33 @@ func TestSelectChar(t *testing.T) {
 		}
 	}
 }
+
+func TestBoolPtrSlice(t *testing.T) {
+	t.Parallel()
+	tableName, err := createTable(1, charB1Null, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	tr, fa := true, false
+	values := []*bool{&tr, nil, &fa}
+	_, err = testSes.PrepAndExe(fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName), values)
+	testErr(err, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("SELECT c1 FROM %v ORDER BY DECODE(c1, '1', 0, NULL, 1, 2)", tableName), ora.OraB)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	var got []*bool
+	if _, err = rset.FetchInto([]interface{}{&got}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(values) {
+		t.Fatalf("got %d rows, wanted %d", len(got), len(values))
+	}
+	for i, want := range values {
+		if (got[i] == nil) != (want == nil) || (want != nil && *got[i] != *want) {
+			t.Errorf("%d. got %v, wanted %v", i, got[i], want)
+		}
+	}
+}
@@ -186,3 +186,56 @@ func TestBindDefine_bytes_blob_size(t *testing.T) {
 		})
 	}
 }
+
+func TestRawGUIDString_session(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry(
+		"SELECT guid, RAWTOHEX(guid), guid, CAST(NULL AS RAW(16)) FROM (SELECT SYS_GUID() guid FROM DUAL)",
+		ora.S, ora.S, ora.Bin, ora.OraS)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	got, want := rset.Row[0].(string), rset.Row[1].(string)
+	if len(got) != 32 || got != want {
+		t.Errorf("got %q, wanted %q", got, want)
+	}
+	b, err := ora.ParseGUID(got)
+	testErr(err, t)
+	if raw := rset.Row[2].([]byte); string(b) != string(raw) {
+		t.Errorf("ParseGUID(%q) = %x, wanted %x", got, b, raw)
+	}
+	if null := rset.Row[3].(ora.String); !null.IsNull {
+		t.Errorf("NULL got %#v", null)
+	}
+	rset.Exhaust()
+}
+
+func TestBindSlice_bytesNull(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), r RAW(16), b BLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	ids := []int64{1, 2, 3}
+	raws := [][]byte{[]byte("a"), nil, []byte("c")}
+	blobs := []ora.BlobBytes{nil, ora.BlobBytes(gen_bytes(100000)), ora.BlobBytes("z")}
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, r, b) VALUES (:1, :2, :3)", ids, raws, blobs); err != nil {
+		t.Fatal(err)
+	}
+	rset, err := testSes.PrepAndQry("SELECT COUNT(r), COUNT(b), MAX(DBMS_LOB.GETLENGTH(b)) FROM "+tableName, ora.I64, ora.I64, ora.I64)
+	testErr(err, t)
+	if rset.Next() {
+		if got := [3]int64{rset.Row[0].(int64), rset.Row[1].(int64), rset.Row[2].(int64)}; got != [3]int64{2, 2, 100000} {
+			t.Errorf("got %v RAWs, BLOBs and maximal BLOB length", got)
+		}
+	}
+	testErr(rset.Err(), t)
+
+	// over the RAW bind limit
+	if _, err = testSes.PrepAndExe("INSERT INTO "+tableName+" (id, b) VALUES (:1, :2)", []int64{4}, [][]byte{gen_bytes(40000)}); err == nil {
+		t.Error("wanted error for a too long RAW")
+	}
+}
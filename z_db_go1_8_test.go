@@ -17,6 +17,8 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/pkg/errors"
+
+	ora "gopkg.in/rana/ora.v4"
 )
 
 func TestNamedArgs(t *testing.T) {
@@ -77,3 +79,58 @@ func TestRapidCancelIssue192(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestQueryContextPrefetch(t *testing.T) {
+	t.Parallel()
+	for _, prefetch := range []uint32{0, 1, 1000} {
+		ctx := ora.WithPrefetch(context.Background(), prefetch)
+		rows, err := testDb.QueryContext(ctx, "SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 10")
+		if err != nil {
+			t.Fatalf("%d: %v", prefetch, err)
+		}
+		var n int
+		for rows.Next() {
+			n++
+		}
+		if err = rows.Err(); err != nil {
+			t.Errorf("%d: %v", prefetch, err)
+		}
+		rows.Close()
+		if n != 10 {
+			t.Errorf("%d: got %d rows, wanted 10", prefetch, n)
+		}
+	}
+}
+
+func TestSesStartTxContext(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	defer srv.Close()
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if tx, err := ses.StartTxContext(ctx, ora.TxCfg{}); err != context.Canceled {
+		if tx != nil {
+			tx.Rollback()
+		}
+		t.Errorf("cancelled: got %v, wanted %v", err, context.Canceled)
+	}
+
+	tableName, err := createTable(1, numberP38S0, ses)
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	tx, err := ses.StartTxContext(context.Background(), ora.TxCfg{ReadOnly: true})
+	testErr(err, t)
+	defer tx.Rollback()
+	if _, err = ses.PrepAndExe("INSERT INTO " + tableName + " (c1) VALUES (1)"); err == nil {
+		t.Error("INSERT succeeded in a read-only transaction")
+	}
+}
@@ -6,6 +6,7 @@ package ora_test
 
 import (
 	"testing"
+	"time"
 
 	"gopkg.in/rana/ora.v4"
 )
@@ -61,6 +62,54 @@ func TestEnv_OpenCloseServer(t *testing.T) {
 	testErr(err, t)
 }
 
+func TestEnv_OpenSrv_ConnectTimeout(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	// Close waits for the abandoned attach, which gives up after the
+	// CONNECT_TIMEOUT of the descriptor
+	defer func() {
+		testErr(env.Close(), t)
+	}()
+
+	cfg := testSrvCfg
+	cfg.Pool = ora.PoolCfg{}
+	// not routed, so the attach hangs
+	cfg.Dblink = "(DESCRIPTION=(CONNECT_TIMEOUT=10)(ADDRESS=(PROTOCOL=TCP)(HOST=10.255.255.1)(PORT=1521))(CONNECT_DATA=(SERVICE_NAME=unreachable)))"
+	cfg.ConnectTimeout = time.Second
+	start := time.Now()
+	srv, err := env.OpenSrv(cfg)
+	if err == nil {
+		srv.Close()
+		t.Fatal("wanted error")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("OpenSrv returned after %s", d)
+	}
+	cte, ok := err.(*ora.ConnectTimeoutError)
+	if !ok {
+		// the network may refuse the connection before the timeout
+		t.Skipf("got %v (%T)", err, err)
+	}
+	if cte.After != cfg.ConnectTimeout || !cte.Timeout() {
+		t.Errorf("got After=%s Timeout()=%t, wanted %s and true", cte.After, cte.Timeout(), cfg.ConnectTimeout)
+	}
+}
+
+func TestEnv_OpenSrv_ServerGroupPool(t *testing.T) {
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+
+	cfg := testSrvCfg
+	cfg.ServerGroup = "grp"
+	cfg.Pool = ora.PoolCfg{Type: ora.SPool, Min: 1, Max: 2, Incr: 1}
+	if srv, err := env.OpenSrv(cfg); err == nil {
+		srv.Close()
+		t.Error("ServerGroup has been accepted with a session pool")
+	}
+}
+
 func TestEnv_OpenCloseCon(t *testing.T) {
 	t.Parallel()
 	env, err := ora.OpenEnv()
@@ -115,3 +115,43 @@ func TestBindDefine_OraIntervalDS_ShiftTime_session(t *testing.T) {
 		t.Fatalf("expected(%v), actual(%v)", expected, actual)
 	}
 }
+
+func TestDefine_IntervalDur(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry(
+		"SELECT INTERVAL '1 02:03:04.5' DAY TO SECOND, -INTERVAL '0 00:00:01' DAY TO SECOND, CAST(NULL AS INTERVAL DAY TO SECOND), INTERVAL '2-3' YEAR TO MONTH, -INTERVAL '0-5' YEAR TO MONTH, CAST(NULL AS INTERVAL YEAR TO MONTH) FROM DUAL",
+		ora.Dur, ora.Dur, ora.Dur, ora.Months, ora.Months, ora.Months)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	want := []interface{}{
+		26*time.Hour + 3*time.Minute + 4500*time.Millisecond, -time.Second, nil,
+		27, -5, nil,
+	}
+	for i, w := range want {
+		if rset.Row[i] != w {
+			t.Errorf("%d. got %#v, wanted %#v", i, rset.Row[i], w)
+		}
+	}
+	rset.Exhaust()
+
+	if _, err = testSes.PrepAndQry("SELECT INTERVAL '1' DAY FROM DUAL", ora.Months); err == nil {
+		t.Error("Months accepted for INTERVAL DAY TO SECOND")
+	}
+}
+
+func TestIntervalDuration(t *testing.T) {
+	t.Parallel()
+	d, err := ora.IntervalDS{Day: -1, Hour: -2, Nanosecond: -3}.Duration()
+	testErr(err, t)
+	if want := -(26*time.Hour + 3); d != want {
+		t.Errorf("got %v, wanted %v", d, want)
+	}
+	if _, err = (ora.IntervalDS{Day: 200000}).Duration(); err == nil {
+		t.Error("200000 days did not overflow")
+	}
+	if m := (ora.IntervalYM{Year: -1, Month: -2}).Months(); m != -14 {
+		t.Errorf("got %d months, wanted -14", m)
+	}
+}
@@ -6,11 +6,13 @@ package ora_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
 	"runtime"
 	"strings"
 	"sync"
@@ -223,6 +225,42 @@ END test_get_json;`,
 	}
 }
 
+func TestLobPtrSlice(t *testing.T) {
+	for _, qry := range []string{
+		`CREATE OR REPLACE PACKAGE test_lob_ptrs AS
+  TYPE blob_tab_typ IS TABLE OF BLOB INDEX BY PLS_INTEGER;
+  PROCEDURE get_lobs(p_lobs OUT blob_tab_typ);
+END test_lob_ptrs;`,
+		`CREATE OR REPLACE PACKAGE BODY test_lob_ptrs AS
+  PROCEDURE get_lobs(p_lobs OUT blob_tab_typ) IS
+  BEGIN
+    FOR i IN 1..2 LOOP
+      DBMS_LOB.createtemporary(p_lobs(i), TRUE);
+      DBMS_LOB.writeappend(p_lobs(i), i, UTL_RAW.copies(HEXTORAW('41'), i));
+    END LOOP;
+  END get_lobs;
+END test_lob_ptrs;`,
+	} {
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Skipf("create package: %v", err)
+		}
+	}
+	lobs := []*ora.Lob{{}, {}}
+	if _, err := testSes.PrepAndExeP("BEGIN test_lob_ptrs.get_lobs(:1); END;", lobs); err != nil {
+		t.Fatal(err)
+	}
+	for i, lob := range lobs {
+		b, err := ioutil.ReadAll(lob)
+		lob.Close()
+		if err != nil {
+			t.Fatalf("%d. %v", i, err)
+		}
+		if want := strings.Repeat("A", i+1); string(b) != want {
+			t.Errorf("%d. got %q, wanted %q.", i, b, want)
+		}
+	}
+}
+
 func TestLobIssue156(t *testing.T) {
 	tbl := tableName()
 	qry := `CREATE TABLE ` + tbl + `
@@ -786,3 +824,485 @@ func reverseString(s string) string {
 	}
 	return string(runes)
 }
+
+func TestLobTrimErase(t *testing.T) {
+	stmt, err := testSes.Prep(`DECLARE
+  v_blob BLOB;
+BEGIN
+  DBMS_LOB.createtemporary(v_blob, TRUE);
+  DBMS_LOB.writeappend(v_blob, 10, UTL_RAW.cast_to_raw('0123456789'));
+  :1 := v_blob;
+END;`, ora.OraBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	lob := &ora.Lob{}
+	if _, err = stmt.Exe(lob); err != nil {
+		t.Fatal(err)
+	}
+	defer lob.Close()
+	if err = lob.Erase(2, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err = lob.Trim(8); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(lob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "01\x00\x00\x00567"; string(b) != want {
+		t.Errorf("got %q, wanted %q.", b, want)
+	}
+
+	if err = (&ora.Lob{Reader: strings.NewReader("x")}).Trim(0); err == nil {
+		t.Error("Trim of a non-database Lob succeeded")
+	}
+}
+
+func TestLobAppend(t *testing.T) {
+	stmt, err := testSes.Prep(`DECLARE
+  v_blob BLOB;
+BEGIN
+  DBMS_LOB.createtemporary(v_blob, TRUE);
+  DBMS_LOB.writeappend(v_blob, 3, UTL_RAW.cast_to_raw('abc'));
+  :1 := v_blob;
+END;`, ora.OraBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	lob := &ora.Lob{}
+	if _, err = stmt.Exe(lob); err != nil {
+		t.Fatal(err)
+	}
+	defer lob.Close()
+	for _, s := range []string{"def", "", "ghij"} {
+		n, err := lob.Append([]byte(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(s) {
+			t.Errorf("%q: appended %d bytes, wanted %d", s, n, len(s))
+		}
+	}
+	b, err := ioutil.ReadAll(lob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "abcdefghij"; string(b) != want {
+		t.Errorf("got %q, wanted %q.", b, want)
+	}
+
+	if _, err = (&ora.Lob{Reader: strings.NewReader("x")}).Append([]byte("y")); err == nil {
+		t.Error("Append to a non-database Lob succeeded")
+	}
+}
+
+func TestLobLength(t *testing.T) {
+	stmt, err := testSes.Prep("SELECT TO_CLOB('árvíz'), TO_BLOB(HEXTORAW('00010203')) FROM DUAL", ora.L, ora.L)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rset.Next() {
+		for i, want := range []int64{5, 4} { // characters for CLOB, bytes for BLOB
+			lob := rset.Row[i].(*ora.Lob)
+			n, err := lob.Length()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != want {
+				t.Errorf("%d. got %d, wanted %d", i, n, want)
+			}
+			if _, err = ioutil.ReadAll(lob); err != nil {
+				t.Fatal(err)
+			}
+			if n, err = lob.Length(); err != nil || n != want {
+				t.Errorf("%d. after reading got %d (%v), wanted %d", i, n, err, want)
+			}
+			lob.Close()
+		}
+	}
+	if err = rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLobChunkSize(t *testing.T) {
+	rset, err := testSes.PrepAndQry("SELECT TO_BLOB(HEXTORAW('00010203')) FROM DUAL")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	lob := rset.Row[0].(*ora.Lob)
+	defer lob.Close()
+	n := lob.ChunkSize()
+	if n <= 0 {
+		t.Errorf("got chunk size %d", n)
+	}
+	// the reads are aligned to it
+	b, err := ioutil.ReadAll(lob)
+	testErr(err, t)
+	if want := []byte{0, 1, 2, 3}; !bytes.Equal(b, want) {
+		t.Errorf("got %v, wanted %v", b, want)
+	}
+	rset.Exhaust()
+
+	if n = (&ora.Lob{Reader: strings.NewReader("x")}).ChunkSize(); n != 0 {
+		t.Errorf("non-database Lob has chunk size %d", n)
+	}
+}
+
+func TestLobOpenClose(t *testing.T) {
+	stmt, err := testSes.Prep(`DECLARE
+  v_blob BLOB;
+BEGIN
+  DBMS_LOB.createtemporary(v_blob, TRUE);
+  DBMS_LOB.writeappend(v_blob, 10, UTL_RAW.cast_to_raw('0123456789'));
+  :1 := v_blob;
+END;`, ora.OraBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	lob := &ora.Lob{}
+	if _, err = stmt.Exe(lob); err != nil {
+		t.Fatal(err)
+	}
+	defer lob.Close()
+	if err = lob.Open(ora.LobReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	if err = lob.Open(ora.LobReadWrite); err == nil {
+		t.Error("double Open succeeded")
+	}
+	for i := int64(0); i < 3; i++ {
+		if err = lob.Erase(i, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = lob.Trim(5); err != nil {
+		t.Fatal(err)
+	}
+	if err = lob.CloseLob(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(lob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "\x00\x00\x0034"; string(b) != want {
+		t.Errorf("got %q, wanted %q.", b, want)
+	}
+}
+
+func TestLobOpenAfterRead(t *testing.T) {
+	rset, err := testSes.PrepAndQry("SELECT TO_BLOB(HEXTORAW('00010203')) FROM DUAL")
+	testErr(err, t)
+	defer rset.Exhaust()
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	lob := rset.Row[0].(*ora.Lob)
+	defer lob.Close()
+	var b [1]byte
+	if _, err = lob.Read(b[:]); err != nil { // opens the LOB implicitly
+		t.Fatal(err)
+	}
+	if err = lob.Open(ora.LobReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err = lob.CloseLob(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := lob.Length(); err != nil || n != 4 {
+		t.Errorf("got length %d (%v), wanted 4", n, err)
+	}
+}
+
+func TestSesCopyLob(t *testing.T) {
+	stmt, err := testSes.Prep(`DECLARE
+  v_src BLOB;
+  v_dst BLOB;
+BEGIN
+  DBMS_LOB.createtemporary(v_src, TRUE);
+  DBMS_LOB.writeappend(v_src, 10, UTL_RAW.cast_to_raw('0123456789'));
+  DBMS_LOB.createtemporary(v_dst, TRUE);
+  DBMS_LOB.writeappend(v_dst, 4, UTL_RAW.cast_to_raw('abcd'));
+  :1 := v_src;
+  :2 := v_dst;
+END;`, ora.OraBin, ora.OraBin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	src, dst := &ora.Lob{}, &ora.Lob{}
+	if _, err = stmt.Exe(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	defer dst.Close()
+	if err = testSes.CopyLob(dst, src, 3, 2, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err = testSes.CopyLob(dst, src, 0, 5, 8); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "ab56789"; string(b) != want {
+		t.Errorf("got %q, wanted %q.", b, want)
+	}
+
+	if err = testSes.CopyLob(dst, &ora.Lob{Reader: strings.NewReader("x")}, 1, 0, 0); err == nil {
+		t.Error("CopyLob from a non-database Lob succeeded")
+	}
+}
+
+func TestLobPrefetch(t *testing.T) {
+	t.Parallel()
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), c CLOB, b BLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tbl, testSes, t)
+
+	// both shorter and longer than the default LobPrefetchSize
+	lengths := []int{0, 1, 3999, 4000, 4001, 100000}
+	for i, n := range lengths {
+		s := strings.Repeat("0123456789", n/10+1)[:n]
+		if _, err := testSes.PrepAndExe(
+			"INSERT INTO "+tbl+" (id, c, b) VALUES (:1, :2, :3)",
+			i, &ora.Lob{Reader: strings.NewReader(s), C: true}, &ora.Lob{Reader: strings.NewReader(s)},
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, size := range []uint32{0, 4000} {
+		stmt, err := testSes.Prep("SELECT id, c, b FROM "+tbl+" ORDER BY id", ora.I64, ora.S, ora.Bin)
+		testErr(err, t)
+		cfg := stmt.Cfg()
+		cfg.LobPrefetchSize = size
+		stmt.SetCfg(cfg)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		for rset.Next() {
+			i := int(rset.Row[0].(int64))
+			n := lengths[i]
+			want := strings.Repeat("0123456789", n/10+1)[:n]
+			if got := rset.Row[1].(string); got != want {
+				t.Errorf("%d. CLOB (prefetch=%d): got %d bytes, wanted %d.", i, size, len(got), len(want))
+			}
+			if got, _ := rset.Row[2].([]byte); string(got) != want {
+				t.Errorf("%d. BLOB (prefetch=%d): got %d bytes, wanted %d.", i, size, len(got), len(want))
+			}
+		}
+		testErr(rset.Err(), t)
+		stmt.Close()
+	}
+}
+
+func TestNClob(t *testing.T) {
+	t.Parallel()
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), nc NCLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tbl, testSes, t)
+
+	texts := []string{
+		"árvíztűrő tükörfúrógép",
+		"日本語のテキスト",
+		strings.Repeat("őű€日", 3000), // longer than the LOB prefetch size
+	}
+	for i, txt := range texts {
+		if _, err := testSes.PrepAndExe(
+			"INSERT INTO "+tbl+" (id, nc) VALUES (:1, :2)",
+			i, &ora.Lob{Reader: strings.NewReader(txt), C: true},
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT id, nc FROM "+tbl+" ORDER BY id", ora.I64, ora.S)
+	testErr(err, t)
+	for rset.Next() {
+		i := int(rset.Row[0].(int64))
+		if got := rset.Row[1].(string); got != texts[i] {
+			t.Errorf("%d. got %q (%d bytes), wanted %q (%d bytes).", i, got, len(got), texts[i], len(texts[i]))
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestTempLobCache(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep("BEGIN :1 := DBMS_LOB.GETLENGTH(:2); END;")
+	testErr(err, t)
+	defer stmt.Close()
+	data := bytes.Repeat([]byte("ora"), 10000)
+	for _, cache := range []bool{true, false} {
+		cfg := stmt.Cfg()
+		cfg.TempLobCache = cache
+		stmt.SetCfg(cfg)
+		var n int64
+		if _, err = stmt.Exe(&n, ora.Lob{Reader: bytes.NewReader(data)}); err != nil {
+			t.Fatalf("cache=%t: %v", cache, err)
+		}
+		if n != int64(len(data)) {
+			t.Errorf("cache=%t: got length %d, wanted %d", cache, n, len(data))
+		}
+	}
+}
+
+func TestClobCharset(t *testing.T) {
+	t.Parallel()
+	var dbCharset string
+	rset, err := testSes.PrepAndQry("SELECT value FROM nls_database_parameters WHERE parameter = 'NLS_CHARACTERSET'")
+	testErr(err, t)
+	for rset.Next() {
+		dbCharset = rset.Row[0].(string)
+	}
+	testErr(rset.Err(), t)
+	t.Logf("database character set: %s", dbCharset) // i.e. WE8MSWIN1252
+
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), c CLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tbl, testSes, t)
+
+	const want = "Größe café €"
+	for i, lob := range []*ora.Lob{
+		{Reader: strings.NewReader(want), C: true},
+		{Reader: strings.NewReader(want), C: true, Charset: "AL32UTF8"},
+		{Reader: strings.NewReader("Gr\xf6\xdfe caf\xe9 \x80"), C: true, Charset: "WE8MSWIN1252"},
+	} {
+		if _, err := testSes.PrepAndExe("INSERT INTO "+tbl+" (id, c) VALUES (:1, :2)", i, lob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := testSes.PrepAndExe(
+		"INSERT INTO "+tbl+" (id, c) VALUES (:1, :2)",
+		9, &ora.Lob{Reader: strings.NewReader(want), C: true, Charset: "NO_SUCH_CHARSET"},
+	); err == nil {
+		t.Error("wanted error for unknown character set")
+	}
+
+	rset, err = testSes.PrepAndQry("SELECT id, c FROM "+tbl+" ORDER BY id", ora.I64, ora.S)
+	testErr(err, t)
+	for rset.Next() {
+		if got := rset.Row[1].(string); got != want {
+			t.Errorf("%d. got %q, wanted %q.", rset.Row[0], got, want)
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestClobSlice(t *testing.T) {
+	t.Parallel()
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), c CLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tbl, testSes, t)
+
+	const want = "Größe café €"
+	qry := "INSERT INTO " + tbl + " (id, c) VALUES (:1, :2)"
+	if _, err := testSes.PrepAndExe(qry, []int64{1, 2}, []*ora.Lob{
+		{Reader: strings.NewReader("Gr\xf6\xdfe caf\xe9 \x80"), C: true, Charset: "WE8MSWIN1252"},
+		{Reader: strings.NewReader("Gr\xf6\xdfe caf\xe9 \x80"), C: true, Charset: "WE8MSWIN1252"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for i, lobs := range [][]*ora.Lob{
+		{{Reader: strings.NewReader(want), C: true}, {Reader: strings.NewReader(want)}},
+		{{Reader: strings.NewReader(want), C: true}, {Reader: strings.NewReader(want), C: true, Charset: "WE8MSWIN1252"}},
+	} {
+		if _, err := testSes.PrepAndExe(qry, []int64{8, 9}, lobs); err == nil {
+			t.Errorf("%d. wanted error for a mixed slice", i)
+		}
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT id, c FROM "+tbl+" ORDER BY id", ora.I64, ora.S)
+	testErr(err, t)
+	var n int
+	for rset.Next() {
+		n++
+		if got := rset.Row[1].(string); got != want {
+			t.Errorf("%d. got %q, wanted %q.", rset.Row[0], got, want)
+		}
+	}
+	testErr(rset.Err(), t)
+	if n != 2 {
+		t.Errorf("got %d rows, wanted 2", n)
+	}
+}
+
+type cancelReaderAt struct {
+	io.ReaderAt
+	cancel func()
+}
+
+func (r cancelReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	r.cancel()
+	return r.ReaderAt.ReadAt(p, off)
+}
+
+func TestFileBlob(t *testing.T) {
+	t.Parallel()
+	tbl := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tbl + " (id NUMBER(3), b BLOB)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tbl, testSes, t)
+
+	fh, err := ioutil.TempFile("", "ora-fileblob-")
+	testErr(err, t)
+	defer os.Remove(fh.Name())
+	defer fh.Close()
+	want := bytes.Repeat([]byte("0123456789abcdef"), 3<<20/16) // more chunks
+	_, err = fh.Write(want)
+	testErr(err, t)
+
+	if _, err = testSes.PrepAndExe(
+		"INSERT INTO "+tbl+" (id, b) VALUES (1, :1)",
+		ora.FileBlob{R: fh, Size: int64(len(want))},
+	); err != nil {
+		t.Fatal(err)
+	}
+	rset, err := testSes.PrepAndQry("SELECT b FROM "+tbl+" WHERE id = 1", ora.Bin)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if got := rset.Row[0].([]byte); !bytes.Equal(got, want) {
+		t.Errorf("got %d bytes, wanted %d", len(got), len(want))
+	}
+	rset.Exhaust()
+
+	// cancel in the middle of streaming
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stmt, err := testSes.Prep("INSERT INTO " + tbl + " (id, b) VALUES (2, :1)")
+	testErr(err, t)
+	defer stmt.Close()
+	if _, err = stmt.ExeContext(ctx, ora.FileBlob{R: cancelReaderAt{ReaderAt: fh, cancel: cancel}, Size: int64(len(want))}); err == nil {
+		t.Error("streaming hasn't been cancelled")
+	} else if !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("got %v, wanted %v", err, context.Canceled)
+	}
+}
@@ -6,6 +6,7 @@ package ora_test
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -262,3 +263,136 @@ func TestIssue144(t *testing.T) {
 		t.Logf("%d. %T: %v", tN, dest, reflect.ValueOf(dest).Elem().Interface())
 	}
 }
+
+func TestBindNonFinite(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), bd BINARY_DOUBLE, bf BINARY_FLOAT, num NUMBER)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	doubles := []float64{math.Inf(1), math.Inf(-1), math.NaN(), 1.5}
+	for i, d := range doubles {
+		if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, bd, bf) VALUES (:1, :2, :3)",
+			int64(i), d, float32(d),
+		); err != nil {
+			t.Fatalf("%d. %v: %v", i, d, err)
+		}
+	}
+	ids := []int64{10, 11, 12, 13}
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, bd) VALUES (:1, :2)", ids, doubles); err != nil {
+		t.Fatal(err)
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT id, bd, bf FROM " + tableName + " ORDER BY id")
+	testErr(err, t)
+	for rset.Next() {
+		id := rset.Row[0].(int64)
+		want := doubles[id%10]
+		got := rset.Row[1].(float64)
+		if !(got == want || math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("%d. BINARY_DOUBLE got %v, wanted %v", id, got, want)
+		}
+		if id >= 10 {
+			continue
+		}
+		gotF := rset.Row[2].(float32)
+		if !(float64(gotF) == want || math.IsNaN(float64(gotF)) && math.IsNaN(want)) {
+			t.Errorf("%d. BINARY_FLOAT got %v, wanted %v", id, gotF, want)
+		}
+	}
+	testErr(rset.Err(), t)
+
+	// NUMBER can't hold Inf
+	if _, err = testSes.PrepAndExe("INSERT INTO "+tableName+" (id, num) VALUES (:1, :2)", int64(20), math.Inf(1)); err == nil {
+		t.Error("inserting +Inf into a NUMBER succeeded")
+	}
+}
+
+func TestBindInterfaceSlice(t *testing.T) {
+	t.Parallel()
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	qry := fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName)
+	rowsAffected, err := testSes.PrepAndExe(qry, []interface{}{int64(1), int64(2), int64(3)})
+	testErr(err, t)
+	if rowsAffected != 3 {
+		t.Errorf("got %d rows affected, wanted 3", rowsAffected)
+	}
+	if _, err = testSes.PrepAndExe(qry, []interface{}{int64(4), "5"}); err == nil {
+		t.Error("heterogeneous []interface{} accepted")
+	}
+}
+
+func TestFloatColumn(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (f FLOAT, f10 FLOAT(10))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("INSERT INTO %v (f, f10) VALUES (:1, :2)", tableName), 0.1, float64(12.5))
+	testErr(err, t)
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT f, f10 FROM %v", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		for i, want := range []float64{0.1, 12.5} {
+			if got, ok := rset.Row[i].(float64); !ok || got != want {
+				t.Errorf("%d. got %#v, wanted %v", i, rset.Row[i], want)
+			}
+		}
+	}
+	testErr(rset.Err(), t)
+
+	// the digits as a string
+	stmt, err := testSes.Prep(fmt.Sprintf("SELECT f FROM %v", tableName), ora.N)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err = stmt.Qry()
+	testErr(err, t)
+	for rset.Next() {
+		if got, ok := rset.Row[0].(ora.Num); !ok || got != "0.1" && got != ".1" {
+			t.Errorf("got %#v, wanted 0.1", rset.Row[0])
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestNumberSmartString(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (i NUMBER(18), f NUMBER(10,2), n NUMBER, b NUMBER(30))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("INSERT INTO %v (i, f, n, b) VALUES (123456789012345678, 12.25, 0.1234567890123456789, 123456789012345678901234567890)", tableName))
+	testErr(err, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("SELECT i, f, n, b FROM %v", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	cfg := stmt.Cfg()
+	cfg.NumberSmartString = true
+	stmt.SetCfg(cfg)
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	for i, want := range []interface{}{
+		int64(123456789012345678),
+		float64(12.25),
+		ora.Num(".1234567890123456789"),
+		ora.Num("123456789012345678901234567890"),
+	} {
+		got := rset.Row[i]
+		if num, ok := got.(ora.Num); ok && strings.HasPrefix(string(num), "0.") {
+			got = ora.Num(num[1:])
+		}
+		if got != want {
+			t.Errorf("%d. got %#v, wanted %#v", i, rset.Row[i], want)
+		}
+	}
+}
@@ -800,6 +800,33 @@ func dropTable(tableName string, ses *ora.Ses, t testing.TB) {
 	testErr(errors.Wrap(err, qry), t)
 }
 
+// parseCounter returns a function returning the "parse count (total)" of ses,
+// read with testSes from v$sesstat. A statement cache hit needs no parse call.
+// The test is skipped if v$sesstat is not accessible.
+func parseCounter(ses *ora.Ses, t *testing.T) func() int64 {
+	rset, err := ses.PrepAndQry("SELECT SYS_CONTEXT('USERENV', 'SID') FROM DUAL")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	sid := rset.Row[0]
+	rset.Exhaust()
+	return func() int64 {
+		rset, err := testSes.PrepAndQry(`SELECT s.value FROM v$sesstat s, v$statname n
+  WHERE s.statistic# = n.statistic# AND n.name = 'parse count (total)' AND s.sid = :1`, sid)
+		if err != nil {
+			t.Skip(err)
+		}
+		if !rset.Next() {
+			t.Fatal(rset.Err())
+		}
+		var n int64
+		fmt.Sscan(fmt.Sprint(rset.Row[0]), &n)
+		rset.Exhaust()
+		return n
+	}
+}
+
 func createTableDB(db *sql.DB, t *testing.T, octs ...oracleColumnType) string {
 	tableName := tableName()
 	qry := createTableSql(tableName, 1, octs...)
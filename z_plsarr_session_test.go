@@ -123,6 +123,49 @@ END TST_ora_plsarr_num;`,
 	}
 }
 
+func Test_plsarr_inout_session(t *testing.T) {
+	t.Parallel()
+	for _, qry := range []string{
+		`CREATE OR REPLACE PACKAGE TST_ora_plsarr_inout AS
+  TYPE pls_tab_typ IS TABLE OF NUMBER INDEX BY PLS_INTEGER;
+  PROCEDURE double(p_nums IN OUT pls_tab_typ);
+END TST_ora_plsarr_inout;`,
+		`CREATE OR REPLACE PACKAGE BODY TST_ora_plsarr_inout AS
+  PROCEDURE double(p_nums IN OUT pls_tab_typ) IS
+    i PLS_INTEGER;
+  BEGIN
+    i := p_nums.FIRST;
+    WHILE i IS NOT NULL LOOP
+      p_nums(i) := 2 * p_nums(i);
+      i := p_nums.NEXT(i);
+    END LOOP;
+  END double;
+END TST_ora_plsarr_inout;`,
+	} {
+		if _, err := testSes.PrepAndExe(qry); err != nil {
+			t.Fatal(err)
+		}
+		checkCompile(t, testSes)
+	}
+
+	const qry = "BEGIN TST_ora_plsarr_inout.double(:1); END;"
+	nums := []int64{1, 2, 3}
+	if _, err := testSes.PrepAndExeP(qry, ora.InOut{Slice: nums}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{2, 4, 6}; !reflect.DeepEqual(nums, want) {
+		t.Errorf("got %v, wanted %v", nums, want)
+	}
+
+	fnums := []float64{0.5, 1.5}
+	if _, err := testSes.PrepAndExeP(qry, ora.InOut{Slice: &fnums}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{1, 3}; !reflect.DeepEqual(fnums, want) {
+		t.Errorf("got %v, wanted %v", fnums, want)
+	}
+}
+
 func Test_plsarr_dt_session(t *testing.T) {
 	t.Parallel()
 	for _, qry := range []string{
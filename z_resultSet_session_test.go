@@ -5,7 +5,9 @@
 package ora_test
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"gopkg.in/rana/ora.v4"
@@ -102,3 +104,506 @@ END;`)
 	for rset.Next() {
 	}
 }
+
+func TestRsetCursorColumn(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep(`SELECT LEVEL id,
+    CURSOR(SELECT LEVEL * 10 FROM DUAL CONNECT BY LEVEL <= 2) det
+  FROM DUAL CONNECT BY LEVEL <= 3`)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	var details []*ora.Rset
+	var got []string
+	for rset.Next() {
+		detail := rset.Row[1].(*ora.Rset)
+		details = append(details, detail)
+		for detail.Next() {
+			got = append(got, fmt.Sprintf("%v:%v", rset.Row[0], detail.Row[0]))
+		}
+		testErr(detail.Err(), t)
+	}
+	testErr(rset.Err(), t)
+	if s, want := strings.Join(got, " "), "1:10 1:20 2:10 2:20 3:10 3:20"; s != want {
+		t.Errorf("got %q, wanted %q", s, want)
+	}
+	if err = stmt.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i, detail := range details {
+		if detail.IsOpen() {
+			t.Errorf("%d. nested Rset is open after closing the parent", i)
+		}
+	}
+}
+
+func TestRsetColumnIdentity(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe(createTableSql(tableName, 1, numberP38S0Identity, varchar2C48)); err != nil {
+		t.Skipf("SKIP create table with identity: %v", err)
+	}
+	defer dropTable(tableName, testSes, t)
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT c1, c2 FROM %v", tableName))
+	testErr(err, t)
+	defer rset.Exhaust()
+	if !rset.Columns[0].Identity || !rset.Columns[0].GeneratedAlways {
+		t.Errorf("c1 is not an identity column: %#v", rset.Columns[0])
+	}
+	if rset.Columns[1].Identity {
+		t.Errorf("c2 is an identity column: %#v", rset.Columns[1])
+	}
+}
+
+func TestRsetProject(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry("SELECT 'a' c1, CAST(2 AS INTEGER) c2, 'c' c3, SYSDATE c4 FROM DUAL")
+	testErr(err, t)
+	if err = rset.Project([]int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if len(rset.Row) != 4 {
+		t.Fatalf("column count: wanted 4, got %d", len(rset.Row))
+	}
+	if rset.Row[0] != nil || rset.Row[3] != nil {
+		t.Errorf("skipped columns are not nil: %#v", rset.Row)
+	}
+	compare(int64(2), rset.Row[1], ora.I64, t)
+	compare("c", rset.Row[2], ora.S, t)
+	if err = rset.Project([]int{0}); err == nil {
+		t.Error("Project after Next succeeded")
+	}
+	rset.Exhaust()
+}
+
+func TestRsetDefineByName(t *testing.T) {
+	t.Parallel()
+	mapping := map[string]ora.GoColumnType{"num": ora.OraI64, "TXT": ora.OraS}
+	for _, qry := range []string{
+		"SELECT 42 num, 'x' txt FROM DUAL",
+		"SELECT 'x' txt, 42 num FROM DUAL",
+	} {
+		rset, err := testSes.PrepAndQry(qry)
+		testErr(err, t)
+		if err = rset.DefineByName(mapping); err != nil {
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			t.Fatal(rset.Err())
+		}
+		m := rset.RowMap()
+		if got, want := m["NUM"], (ora.Int64{Value: 42}); got != want {
+			t.Errorf("%s: NUM got %#v, wanted %#v", qry, got, want)
+		}
+		if got, want := m["TXT"], (ora.String{Value: "x"}); got != want {
+			t.Errorf("%s: TXT got %#v, wanted %#v", qry, got, want)
+		}
+		rset.Exhaust()
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT 1 num FROM DUAL")
+	testErr(err, t)
+	if err = rset.DefineByName(map[string]ora.GoColumnType{"nonexistent": ora.S}); err == nil {
+		t.Error("DefineByName with unknown column succeeded")
+	}
+	rset.Exhaust()
+}
+
+func TestRsetFetchInto(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry("SELECT LEVEL, TO_CHAR(LEVEL) FROM DUAL CONNECT BY LEVEL <= 10")
+	testErr(err, t)
+	var ints []int
+	var strs []string
+	n, err := rset.FetchInto([]interface{}{&ints, &strs}, 7)
+	testErr(err, t)
+	if n != 7 || len(ints) != 7 || len(strs) != 7 {
+		t.Fatalf("wanted 7 rows, got %d (%v, %v)", n, ints, strs)
+	}
+	if n, err = rset.FetchInto([]interface{}{&ints, &strs}, 7); err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("wanted the remaining 3 rows, got %d", n)
+	}
+	for i := range ints {
+		if ints[i] != i+1 || strs[i] != fmt.Sprintf("%d", i+1) {
+			t.Errorf("%d. got %d, %q", i, ints[i], strs[i])
+		}
+	}
+}
+
+func TestRsetPrefetchedRows(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= %d", ora.MaxFetchLen+10))
+	testErr(err, t)
+	if n := rset.PrefetchedRows(); n != 0 {
+		t.Errorf("before Next: got %d, wanted 0", n)
+	}
+	for i := 1; rset.Next(); i++ {
+		want := ora.MaxFetchLen - i
+		if i > ora.MaxFetchLen {
+			want = ora.MaxFetchLen + 10 - i
+		}
+		if n := rset.PrefetchedRows(); n != want {
+			t.Errorf("%d. got %d, wanted %d", i, n, want)
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+// csvEncoder is a RowEncoder encoding the rows as comma separated values.
+type csvEncoder struct{}
+
+func (csvEncoder) EncodeRow(cols []ora.Column, row []interface{}) ([]byte, error) {
+	parts := make([]string, len(row))
+	for i, v := range row {
+		parts[i] = fmt.Sprintf("%s=%v", cols[i].Name, v)
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+func TestRsetStreamEncoded(t *testing.T) {
+	t.Parallel()
+	const n = ora.MaxFetchLen*2 + 10
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT LEVEL AS l, 'x' AS s FROM DUAL CONNECT BY LEVEL <= %d", n), ora.I64, ora.S)
+	testErr(err, t)
+	rows, errc := rset.StreamEncoded(context.Background(), csvEncoder{})
+	var i int
+	for b := range rows {
+		i++
+		if want := fmt.Sprintf("L=%d,S=x", i); string(b) != want {
+			t.Errorf("%d. got %q, wanted %q", i, b, want)
+		}
+	}
+	testErr(<-errc, t)
+	if i != n {
+		t.Errorf("got %d rows, wanted %d", i, n)
+	}
+
+	// cancel in the middle
+	rset, err = testSes.PrepAndQry(fmt.Sprintf("SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= %d", n))
+	testErr(err, t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rows, errc = rset.StreamEncoded(ctx, csvEncoder{})
+	<-rows
+	cancel()
+	for range rows {
+	}
+	if err = <-errc; err != context.Canceled {
+		t.Errorf("got %v, wanted %v", err, context.Canceled)
+	}
+}
+
+func TestRsetScan(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep("SELECT CAST(NULL AS NUMBER(10)), CAST(7 AS NUMBER(10)) FROM DUAL", ora.OraI64, ora.OraI64)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	var n ora.Int64
+	var p *int64
+	testErr(rset.Scan(&n, &p), t)
+	if !n.IsNull || p == nil || *p != 7 {
+		t.Errorf("got %v and %v, wanted NULL and 7", n, p)
+	}
+
+	var i, j int64
+	err = rset.Scan(&i, &j)
+	if nerr, ok := err.(*ora.ErrNullValue); !ok {
+		t.Errorf("got %v, wanted ErrNullValue", err)
+	} else if nerr.Index != 0 {
+		t.Errorf("got column %d, wanted 0", nerr.Index)
+	}
+}
+
+func TestRsetUpdateByRowid(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	tableName := tableName()
+	_, err = ses.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), name VARCHAR2(10))")
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+	_, err = ses.PrepAndExe("INSERT INTO "+tableName+" (id, name) VALUES (:1, :2)",
+		[]int64{1, 2, 3}, []string{"a", "b", "c"})
+	testErr(err, t)
+
+	// the rows stay locked until the end of the transaction
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	rset, err := ses.PrepAndQry("SELECT ROWID, id FROM " + tableName + " ORDER BY id FOR UPDATE")
+	testErr(err, t)
+	for rset.Next() {
+		if rset.Row[1].(int64) == 2 {
+			if err = rset.UpdateByRowid(tableName+" SET name = :1", "updated"); err != nil {
+				tx.Rollback()
+				t.Fatal(err)
+			}
+		}
+	}
+	testErr(rset.Err(), t)
+	testErr(tx.Commit(), t)
+
+	rset, err = ses.PrepAndQry("SELECT id, name FROM " + tableName + " ORDER BY id")
+	testErr(err, t)
+	for rset.Next() {
+		want := map[int64]string{1: "a", 2: "updated", 3: "c"}[rset.Row[0].(int64)]
+		if got := rset.Row[1].(string); got != want {
+			t.Errorf("%v. got %q, wanted %q", rset.Row[0], got, want)
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestRowidSliceUpdate(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), name VARCHAR2(10))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe("INSERT INTO "+tableName+" (id, name) VALUES (:1, :2)",
+		[]int64{1, 2, 3}, []string{"a", "b", "c"})
+	testErr(err, t)
+
+	var rowids []ora.Rowid
+	var names []string
+	rset, err := testSes.PrepAndQry("SELECT ROWID, id FROM " + tableName + " WHERE id <> 2")
+	testErr(err, t)
+	for rset.Next() {
+		rowids = append(rowids, ora.Rowid(rset.Row[0].(string)))
+		names = append(names, fmt.Sprintf("upd%v", rset.Row[1]))
+	}
+	testErr(rset.Err(), t)
+
+	n, err := testSes.PrepAndExe("UPDATE "+tableName+" SET name = :1 WHERE ROWID = :2", names, rowids)
+	testErr(err, t)
+	if n != 2 {
+		t.Errorf("updated %d rows, wanted 2", n)
+	}
+
+	rset, err = testSes.PrepAndQry("SELECT id, name FROM " + tableName + " ORDER BY id")
+	testErr(err, t)
+	for rset.Next() {
+		want := map[int64]string{1: "upd1", 2: "b", 3: "upd3"}[rset.Row[0].(int64)]
+		if got := rset.Row[1].(string); got != want {
+			t.Errorf("%v. got %q, wanted %q", rset.Row[0], got, want)
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestRsetRowSCN(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe("INSERT INTO " + tableName + " (id) VALUES (1)")
+	testErr(err, t)
+
+	rset, err := testSes.PrepAndQry("SELECT id, ORA_ROWSCN FROM " + tableName)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	scn, err := rset.RowSCN()
+	testErr(err, t)
+	if scn == 0 {
+		t.Errorf("got zero SCN")
+	}
+	rset.Exhaust()
+}
+
+func TestRsetMaxRows(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		rows, maxRows int
+		truncated     bool
+	}{
+		{rows: 10, maxRows: 3, truncated: true},
+		{rows: 3, maxRows: 3},
+		{rows: 2, maxRows: 3},
+		{rows: 300, maxRows: 200, truncated: true},
+	} {
+		stmt, err := testSes.Prep(fmt.Sprintf("SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= %d", tc.rows))
+		testErr(err, t)
+		cfg := stmt.Cfg()
+		cfg.MaxRows = tc.maxRows
+		stmt.SetCfg(cfg)
+		rset, err := stmt.Qry()
+		testErr(err, t)
+		var n int
+		for rset.Next() {
+			n++
+		}
+		testErr(rset.Err(), t)
+		want := tc.rows
+		if want > tc.maxRows {
+			want = tc.maxRows
+		}
+		if n != want || rset.Len() != want {
+			t.Errorf("%d/%d: got %d rows (Len=%d), wanted %d", tc.rows, tc.maxRows, n, rset.Len(), want)
+		}
+		if rset.Truncated() != tc.truncated {
+			t.Errorf("%d/%d: got truncated %t, wanted %t", tc.rows, tc.maxRows, rset.Truncated(), tc.truncated)
+		}
+		stmt.Close()
+	}
+}
+
+func TestRsetColumnDomain(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	domainName := strings.ToUpper(tableName + "_d")
+	if _, err := testSes.PrepAndExe("CREATE DOMAIN " + domainName + " AS VARCHAR2(100)"); err != nil {
+		if cd, ok := err.(interface {
+			Code() int
+		}); ok && (cd.Code() == 900 || cd.Code() == 922) {
+			t.Skipf("no SQL domains (Oracle 23 needed): %v", err)
+		}
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP DOMAIN " + domainName)
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + ` (
+  email VARCHAR2(100) DOMAIN ` + domainName + ` ANNOTATIONS (Display 'E-mail', Hidden),
+  txt VARCHAR2(10))`); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	rset, err := testSes.PrepAndQry("SELECT email, txt FROM " + tableName)
+	testErr(err, t)
+	defer rset.Exhaust()
+	email, txt := rset.Columns[0], rset.Columns[1]
+	if email.DomainName == "" {
+		t.Skip("no domain described (client older than 23?)")
+	}
+	if email.DomainName != domainName {
+		t.Errorf("got domain %q, wanted %s", email.DomainName, domainName)
+	}
+	if got := email.Annotations["DISPLAY"]; got != "E-mail" {
+		t.Errorf("got annotations %v, wanted DISPLAY=E-mail", email.Annotations)
+	}
+	if _, ok := email.Annotations["HIDDEN"]; !ok {
+		t.Errorf("got annotations %v, wanted HIDDEN", email.Annotations)
+	}
+	if txt.DomainName != "" || len(txt.Annotations) != 0 {
+		t.Errorf("plain column got domain %q, annotations %v", txt.DomainName, txt.Annotations)
+	}
+}
+
+func TestReturningRowid(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), name VARCHAR2(10))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	var rowid ora.Rowid
+	_, err = testSes.PrepAndExe("INSERT INTO "+tableName+" (id, name) VALUES (1, 'a') RETURNING ROWID INTO :1", &rowid)
+	testErr(err, t)
+	if rowid == "" {
+		t.Fatal("no ROWID returned")
+	}
+	var s string
+	_, err = testSes.PrepAndExe("UPDATE "+tableName+" SET name = 'b' WHERE ROWID = :1 RETURNING ROWID INTO :2", rowid, &s)
+	testErr(err, t)
+	if s != string(rowid) {
+		t.Errorf("UPDATE returned %q, INSERT %q", s, rowid)
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT name FROM "+tableName+" WHERE ROWID = :1", rowid)
+	testErr(err, t)
+	if !rset.Next() || rset.Row[0] != "b" {
+		t.Errorf("got %v (%v), wanted b", rset.Row, rset.Err())
+	}
+}
+
+func TestRowidToChar(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3) PRIMARY KEY) ORGANIZATION INDEX")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe("INSERT INTO "+tableName+" (id) VALUES (:1)", []int64{1, 2})
+	testErr(err, t)
+
+	for _, qry := range []string{
+		"SELECT ROWID, 'x' FROM DUAL",        // physical ROWID
+		"SELECT ROWID, id FROM " + tableName, // universal ROWID of an IOT
+	} {
+		rset, err := testSes.PrepAndQry(qry)
+		testErr(err, t)
+		for rset.Next() {
+			rowid, ok := rset.Row[0].(string)
+			if !ok || rowid == "" {
+				t.Fatalf("%s: got %#v, wanted a ROWID string", qry, rset.Row[0])
+			}
+			if strings.HasPrefix(qry, "SELECT ROWID, 'x'") && len(rowid) != 18 {
+				t.Errorf("%s: got %q, wanted 18 characters", qry, rowid)
+			}
+			// the ROWID is usable in later queries
+			check, err := testSes.PrepAndQry("SELECT COUNT(0) FROM "+strings.SplitN(qry, " FROM ", 2)[1]+" WHERE ROWID = :1", ora.Rowid(rowid))
+			testErr(err, t)
+			if check.Next() {
+				if n := fmt.Sprintf("%v", check.Row[0]); n != "1" {
+					t.Errorf("%s: ROWID %q found %s rows", qry, rowid, n)
+				}
+			}
+			check.Exhaust()
+		}
+		testErr(rset.Err(), t)
+	}
+}
+
+func TestInCursor(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+
+	stmt, err := ses.Prep("BEGIN OPEN :1 FOR SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 5; END;")
+	testErr(err, t)
+	defer stmt.Close()
+	rset := &ora.Rset{}
+	_, err = stmt.Exe(rset)
+	testErr(err, t)
+	if !rset.IsOpen() {
+		t.Fatal("cursor is not open")
+	}
+
+	// the rows are fetched by the receiver
+	var n int64
+	_, err = ses.PrepAndExe(`DECLARE
+  c SYS_REFCURSOR := :1;
+  v NUMBER;
+  n NUMBER := 0;
+BEGIN
+  LOOP
+    FETCH c INTO v;
+    EXIT WHEN c%NOTFOUND;
+    n := n + 1;
+  END LOOP;
+  CLOSE c;
+  :2 := n;
+END;`, ora.InCursor{Rset: rset}, &n)
+	testErr(err, t)
+	if n != 5 {
+		t.Errorf("got %d rows, wanted 5", n)
+	}
+
+	if _, err = ses.PrepAndExe("BEGIN NULL; END;", ora.InCursor{Rset: &ora.Rset{}}); err == nil {
+		t.Error("closed Rset: wanted error")
+	}
+}
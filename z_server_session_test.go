@@ -5,6 +5,7 @@
 package ora_test
 
 import (
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
@@ -151,3 +152,173 @@ func TestPool(t *testing.T) {
 	pool.Close()
 	T("Pool close", p2, s2)
 }
+
+func TestPoolValidateOnGet(t *testing.T) {
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	pool := env.NewPool(testSrvCfg, testSesCfg, 1)
+	defer pool.Close()
+
+	ses, err := pool.Get()
+	testErr(err, t)
+	var sid, serial interface{}
+	rset, err := ses.PrepAndQry("SELECT sid, serial# FROM v$session WHERE sid = SYS_CONTEXT('USERENV', 'SID')")
+	if err != nil {
+		pool.Put(ses)
+		t.Skip(err)
+	}
+	if rset.Next() {
+		sid, serial = rset.Row[0], rset.Row[1]
+	}
+	pool.Put(ses)
+	if _, err = testSes.PrepAndExe(fmt.Sprintf("ALTER SYSTEM KILL SESSION '%v,%v' IMMEDIATE", sid, serial)); err != nil {
+		t.Skip(err)
+	}
+
+	if ses, err = pool.Get(); err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(ses)
+	if _, err = ses.PrepAndQry("SELECT 1 FROM DUAL"); err != nil {
+		t.Errorf("killed session has not been replaced: %v", err)
+	}
+}
+
+func TestSesKilled(t *testing.T) {
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	var sid, serial interface{}
+	rset, err := ses.PrepAndQry("SELECT sid, serial# FROM v$session WHERE sid = SYS_CONTEXT('USERENV', 'SID')")
+	if err != nil {
+		t.Skip(err)
+	}
+	if rset.Next() {
+		sid, serial = rset.Row[0], rset.Row[1]
+	}
+	if _, err = testSes.PrepAndExe(fmt.Sprintf("ALTER SYSTEM KILL SESSION '%v,%v' IMMEDIATE", sid, serial)); err != nil {
+		t.Skip(err)
+	}
+
+	_, err = ses.PrepAndQry("SELECT 1 FROM DUAL")
+	if !ora.IsConnLost(err) {
+		t.Fatalf("wanted connection lost error, got %v", err)
+	}
+	if ses.IsOpen() {
+		t.Error("killed session is still open")
+	}
+	if srv.IsOpen() {
+		t.Error("server of the killed session is still open")
+	}
+}
+
+func TestSesEdition(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+
+	cfg := testSesCfg
+	cfg.Edition = "ora$base"
+	ses, err := srv.OpenSes(cfg)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ses.Close()
+	rset, err := ses.PrepAndQry("SELECT SYS_CONTEXT('USERENV', 'CURRENT_EDITION_NAME') FROM DUAL")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if got := rset.Row[0]; got != "ORA$BASE" {
+		t.Errorf("got edition %q, wanted ORA$BASE", got)
+	}
+
+	cfg.Edition = "ora$base; DROP"
+	if ses2, err := srv.OpenSes(cfg); err == nil {
+		ses2.Close()
+		t.Error("invalid edition name accepted")
+	}
+}
+
+func TestSesTag(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+
+	srvCfg := testSrvCfg
+	srvCfg.Pool = ora.PoolCfg{Type: ora.SPool, Username: testSesCfg.Username, Password: testSesCfg.Password, Min: 1, Max: 2, Incr: 1}
+	srvCfg.StmtCacheSize = 10
+	srv, err := env.OpenSrv(srvCfg)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer srv.Close()
+
+	cfg := testSesCfg
+	cfg.Tag = "NLS_DATE_FORMAT=YYYY"
+	ses, err := srv.OpenSes(cfg)
+	testErr(err, t)
+	if ses.TagFound() {
+		t.Error("tag found in a new pool")
+	}
+	if _, err = ses.PrepAndExe("ALTER SESSION SET NLS_DATE_FORMAT='YYYY'"); err != nil {
+		ses.Close()
+		t.Fatal(err)
+	}
+	ses.SetTag(cfg.Tag)
+	testErr(ses.Close(), t)
+
+	ses, err = srv.OpenSes(cfg)
+	testErr(err, t)
+	defer ses.Close()
+	if !ses.TagFound() {
+		t.Error("tagged session not found")
+	}
+}
+
+func TestPoolMaxLifetime(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	srvCfg := testSrvCfg
+	srvCfg.Pool.MaxLifetime = 100 * time.Millisecond
+	pool := env.NewPool(srvCfg, testSesCfg, 2)
+	defer pool.Close()
+
+	ses, err := pool.Get()
+	testErr(err, t)
+	testErr(ses.Close(), t)
+	if n := pool.Recycled(); n != 0 {
+		t.Errorf("young session recycled (%d)", n)
+	}
+
+	ses, err = pool.Get()
+	testErr(err, t)
+	time.Sleep(2 * srvCfg.Pool.MaxLifetime)
+	testErr(ses.Close(), t)
+	if n := pool.Recycled(); n != 1 {
+		t.Errorf("got %d recycled sessions, wanted 1", n)
+	}
+
+	if ses, err = pool.Get(); err != nil {
+		t.Fatal(err)
+	}
+	defer ses.Close()
+	if err = ses.Ping(); err != nil {
+		t.Errorf("replacement session: %v", err)
+	}
+}
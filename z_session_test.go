@@ -5,6 +5,8 @@
 package ora_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -440,3 +442,527 @@ func GetCompileErrors(ses *ora.Ses, all bool) ([]CompileError, error) {
 	}
 	return errors, rows.Err()
 }
+
+func TestSes_PrepFrom(t *testing.T) {
+	t.Parallel()
+	tmpl := ora.NewPreparedSQL("SELECT :1 FROM DUAL", ora.S)
+	for i := 0; i < 2; i++ {
+		env, err := ora.OpenEnv()
+		testErr(err, t)
+		srv, err := env.OpenSrv(testSrvCfg)
+		testErr(err, t)
+		ses, err := srv.OpenSes(testSesCfg)
+		testErr(err, t)
+		stmt, err := ses.PrepFrom(tmpl)
+		testErr(err, t)
+		rset, err := stmt.Qry(i)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal(rset.Err())
+		}
+		if got := rset.Row[0]; got != fmt.Sprintf("%d", i) {
+			t.Errorf("%d. got %#v", i, got)
+		}
+		stmt.Close()
+		ses.Close()
+		srv.Close()
+		env.Close()
+	}
+}
+
+func TestSes_InTransaction(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	if ses.InTransaction() || ses.NumTx() != 0 {
+		t.Fatalf("new session is in transaction (%d)", ses.NumTx())
+	}
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	if !ses.InTransaction() || ses.NumTx() != 1 {
+		t.Errorf("session is not in transaction after StartTx (%d)", ses.NumTx())
+	}
+	testErr(tx.Rollback(), t)
+	if ses.InTransaction() || ses.NumTx() != 0 {
+		t.Errorf("session is in transaction after Rollback (%d)", ses.NumTx())
+	}
+}
+
+func TestSes_CommitEvery(t *testing.T) {
+	t.Parallel()
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	count := func() int64 {
+		rset, err := testSes.PrepAndQry("SELECT COUNT(0) FROM "+tableName, ora.I64)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal(rset.Err())
+		}
+		return rset.Row[0].(int64)
+	}
+
+	testErr(ses.CommitEvery(3), t)
+	stmt, err := ses.Prep(fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	for i := int64(1); i <= 4; i++ {
+		_, err = stmt.Exe(i)
+		testErr(err, t)
+		want := int64(0)
+		if i >= 3 {
+			want = 3
+		}
+		if got := count(); got != want {
+			t.Errorf("after %d inserts: got %d committed rows, wanted %d", i, got, want)
+		}
+	}
+	testErr(ses.Commit(), t)
+	if got := count(); got != 4 {
+		t.Errorf("after Commit: got %d committed rows, wanted 4", got)
+	}
+}
+
+func TestSes_CommitEvery_rollback(t *testing.T) {
+	t.Parallel()
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	count := func() int64 {
+		rset, err := testSes.PrepAndQry("SELECT COUNT(0) FROM "+tableName, ora.I64)
+		testErr(err, t)
+		if !rset.Next() {
+			t.Fatal(rset.Err())
+		}
+		return rset.Row[0].(int64)
+	}
+
+	// a pooled session settles its batch on Close
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	testErr(ses.CommitEvery(5), t)
+	if _, err = ses.PrepAndExe(fmt.Sprintf("INSERT INTO %v (c1) VALUES (1)", tableName)); err != nil {
+		t.Fatal(err)
+	}
+	testErr(ses.Close(), t)
+	if got := count(); got != 1 {
+		t.Errorf("after Close: got %d committed rows, wanted 1", got)
+	}
+
+	// a cancelled execution loses the pending rows of the batch
+	ses, err = testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	testErr(ses.CommitEvery(10), t)
+	stmt, err := ses.Prep(fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	if _, err = stmt.Exe([]int64{2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = ora.WithProgress(ctx, 4, func(done, total int) { cancel() })
+	_, err = stmt.ExeContext(ctx, []int64{4, 5, 6, 7, 8, 9, 10, 11})
+	rbErr, ok := err.(*ora.BatchRollbackError)
+	if !ok {
+		t.Fatalf("got %v (%T), wanted *BatchRollbackError", err, err)
+	}
+	if rbErr.Rows != 2 || rbErr.Err != context.Canceled {
+		t.Errorf("got %#v, wanted 2 rows lost by cancel", rbErr)
+	}
+	if got := count(); got != 1 {
+		t.Errorf("after the rollback: got %d committed rows, wanted 1", got)
+	}
+}
+
+func TestSes_GetModuleAction(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+
+	testErr(ses.SetAction("ora.test", "outer"), t)
+	module, action, err := ses.GetModuleAction()
+	testErr(err, t)
+	if module != "ora.test" || action != "outer" {
+		t.Errorf("got %q/%q, wanted %q/%q", module, action, "ora.test", "outer")
+	}
+
+	// nested scope: save, set, restore
+	testErr(ses.SetAction("ora.test", "inner"), t)
+	testErr(ses.SetAction(module, action), t)
+	if _, action, err = ses.GetModuleAction(); err != nil {
+		t.Fatal(err)
+	} else if action != "outer" {
+		t.Errorf("restored action: got %q, wanted %q", action, "outer")
+	}
+}
+
+func TestSes_HasOpenTransaction(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	tableName, err := createTable(1, numberP38S0, ses)
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	open, err := ses.HasOpenTransaction()
+	if err != nil {
+		t.Skip(err)
+	}
+	if open {
+		t.Error("open transaction reported before any DML")
+	}
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("INSERT INTO %v (c1) VALUES (1)", tableName))
+	testErr(err, t)
+	if open, err = ses.HasOpenTransaction(); err != nil {
+		t.Fatal(err)
+	} else if !open {
+		t.Error("no open transaction reported after an uncommitted INSERT")
+	}
+	testErr(tx.Rollback(), t)
+	if open, err = ses.HasOpenTransaction(); err != nil {
+		t.Fatal(err)
+	} else if open {
+		t.Error("open transaction reported after Rollback")
+	}
+}
+
+func TestSrvPDBName(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+
+	cfg := testSrvCfg
+	cfg.PDBName = "no such pdb"
+	srv, err := env.OpenSrv(cfg)
+	testErr(err, t)
+	if ses, err := srv.OpenSes(testSesCfg); err == nil {
+		ses.Close()
+		t.Errorf("invalid PDB name %q accepted", cfg.PDBName)
+	}
+	srv.Close()
+
+	cfg.PDBName = "NO_SUCH_PDB_ORA"
+	srv, err = env.OpenSrv(cfg)
+	testErr(err, t)
+	defer srv.Close()
+	ses, err := srv.OpenSes(testSesCfg)
+	if err == nil {
+		ses.Close()
+		t.Fatalf("switched to the nonexistent PDB %q", cfg.PDBName)
+	}
+	cd, ok := err.(interface {
+		Code() int
+	})
+	if !ok || cd.Code() != 65011 {
+		t.Skipf("not a container database, or no SET CONTAINER privilege: %v", err)
+	}
+	if !strings.Contains(err.Error(), cfg.PDBName) {
+		t.Errorf("error %q does not name the PDB", err)
+	}
+}
+
+func TestSes_PinStmt(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	srvCfg := testSrvCfg
+	srvCfg.StmtCacheSize = 2
+	srv, err := env.OpenSrv(srvCfg)
+	testErr(err, t)
+	defer srv.Close()
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+
+	// the parse count doesn't change when the pinned statement is found in the cache
+	parseCount := parseCounter(ses, t)
+
+	const hot = "SELECT :1 FROM DUAL"
+	testErr(ses.PinStmt(hot), t)
+	for i := 0; i < 5; i++ {
+		// the one-off statements must not evict the pinned one
+		stmt, err := ses.Prep(fmt.Sprintf("SELECT %d FROM DUAL", i))
+		testErr(err, t)
+		_, err = stmt.Qry()
+		testErr(err, t)
+		testErr(stmt.Close(), t)
+
+		before := parseCount()
+		var got int64
+		if stmt, err = ses.Prep(hot, ora.I64); err != nil {
+			t.Fatal(err)
+		}
+		rset, err := stmt.Qry(int64(i))
+		testErr(err, t)
+		if rset.Next() {
+			got = rset.Row[0].(int64)
+		}
+		testErr(rset.Err(), t)
+		testErr(stmt.Close(), t)
+		if got != int64(i) {
+			t.Errorf("%d. got %d", i, got)
+		}
+		if after := parseCount(); i > 0 && after != before {
+			t.Errorf("%d. the pinned statement has been evicted (parsed %d times)", i, after-before)
+		}
+	}
+	ses.UnpinStmt(hot)
+	testErr(ses.Close(), t)
+	if err = ses.PinStmt(hot); err == nil {
+		t.Error("PinStmt succeeded on a closed session")
+	}
+}
+
+func TestSes_InstanceName(t *testing.T) {
+	t.Parallel()
+	inst, err := testSes.InstanceName()
+	testErr(err, t)
+	rset, err := testSes.PrepAndQry("SELECT SYS_CONTEXT('USERENV', 'INSTANCE_NAME') FROM DUAL")
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if want := rset.Row[0].(string); !strings.EqualFold(inst, want) {
+		t.Errorf("got %q, wanted %q", inst, want)
+	}
+	dbName, err := testSes.DBName()
+	testErr(err, t)
+	if dbName == "" {
+		t.Error("empty DBName")
+	}
+
+	// errors of the session tell the instance
+	if rset, err = testSes.PrepAndQry("SELECT 1/0 FROM DUAL"); err == nil {
+		rset.Next()
+		err = rset.Err()
+	}
+	if err == nil {
+		t.Fatal("wanted ORA-01476")
+	}
+	if ie, ok := err.(interface {
+		Instance() string
+	}); !ok || ie.Instance() != inst {
+		t.Errorf("error %v: wanted instance %q", err, inst)
+	}
+}
+
+func TestSes_BatchInsert(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(5), name VARCHAR2(10))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	// INSERT ALL for a few rows, array binding for many
+	for _, n := range []int{3, 100} {
+		rows := make([][]interface{}, n)
+		for i := range rows {
+			rows[i] = []interface{}{i, strconv.Itoa(i)}
+			if i%2 == 1 {
+				rows[i][1] = nil
+			}
+		}
+		if _, err := testSes.PrepAndExe("DELETE FROM " + tableName); err != nil {
+			t.Fatal(err)
+		}
+		got, err := testSes.BatchInsert(tableName, []string{"id", "name"}, rows)
+		if err != nil {
+			t.Fatalf("%d: %v", n, err)
+		}
+		if got != uint64(n) {
+			t.Errorf("%d: inserted %d rows", n, got)
+		}
+		rset, err := testSes.PrepAndQry("SELECT COUNT(0), COUNT(name) FROM "+tableName, ora.I64, ora.I64)
+		testErr(err, t)
+		if rset.Next() {
+			if all, names := rset.Row[0].(int64), rset.Row[1].(int64); all != int64(n) || names != int64((n+1)/2) {
+				t.Errorf("%d: got %d rows and %d names", n, all, names)
+			}
+		}
+		testErr(rset.Err(), t)
+	}
+
+	if _, err := testSes.BatchInsert(tableName, []string{"id", "name"}, [][]interface{}{{1}}); err == nil {
+		t.Error("short row: wanted error")
+	}
+	if _, err := testSes.BatchInsert(tableName, []string{"id", "name) SELECT 1, 2 FROM DUAL --"}, [][]interface{}{{1, "a"}}); err == nil {
+		t.Error("injected column name: wanted error")
+	}
+}
+
+func TestSes_CurrentUser(t *testing.T) {
+	t.Parallel()
+	user, err := testSes.CurrentUser()
+	testErr(err, t)
+	if want := strings.ToUpper(testSesCfg.Username); user != want {
+		t.Errorf("got %q, wanted %q", user, want)
+	}
+	proxy, err := testSes.ProxyUser()
+	testErr(err, t)
+	if proxy != "" {
+		t.Errorf("got proxy user %q without proxy authentication", proxy)
+	}
+}
+
+func TestSes_CurrentUserReopen(t *testing.T) {
+	// This needs a user which can connect through the test user:
+	// "ALTER USER client GRANT CONNECT THROUGH test".
+	client := os.Getenv("GO_ORA_DRV_TEST_PROXY_CLIENT")
+	if client == "" {
+		t.Skip("GO_ORA_DRV_TEST_PROXY_CLIENT is not set")
+	}
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	defer srv.Close()
+
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	user, err := ses.CurrentUser()
+	testErr(err, t)
+	if want := strings.ToUpper(testSesCfg.Username); user != want {
+		t.Errorf("got %q, wanted %q", user, want)
+	}
+	testErr(ses.Close(), t)
+
+	// the closed Ses is recycled, so it must not keep the cached users
+	cfg := testSesCfg
+	cfg.Username = testSesCfg.Username + "[" + client + "]"
+	ses, err = srv.OpenSes(cfg)
+	testErr(err, t)
+	defer ses.Close()
+	user, err = ses.CurrentUser()
+	testErr(err, t)
+	if want := strings.ToUpper(client); user != want {
+		t.Errorf("got user %q, wanted %q", user, want)
+	}
+	proxy, err := ses.ProxyUser()
+	testErr(err, t)
+	if want := strings.ToUpper(testSesCfg.Username); proxy != want {
+		t.Errorf("got proxy user %q, wanted %q", proxy, want)
+	}
+}
+
+func TestSes_WithConsistentSnapshot(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+
+	count := func(ses *ora.Ses) int64 {
+		rset, err := ses.PrepAndQry("SELECT COUNT(0) FROM "+tableName, ora.I64)
+		testErr(err, t)
+		var n int64
+		if rset.Next() {
+			n = rset.Row[0].(int64)
+		}
+		testErr(rset.Err(), t)
+		return n
+	}
+	err = ses.WithConsistentSnapshot(func(ses *ora.Ses) error {
+		before := count(ses)
+		// committed by another session in the meantime
+		if _, err := testSes.PrepAndExe("INSERT INTO " + tableName + " (id) VALUES (1)"); err != nil {
+			return err
+		}
+		if after := count(ses); after != before {
+			t.Errorf("got %d rows in the snapshot, wanted %d", after, before)
+		}
+		return nil
+	})
+	testErr(err, t)
+	if n := count(ses); n != 1 {
+		t.Errorf("got %d rows after the snapshot, wanted 1", n)
+	}
+
+	// the error of f is returned as is
+	errStop := errors.New("stop")
+	if err = ses.WithConsistentSnapshot(func(*ora.Ses) error { return errStop }); err != errStop {
+		t.Errorf("got %v, wanted %v", err, errStop)
+	}
+}
+
+func TestSes_DescribeObject(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(5,2), name VARCHAR2(10))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	desc, err := testSes.DescribeObject(tableName)
+	testErr(err, t)
+	if desc.Type != ora.ObjectTable || !strings.EqualFold(desc.Name, tableName) {
+		t.Errorf("got %s %s", desc.Type, desc.Name)
+	}
+	if len(desc.Columns) != 2 {
+		t.Fatalf("got %d columns, wanted 2", len(desc.Columns))
+	}
+	if col := desc.Columns[0]; col.Name != "ID" || col.Precision != 5 || col.Scale != 2 {
+		t.Errorf("got %#v", col)
+	}
+	if col := desc.Columns[1]; col.Name != "NAME" || col.CharSize != 10 {
+		t.Errorf("got %#v", col)
+	}
+
+	funcName := tableName + "_F"
+	if _, err = testSes.PrepAndExe("CREATE OR REPLACE FUNCTION " + funcName +
+		"(p_in IN NUMBER, p_out OUT VARCHAR2, p_def IN NUMBER DEFAULT 0) RETURN DATE IS BEGIN p_out := 'x'; RETURN SYSDATE; END;"); err != nil {
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP FUNCTION " + funcName)
+	if desc, err = testSes.DescribeObject(funcName); err != nil {
+		t.Fatal(err)
+	}
+	if desc.Type != ora.ObjectFunction || len(desc.Arguments) != 4 {
+		t.Fatalf("got %s with %#v", desc.Type, desc.Arguments)
+	}
+	for i, want := range []struct {
+		name       string
+		dir        ora.BindDir
+		hasDefault bool
+	}{{"", ora.BindOut, false}, {"P_IN", ora.BindIn, false}, {"P_OUT", ora.BindOut, false}, {"P_DEF", ora.BindIn, true}} {
+		arg := desc.Arguments[i]
+		if arg.Position != uint16(i) || arg.Name != want.name || (i > 0 && arg.Dir != want.dir) || arg.HasDefault != want.hasDefault {
+			t.Errorf("%d. got %#v", i, arg)
+		}
+	}
+
+	if _, err = testSes.DescribeObject(tableName + "_NONE"); err == nil {
+		t.Error("wanted error for a missing object")
+	}
+}
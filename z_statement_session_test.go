@@ -5,8 +5,12 @@
 package ora_test
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	ora "gopkg.in/rana/ora.v4"
 
@@ -55,6 +59,30 @@ func TestStmt_Exe_insert(t *testing.T) {
 	}
 }
 
+func TestStmt_IsReturning(t *testing.T) {
+	t.Parallel()
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	for _, tc := range []struct {
+		qry  string
+		want bool
+	}{
+		{"insert into %v (c1) values (9)", false},
+		{"insert into %v (c1) values (9) returning c1 into :1", true},
+		{"update %v set c1 = c1 + 1 returning c1 into :1", true},
+		{"select c1 from %v", false},
+	} {
+		stmt, err := testSes.Prep(fmt.Sprintf(tc.qry, tableName))
+		testErr(err, t)
+		if got := stmt.IsReturning(); got != tc.want {
+			t.Errorf("%q: got %t, wanted %t", tc.qry, got, tc.want)
+		}
+		stmt.Close()
+	}
+}
+
 func TestStmt_Exe_update(t *testing.T) {
 	t.Parallel()
 	tableName, err := createTable(1, numberP38S0, testSes)
@@ -211,3 +239,788 @@ func mkBenchArrays() ([]int64, []string) {
 	}
 	return ids, names
 }
+
+func TestStmt_QryWithLockRetry(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	_, err = testSes.PrepAndExe(fmt.Sprintf("insert into %v (c1) values (1)", tableName))
+	testErr(err, t)
+
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	qry := fmt.Sprintf("select c1 from %v for update nowait", tableName)
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	locked, err := ses.PrepAndQry(qry)
+	testErr(err, t)
+	locked.Exhaust()
+
+	// the retrying session locks the row, too, in its own transaction
+	retrySes, err := testSesPool.Get()
+	testErr(err, t)
+	defer retrySes.Close()
+	retryTx, err := retrySes.StartTx()
+	testErr(err, t)
+	stmt, err := retrySes.Prep(qry)
+	testErr(err, t)
+	defer stmt.Close()
+	if _, err = stmt.QryWithLockRetry(3, 10*time.Millisecond); err == nil {
+		t.Fatal("wanted ORA-00054, got nil error")
+	} else if cd, ok := err.(interface {
+		Code() int
+	}); !ok || cd.Code() != 54 {
+		t.Fatalf("wanted ORA-00054, got %v", err)
+	}
+
+	rolledBack := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		rolledBack <- tx.Rollback()
+	}()
+	rset, err := stmt.QryWithLockRetry(10, 10*time.Millisecond)
+	testErr(err, t)
+	rset.Exhaust()
+	testErr(<-rolledBack, t)
+	testErr(retryTx.Rollback(), t)
+}
+
+func TestStmt_Validate(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep("SELECT 1 FROM DUAL")
+	testErr(err, t)
+	defer stmt.Close()
+	if err = stmt.Validate(); err != nil {
+		t.Errorf("valid statement: %v", err)
+	}
+
+	const qry = "SELECT 1 FROM DUAL WHERE nonexistent_column = 1"
+	stmt2, err := testSes.Prep(qry)
+	testErr(err, t)
+	defer stmt2.Close()
+	err = stmt2.Validate()
+	pe, ok := err.(*ora.ParseError)
+	if !ok {
+		t.Fatalf("wanted *ora.ParseError, got %#v", err)
+	}
+	if pe.Code() != 904 {
+		t.Errorf("wanted ORA-00904, got %v", pe)
+	}
+	if want := strings.Index(qry, "nonexistent_column"); pe.Offset != want {
+		t.Errorf("got offset %d, wanted %d", pe.Offset, want)
+	}
+
+	stmt3, err := testSes.Prep("CREATE TABLE " + tableName() + " (n NUMBER)")
+	testErr(err, t)
+	defer stmt3.Close()
+	if err = stmt3.Validate(); err == nil {
+		t.Error("validating DDL succeeded")
+	}
+}
+
+func TestStmt_ConcurrentExe(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep("DECLARE v VARCHAR2(10) := :1; BEGIN NULL; END;")
+	testErr(err, t)
+	defer stmt.Close()
+
+	errs := make(chan error, 2)
+	for g := 0; g < 2; g++ {
+		go func(g int) {
+			for i := 0; i < 50; i++ {
+				if _, err := stmt.Exe(fmt.Sprintf("%d-%d", g, i)); err != nil && err != ora.ErrStmtInUse {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}(g)
+	}
+	for g := 0; g < 2; g++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestStmt_ExeContext_cancelRollback(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	tableName, err := createTable(1, numberP38S0, ses)
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+	// slow down the inserts, to be able to cancel in the middle
+	if _, err = ses.PrepAndExe(fmt.Sprintf(`CREATE OR REPLACE TRIGGER %[1]s_slow
+  BEFORE INSERT ON %[1]s FOR EACH ROW
+BEGIN
+  DBMS_SESSION.sleep(0.01);
+END;`, tableName)); err != nil {
+		t.Skip(err)
+	}
+
+	values := make([]int64, 1000)
+	for i := range values {
+		values[i] = int64(i)
+	}
+	stmt, err := ses.Prep(fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err = stmt.ExeContext(ctx, values); err == nil {
+		t.Fatal("the insert hasn't been cancelled")
+	}
+	t.Logf("cancelled: %v", err)
+
+	rset, err := ses.PrepAndQry(fmt.Sprintf("SELECT COUNT(0) FROM %v", tableName), ora.I64)
+	testErr(err, t)
+	if rset.Next() {
+		if n := rset.Row[0]; n != int64(0) {
+			t.Errorf("got %v rows after the cancelled insert, wanted 0", n)
+		}
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestStmt_ExeContext_cancelErr(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+
+	for _, keep := range []bool{false, true} {
+		cfg := ora.NewStmtCfg()
+		cfg.KeepCancelErr = keep
+		stmt, err := ses.Prep("BEGIN DBMS_SESSION.sleep(5); END;")
+		testErr(err, t)
+		stmt.SetCfg(cfg)
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		_, err = stmt.ExeContext(ctx)
+		cancel()
+		stmt.Close()
+		if err == nil {
+			t.Fatalf("keep=%t: the call hasn't been cancelled", keep)
+		}
+		if keep {
+			if !strings.Contains(err.Error(), "ORA-01013") {
+				t.Errorf("keep=%t: got %v, wanted ORA-01013", keep, err)
+			}
+		} else if err != context.DeadlineExceeded {
+			t.Errorf("keep=%t: got %v, wanted %v", keep, err, context.DeadlineExceeded)
+		}
+	}
+}
+
+func TestStmt_ExeNoCommit(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+
+	// counted by another session, which sees only the committed rows
+	count := func() int64 {
+		rset, err := testSes.PrepAndQry("SELECT COUNT(0) FROM "+tableName, ora.I64)
+		testErr(err, t)
+		var n int64
+		if rset.Next() {
+			n = rset.Row[0].(int64)
+		}
+		testErr(rset.Err(), t)
+		return n
+	}
+	stmt, err := ses.Prep("INSERT INTO " + tableName + " (id) VALUES (:1)")
+	testErr(err, t)
+	defer stmt.Close()
+	if _, err = stmt.ExeNoCommit(int64(1)); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 0 {
+		t.Errorf("got %d committed rows, wanted 0", n)
+	}
+	if _, err = stmt.Exe(int64(2)); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 2 {
+		t.Errorf("got %d committed rows, wanted 2", n)
+	}
+}
+
+func TestStmt_staleStmtCache(t *testing.T) {
+	t.Parallel()
+	env, err := ora.OpenEnv()
+	testErr(err, t)
+	defer env.Close()
+	srvCfg := testSrvCfg
+	srvCfg.StmtCacheSize = 8
+	srv, err := env.OpenSrv(srvCfg)
+	testErr(err, t)
+	defer srv.Close()
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	pkg := tableName() + "_pkg"
+	createPkg := func(val int) {
+		// the package state (the variable) is discarded at each recompile
+		if _, err := testSes.PrepAndExe(fmt.Sprintf(`CREATE OR REPLACE PACKAGE %s IS
+  v PLS_INTEGER := %d;
+  FUNCTION get RETURN PLS_INTEGER;
+END;`, pkg, val)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := testSes.PrepAndExe(fmt.Sprintf(`CREATE OR REPLACE PACKAGE BODY %s IS
+  FUNCTION get RETURN PLS_INTEGER IS BEGIN RETURN v; END;
+END;`, pkg)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createPkg(1)
+	defer testSes.PrepAndExe("DROP PACKAGE " + pkg)
+
+	qry := fmt.Sprintf("BEGIN :1 := %s.get; END;", pkg)
+	exe := func() (int64, error) {
+		stmt, err := ses.Prep(qry)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Close()
+		var v int64
+		_, err = stmt.Exe(&v)
+		return v, err
+	}
+	v, err := exe()
+	testErr(err, t)
+	if v != 1 {
+		t.Errorf("got %d, wanted 1", v)
+	}
+
+	parseCount := parseCounter(ses, t)
+	createPkg(2) // invalidates the state of the package in ses
+	if _, err = exe(); err == nil {
+		t.Fatal("wanted ORA-04068 after the recompile, got nil error")
+	}
+	// the stale statement is purged from the cache: prepared and executed anew
+	before := parseCount()
+	if v, err = exe(); err != nil {
+		t.Fatal(err)
+	} else if v != 2 {
+		t.Errorf("got %d, wanted 2", v)
+	}
+	if after := parseCount(); after == before {
+		t.Error("the stale statement has been found in the statement cache")
+	}
+}
+
+func TestStmt_ExeReturningResults(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	stmt, err := ses.Prep(`DECLARE
+  c1 SYS_REFCURSOR;
+  c2 SYS_REFCURSOR;
+BEGIN
+  OPEN c1 FOR SELECT LEVEL FROM DUAL CONNECT BY LEVEL <= 2;
+  DBMS_SQL.RETURN_RESULT(c1);
+  OPEN c2 FOR SELECT 'a' FROM DUAL;
+  DBMS_SQL.RETURN_RESULT(c2);
+END;`)
+	testErr(err, t)
+	defer stmt.Close()
+	_, rsets, err := stmt.ExeReturningResults()
+	testErr(err, t)
+	if len(rsets) != 2 {
+		t.Fatalf("got %d results, wanted 2", len(rsets))
+	}
+	for i, want := range []int{2, 1} {
+		var n int
+		for rsets[i].Next() {
+			n++
+		}
+		testErr(rsets[i].Err(), t)
+		if n != want {
+			t.Errorf("%d. got %d rows, wanted %d", i, n, want)
+		}
+	}
+}
+
+func TestStmt_Exe_arrayNullPatterns(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	tableName := tableName()
+	_, err = ses.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), n NUMBER(10), s VARCHAR2(10), f BINARY_DOUBLE, d DATE, m NUMBER(10))")
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+
+	const rows = 100
+	rnd := rand.New(rand.NewSource(1))
+	ids := make([]int64, rows)
+	ns := make([]ora.Int64, rows)
+	ss := make([]ora.String, rows)
+	fs := make([]ora.Float64, rows)
+	ds := make([]ora.Time, rows)
+	ms := make([]int64, rows) // a plain slice, without NULLs
+	base := time.Date(2017, 1, 1, 0, 0, 0, 0, time.Local)
+	for i := range ids {
+		ids[i], ms[i] = int64(i), int64(i)
+		ns[i] = ora.Int64{IsNull: rnd.Intn(3) == 0, Value: int64(i)}
+		ss[i] = ora.String{IsNull: rnd.Intn(3) == 0, Value: fmt.Sprintf("s%d", i)}
+		fs[i] = ora.Float64{IsNull: rnd.Intn(3) == 0, Value: float64(i) / 2}
+		ds[i] = ora.Time{IsNull: rnd.Intn(3) == 0, Value: base.AddDate(0, 0, i)}
+	}
+	stmt, err := ses.Prep(fmt.Sprintf("INSERT INTO %v (id, n, s, f, d, m) VALUES (:1, :2, :3, :4, :5, :6)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	n, err := stmt.Exe(ids, ns, ss, fs, ds, ms)
+	testErr(err, t)
+	if n != rows {
+		t.Fatalf("inserted %d rows, wanted %d", n, rows)
+	}
+
+	qry, err := ses.Prep(fmt.Sprintf("SELECT id, n, s, f, d, m FROM %v ORDER BY id", tableName),
+		ora.I64, ora.OraI64, ora.OraS, ora.OraF64, ora.OraT, ora.OraI64)
+	testErr(err, t)
+	defer qry.Close()
+	rset, err := qry.Qry()
+	testErr(err, t)
+	var i int
+	for ; rset.Next(); i++ {
+		row := rset.Row
+		if got := row[1].(ora.Int64); got.IsNull != ns[i].IsNull || !got.IsNull && got.Value != ns[i].Value {
+			t.Errorf("%d. n: got %v, wanted %v", i, got, ns[i])
+		}
+		if got := row[2].(ora.String); got.IsNull != ss[i].IsNull || !got.IsNull && got.Value != ss[i].Value {
+			t.Errorf("%d. s: got %v, wanted %v", i, got, ss[i])
+		}
+		if got := row[3].(ora.Float64); got.IsNull != fs[i].IsNull || !got.IsNull && got.Value != fs[i].Value {
+			t.Errorf("%d. f: got %v, wanted %v", i, got, fs[i])
+		}
+		if got := row[4].(ora.Time); got.IsNull != ds[i].IsNull || !got.IsNull && !got.Value.Equal(ds[i].Value) {
+			t.Errorf("%d. d: got %v, wanted %v", i, got, ds[i])
+		}
+		if got := row[5].(ora.Int64); got.IsNull || got.Value != ms[i] {
+			t.Errorf("%d. m: got %v, wanted %d", i, got, ms[i])
+		}
+	}
+	testErr(rset.Err(), t)
+	if i != rows {
+		t.Errorf("got %d rows, wanted %d", i, rows)
+	}
+}
+
+func TestStmt_NumInput(t *testing.T) {
+	t.Parallel()
+	for sql, want := range map[string]int{
+		"SELECT 1 FROM DUAL":                 0,
+		"SELECT :1, :2 FROM DUAL":            2,
+		"BEGIN :a := :b; :c := :a + 1; END;": 3,
+	} {
+		stmt, err := testSes.Prep(sql)
+		testErr(err, t)
+		for i := 0; i < 2; i++ { // the second is cached
+			if got := stmt.NumInput(); got != want {
+				t.Errorf("%q: got %d, wanted %d", sql, got, want)
+			}
+		}
+		stmt.Close()
+	}
+}
+
+func TestStmt_ExeStructs(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), name VARCHAR2(10), amount NUMBER)")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	type row struct {
+		ID     int64
+		Name   ora.String
+		Amount float64
+		note   string
+	}
+	stmt, err := testSes.Prep(fmt.Sprintf("INSERT INTO %v (id, name, amount) VALUES (:1, :2, :3)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	n, err := stmt.ExeStructs([]row{{1, ora.String{Value: "a"}, 1.5, ""}, {2, ora.String{IsNull: true}, 2.5, ""}})
+	testErr(err, t)
+	if n != 2 {
+		t.Errorf("inserted %d rows, wanted 2", n)
+	}
+
+	// by tags, in a different order than the fields
+	type tagged struct {
+		Amount float64 `ora:"amt"`
+		Skip   string  `ora:"-"`
+		ID     int64   `ora:"id"`
+	}
+	stmt2, err := testSes.Prep(fmt.Sprintf("INSERT INTO %v (id, amount) VALUES (:id, :amt)", tableName))
+	testErr(err, t)
+	defer stmt2.Close()
+	n, err = stmt2.ExeStructs([]*tagged{{Amount: 3.5, ID: 3}})
+	testErr(err, t)
+	if n != 1 {
+		t.Errorf("inserted %d rows, wanted 1", n)
+	}
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT id, NVL(name, '-'), amount FROM %v ORDER BY id", tableName))
+	testErr(err, t)
+	var got []string
+	for rset.Next() {
+		got = append(got, fmt.Sprintf("%v/%v/%v", rset.Row...))
+	}
+	testErr(rset.Err(), t)
+	if want := "1/a/1.5 2/-/2.5 3/-/3.5"; strings.Join(got, " ") != want {
+		t.Errorf("got %q, wanted %q", strings.Join(got, " "), want)
+	}
+
+	if _, err = stmt.ExeStructs([]int{1}); err == nil {
+		t.Error("ExeStructs of []int succeeded")
+	}
+}
+
+func TestStmt_NoPrefetchForUpdate(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+	testErr(err, t)
+	_, err = stmt.Exe([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	stmt.Close()
+	testErr(err, t)
+
+	env, err := ora.OpenEnv()
+	defer env.Close()
+	testErr(err, t)
+	srv, err := env.OpenSrv(testSrvCfg)
+	defer srv.Close()
+	testErr(err, t)
+	ses, err := srv.OpenSes(testSesCfg)
+	defer ses.Close()
+	testErr(err, t)
+
+	qry := fmt.Sprintf("select c1 from %v order by c1 for update skip locked", tableName)
+	tx, err := ses.StartTx()
+	testErr(err, t)
+	defer tx.Rollback()
+	lockStmt, err := ses.Prep(qry)
+	testErr(err, t)
+	defer lockStmt.Close()
+	cfg := lockStmt.Cfg()
+	cfg.NoPrefetchForUpdate = true
+	lockStmt.SetCfg(cfg)
+	rset, err := lockStmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatalf("no rows: %v", rset.Err())
+	}
+	if got := rset.PrefetchedRows(); got != 0 {
+		t.Errorf("prefetched %d rows, wanted 0", got)
+	}
+
+	// only the fetched row is locked, the second session gets the rest
+	tx2, err := testSes.StartTx()
+	testErr(err, t)
+	defer tx2.Rollback()
+	rset2, err := testSes.PrepAndQry(qry)
+	testErr(err, t)
+	var n int
+	for rset2.Next() {
+		n++
+	}
+	testErr(rset2.Err(), t)
+	if n != 9 {
+		t.Errorf("got %d unlocked rows, wanted 9", n)
+	}
+}
+
+func TestSes_ExeResult(t *testing.T) {
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe(createTableSql(tableName, 1, numberP38S0Identity, varchar2C48)); err != nil {
+		t.Skipf("SKIP create table with identity: %v", err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	for i := int64(1); i <= 2; i++ {
+		res, err := testSes.ExeResult(fmt.Sprintf("INSERT INTO %v (c2) VALUES (:1) RETURNING c1 /*lastInsertId*/ INTO :c1", tableName), "go")
+		testErr(err, t)
+		if id, err := res.LastInsertId(); err != nil || id != i {
+			t.Errorf("LastInsertId: got %d (%v), wanted %d", id, err, i)
+		}
+		if n, err := res.RowsAffected(); err != nil || n != 1 {
+			t.Errorf("RowsAffected: got %d (%v), wanted 1", n, err)
+		}
+	}
+
+	res, err := testSes.ExeResult(fmt.Sprintf("UPDATE %v SET c2 = :1", tableName), "ora")
+	testErr(err, t)
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("RowsAffected: got %d, wanted 2", n)
+	}
+	if id, _ := res.LastInsertId(); id != 0 {
+		t.Errorf("LastInsertId: got %d, wanted 0", id)
+	}
+}
+
+func TestStmt_QryContext_charset(t *testing.T) {
+	stmt, err := testSes.Prep("SELECT 'café' FROM DUAL", ora.S)
+	testErr(err, t)
+	defer stmt.Close()
+	for charset, want := range map[string]string{
+		"":             "café",
+		"AL32UTF8":     "café",
+		"WE8ISO8859P1": "caf\xe9",
+	} {
+		rset, err := stmt.QryContext(ora.WithCharset(context.Background(), charset))
+		testErr(err, t)
+		for rset.Next() {
+			if got := rset.Row[0].(string); got != want {
+				t.Errorf("%q: got %q, wanted %q", charset, got, want)
+			}
+		}
+		testErr(rset.Err(), t)
+	}
+	if _, err = stmt.QryContext(ora.WithCharset(context.Background(), "NO_SUCH_CHARSET")); err == nil {
+		t.Error("wanted error for unknown character set")
+	}
+}
+
+func TestStmt_ResetBinds(t *testing.T) {
+	stmt, err := testSes.Prep("BEGIN :1 := :2 * 2; END;")
+	testErr(err, t)
+	defer stmt.Close()
+	var a, b int64
+	_, err = stmt.Exe(&a, int64(2))
+	testErr(err, t)
+	testErr(stmt.ResetBinds(), t)
+	_, err = stmt.Exe(&b, int64(5))
+	testErr(err, t)
+	if a != 4 || b != 10 {
+		t.Errorf("got a=%d b=%d, wanted a=4 b=10", a, b)
+	}
+
+	// fewer params than before
+	stmt2, err := testSes.Prep("SELECT COUNT(0) FROM DUAL WHERE 1 = :1", ora.I64)
+	testErr(err, t)
+	defer stmt2.Close()
+	for _, params := range [][]interface{}{
+		{int64(1), int64(2)}, // too many, fails
+		{int64(1)},
+	} {
+		rset, err := stmt2.Qry(params...)
+		if len(params) > 1 {
+			if err == nil {
+				t.Errorf("wanted error for %d params", len(params))
+			}
+			continue
+		}
+		testErr(err, t)
+		for rset.Next() {
+			if n := rset.Row[0].(int64); n != 1 {
+				t.Errorf("got %d, wanted 1", n)
+			}
+		}
+		testErr(rset.Err(), t)
+	}
+}
+
+func TestStmt_QryPage(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+	testErr(err, t)
+	_, err = stmt.Exe([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	stmt.Close()
+	testErr(err, t)
+
+	stmt, err = testSes.Prep(fmt.Sprintf("select c1 from %v where c1 > :1 order by c1", tableName), ora.I64)
+	testErr(err, t)
+	defer stmt.Close()
+	for _, tc := range []struct {
+		offset, limit int
+		want          string
+	}{
+		{0, 3, "2 3 4"},
+		{3, 3, "5 6 7"},
+		{6, 3, "8 9 10"},
+		{9, 3, ""},
+	} {
+		rset, err := stmt.QryPage(tc.offset, tc.limit, int64(1))
+		testErr(err, t)
+		var got []string
+		for rset.Next() {
+			i, ok := rset.Row[0].(int64)
+			if !ok {
+				t.Fatalf("%d/%d: got %T, wanted int64 (as ora.I64)", tc.offset, tc.limit, rset.Row[0])
+			}
+			got = append(got, fmt.Sprint(i))
+		}
+		testErr(rset.Err(), t)
+		if s := strings.Join(got, " "); s != tc.want {
+			t.Errorf("%d/%d: got %q, wanted %q", tc.offset, tc.limit, s, tc.want)
+		}
+	}
+}
+
+func TestStmt_BlockRowCount(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+	testErr(err, t)
+	_, err = stmt.Exe([]int64{1, 2, 3, 4, 5})
+	stmt.Close()
+	testErr(err, t)
+
+	cfg := testSes.Cfg()
+	defer testSes.SetCfg(cfg)
+	cfg.StmtCfg.BlockRowCount = true
+	testSes.SetCfg(cfg)
+	stmt, err = testSes.Prep(fmt.Sprintf("BEGIN UPDATE %v SET c1 = -c1 WHERE c1 > :1; END;", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	if n := stmt.NumInput(); n != 1 {
+		t.Errorf("NumInput got %d, wanted 1", n)
+	}
+	n, err := stmt.Exe(int64(2))
+	testErr(err, t)
+	if n != 3 {
+		t.Errorf("got %d rows affected, wanted 3", n)
+	}
+}
+
+func TestStmt_ExeContext_progress(t *testing.T) {
+	tableName, err := createTable(1, numberP38S0, testSes)
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+	stmt, err := testSes.Prep(fmt.Sprintf("insert into %v (c1) values (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	ids := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	var dones []string
+	ctx := ora.WithProgress(context.Background(), 3, func(done, total int) {
+		dones = append(dones, fmt.Sprintf("%d/%d", done, total))
+		if got := stmt.RowsProcessedSoFar(); got != uint64(done) {
+			t.Errorf("%d/%d: %d rows processed so far", done, total, got)
+		}
+	})
+	n, err := stmt.ExeContext(ctx, ids)
+	testErr(err, t)
+	if n != 10 {
+		t.Errorf("inserted %d rows, wanted 10", n)
+	}
+	if got, want := strings.Join(dones, " "), "3/10 6/10 9/10 10/10"; got != want {
+		t.Errorf("progress %q, wanted %q", got, want)
+	}
+
+	// cancel between the chunks: the auto-committed rows are rolled back
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = ora.WithProgress(ctx, 4, func(done, total int) { cancel() })
+	if _, err = stmt.ExeContext(ctx, ids); err == nil {
+		t.Fatal("cancelled execution succeeded")
+	}
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("select count(*) from %v", tableName), ora.I64)
+	testErr(err, t)
+	if rset.Next() && rset.Row[0].(int64) != 10 {
+		t.Errorf("got %d rows, wanted 10", rset.Row[0])
+	}
+	testErr(rset.Err(), t)
+}
+
+func TestStmt_bindDefinedTypes(t *testing.T) {
+	t.Parallel()
+	type status int
+	type label string
+	rset, err := testSes.PrepAndQry("SELECT :1 + 1, :2 || 'b' FROM DUAL", status(41), label("a"))
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if got := fmt.Sprintf("%v/%v", rset.Row...); got != "42/ab" {
+		t.Errorf("got %q, wanted 42/ab", got)
+	}
+}
+
+func TestStmt_BindDirections(t *testing.T) {
+	t.Parallel()
+	procName := tableName() + "_proc"
+	if _, err := testSes.PrepAndExe("CREATE OR REPLACE PROCEDURE " + procName + `(
+  p_in IN NUMBER, p_out OUT NUMBER, p_inout IN OUT NUMBER) IS
+BEGIN
+  p_out := p_in; p_inout := p_inout + 1;
+END;`); err != nil {
+		t.Fatal(err)
+	}
+	defer testSes.PrepAndExe("DROP PROCEDURE " + procName)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("BEGIN %s(:a, :b, p_inout => :c); :d := 1; END;", procName))
+	testErr(err, t)
+	defer stmt.Close()
+	dirs, err := stmt.BindDirections()
+	testErr(err, t)
+	want := []ora.BindDir{ora.BindIn, ora.BindOut, ora.BindInOut, ora.BindOut}
+	if fmt.Sprint(dirs) != fmt.Sprint(want) {
+		t.Errorf("got %v, wanted %v", dirs, want)
+	}
+}
+
+func TestStmt_ExeMerge(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	tableName := tableName()
+	_, err = ses.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), val NUMBER(3))")
+	testErr(err, t)
+	defer dropTable(tableName, ses, t)
+	_, err = ses.PrepAndExe(fmt.Sprintf("INSERT INTO %v (id, val) SELECT LEVEL, 0 FROM DUAL CONNECT BY LEVEL <= 3", tableName))
+	testErr(err, t)
+
+	// 2 and 3 exist, 4 and 5 are new
+	stmt, err := ses.Prep(fmt.Sprintf(`BEGIN
+  MERGE INTO %[1]v t
+    USING (SELECT LEVEL + :1 id FROM DUAL CONNECT BY LEVEL <= 4) s
+    ON (t.id = s.id)
+    WHEN MATCHED THEN UPDATE SET t.val = 1;
+  :ora_updated := SQL%%ROWCOUNT;
+  MERGE INTO %[1]v t
+    USING (SELECT LEVEL + :1 id FROM DUAL CONNECT BY LEVEL <= 4) s
+    ON (t.id = s.id)
+    WHEN NOT MATCHED THEN INSERT (id, val) VALUES (s.id, 2);
+  :ora_inserted := SQL%%ROWCOUNT;
+END;`, tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	inserted, updated, err := stmt.ExeMerge(int64(1))
+	testErr(err, t)
+	if inserted != 2 || updated != 2 {
+		t.Errorf("got %d inserted, %d updated, wanted 2 and 2", inserted, updated)
+	}
+
+	plain, err := ses.Prep(fmt.Sprintf("UPDATE %v SET val = 3", tableName))
+	testErr(err, t)
+	defer plain.Close()
+	if _, _, err = plain.ExeMerge(); err == nil {
+		t.Error("ExeMerge of an UPDATE: no error")
+	}
+}
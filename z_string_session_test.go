@@ -66,6 +66,71 @@ func TestMultiDefine_string(t *testing.T) {
 	}
 }
 
+func TestRTrimChar_session(t *testing.T) {
+	t.Parallel()
+	const qry = "SELECT CAST('a' AS CHAR(5)), CAST('b' AS NCHAR(5)), CAST('c  ' AS VARCHAR2(5)) FROM DUAL"
+	for _, trim := range []bool{true, false} {
+		stmt, err := testSes.Prep(qry)
+		testErr(err, t)
+		cfg := stmt.Cfg()
+		cfg.RTrimChar = trim
+		stmt.SetCfg(cfg)
+		rset, err := stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			stmt.Close()
+			t.Fatal(rset.Err())
+		}
+		want := []string{"a    ", "b    ", "c  "}
+		if trim {
+			want[0], want[1] = "a", "b"
+		}
+		for i, w := range want {
+			if got := rset.Row[i].(string); got != w {
+				t.Errorf("RTrimChar=%t %d. got %q, wanted %q", trim, i, got, w)
+			}
+		}
+		stmt.Close()
+	}
+}
+
+func TestMultibyteMaxLength_session(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (vc VARCHAR2(100 CHAR), nvc NVARCHAR2(100))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	want := strings.Repeat("\u20ac", 100) // 3 bytes each in UTF-8
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (vc, nvc) VALUES (:1, :2)", want, want); err != nil {
+		t.Fatal(err)
+	}
+	stmt, err := testSes.Prep("SELECT vc, nvc FROM " + tableName)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	for i, v := range rset.Row {
+		got := v.(string)
+		if strings.Trim(got, "?\u00bf") == "" {
+			t.Skipf("%d. database character set cannot store %q", i, want[:3])
+		}
+		if got != want {
+			t.Errorf("%d. got %q (%d bytes), wanted %d bytes", i, got, len(got), len(want))
+		}
+	}
+	if err = rset.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestWorkload_charB48_session(t *testing.T) {
 	for _, ctName := range _T_stringCols {
 		t.Run(ctName, func(t *testing.T) {
@@ -248,3 +313,132 @@ func TestStringSlice(t *testing.T) {
 	t.Log(rset.Row[0])
 
 }
+
+func TestXMLType_session(t *testing.T) {
+	t.Parallel()
+	rset, err := testSes.PrepAndQry("SELECT XMLTYPE('<a>1</a>') x, CAST(NULL AS XMLTYPE) y FROM DUAL")
+	testErr(err, t)
+	if got := rset.Columns[0].TypeName; got != ora.XMLTypeName {
+		t.Errorf("got type name %q, wanted %q", got, ora.XMLTypeName)
+	}
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	s, ok := rset.Row[0].(string)
+	if !ok || !strings.Contains(s, "<a>1</a>") {
+		t.Errorf("got %#v, wanted the XML as a string", rset.Row[0])
+	}
+	if rset.Row[1] != "" {
+		t.Errorf("NULL: got %#v, wanted empty string", rset.Row[1])
+	}
+	rset.Exhaust()
+
+	stmt, err := testSes.Prep("SELECT XMLTYPE('<a>1</a>') FROM DUAL", ora.Bin)
+	testErr(err, t)
+	defer stmt.Close()
+	if rset, err = stmt.Qry(); err != nil {
+		t.Fatal(err)
+	}
+	if rset.Next() {
+		if b, ok := rset.Row[0].([]byte); !ok || !strings.Contains(string(b), "<a>1</a>") {
+			t.Errorf("Bin: got %#v", rset.Row[0])
+		}
+	}
+	testErr(rset.Err(), t)
+
+	// other named types are rejected before fetching
+	if _, err = testSes.PrepAndQry("SELECT SYS.ODCIVARCHAR2LIST('a') FROM DUAL"); err == nil {
+		t.Error("collection column has been accepted")
+	} else if strings.Contains(err.Error(), "ORA-00932") {
+		t.Errorf("collection column failed at fetch: %v", err)
+	}
+}
+
+func TestXMLType_noObjectEnv(t *testing.T) {
+	cfg := ora.Cfg()
+	if !cfg.Object {
+		t.Fatal("Object is not the default")
+	}
+	defer ora.SetCfg(cfg)
+	noObj := cfg
+	noObj.Object = false
+	ora.SetCfg(noObj)
+	env, err := ora.OpenEnv()
+	ora.SetCfg(cfg)
+	testErr(err, t)
+	defer env.Close()
+	srv, err := env.OpenSrv(testSrvCfg)
+	testErr(err, t)
+	defer srv.Close()
+	ses, err := srv.OpenSes(testSesCfg)
+	testErr(err, t)
+	defer ses.Close()
+
+	rset, err := ses.PrepAndQry("SELECT XMLTYPE('<a>1</a>') FROM DUAL")
+	if err == nil {
+		if !rset.Next() {
+			err = rset.Err()
+		}
+	}
+	if err == nil || !strings.Contains(err.Error(), "DrvCfg.Object") {
+		t.Errorf("got %v, wanted a DrvCfg.Object error", err)
+	}
+}
+
+func TestBindPtr_string_shortOut(t *testing.T) {
+	t.Parallel()
+	stmt, err := testSes.Prep(`DECLARE
+  PROCEDURE p(p_out OUT VARCHAR2) IS
+  BEGIN
+    p_out := 'árvíz';
+  END;
+BEGIN
+  p(:1);
+END;`)
+	testErr(err, t)
+	defer stmt.Close()
+	cfg := stmt.Cfg().SetStringPtrBufferSize(8000)
+	stmt.SetCfg(cfg)
+
+	// the input is longer than the output
+	out := strings.Repeat("x", 1000)
+	_, err = stmt.Exe(&out)
+	testErr(err, t)
+	if want := "árvíz"; out != want {
+		t.Errorf("got %q (%d bytes), wanted %q (%d bytes)", out, len(out), want, len(want))
+	}
+
+	var s ora.String
+	_, err = stmt.Exe(&s)
+	testErr(err, t)
+	if s.IsNull || s.Value != "árvíz" {
+		t.Errorf("got %#v, wanted árvíz", s)
+	}
+}
+
+func TestStringBindBytes(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	_, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (c1 VARCHAR2(4000 BYTE))")
+	testErr(err, t)
+	defer dropTable(tableName, testSes, t)
+
+	stmt, err := testSes.Prep(fmt.Sprintf("INSERT INTO %v (c1) VALUES (:1)", tableName))
+	testErr(err, t)
+	defer stmt.Close()
+	cfg := stmt.Cfg()
+	cfg.StringBindBytes = true
+	stmt.SetCfg(cfg)
+	value := strings.Repeat("é", 2000) // 4000 bytes in AL32UTF8
+	_, err = stmt.Exe(value)
+	testErr(err, t)
+
+	rset, err := testSes.PrepAndQry(fmt.Sprintf("SELECT LENGTHB(c1) FROM %v", tableName))
+	testErr(err, t)
+	for rset.Next() {
+		if n := rset.Row[0]; fmt.Sprintf("%v", n) != "4000" {
+			t.Errorf("got %v bytes, wanted 4000", n)
+		}
+	}
+	testErr(rset.Err(), t)
+}
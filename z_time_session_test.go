@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	ora "gopkg.in/rana/ora.v4"
 )
@@ -63,6 +64,84 @@ func TestBindDefine_time(t *testing.T) {
 	}
 }
 
+func TestTimeZoneRegion_session(t *testing.T) {
+	const qry = "SELECT TO_TIMESTAMP_TZ('2017-03-12 12:00:00 America/New_York', 'YYYY-MM-DD HH24:MI:SS TZR') FROM DUAL"
+	for _, region := range []bool{true, false} {
+		stmt, err := testSes.Prep(qry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg := stmt.Cfg()
+		cfg.TimeZoneRegion = region
+		stmt.SetCfg(cfg)
+		rset, err := stmt.Qry()
+		if err != nil {
+			stmt.Close()
+			t.Fatal(err)
+		}
+		if !rset.Next() {
+			stmt.Close()
+			t.Fatal(rset.Err())
+		}
+		got := rset.Row[0].(time.Time)
+		stmt.Close()
+		name := got.Location().String()
+		if region && name != "America/New_York" {
+			t.Errorf("region: got location %q, wanted America/New_York", name)
+		} else if !region && name != "-04:00" {
+			t.Errorf("offset: got location %q, wanted -04:00", name)
+		}
+	}
+}
+
+func TestTimeSliceZones_session(t *testing.T) {
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), ts TIMESTAMP WITH TIME ZONE)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	base := time.Date(2017, 3, 12, 12, 0, 0, 0, time.UTC)
+	times := []time.Time{
+		base,
+		base.In(time.FixedZone("", 2*3600)),
+		base.In(time.FixedZone("", -5*3600-30*60)),
+		base.In(time.FixedZone("", 9*3600)),
+	}
+	ids := make([]int64, len(times))
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, ts) VALUES (:1, :2)", ids, times); err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := testSes.Prep("SELECT id, ts FROM " + tableName + " ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	cfg := stmt.Cfg()
+	cfg.TimeZoneRegion = false
+	stmt.SetCfg(cfg)
+	rset, err := stmt.Qry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rset.Next() {
+		i := int(rset.Row[0].(int64))
+		got := rset.Row[1].(time.Time)
+		_, gotOff := got.Zone()
+		_, wantOff := times[i].Zone()
+		if !got.Equal(times[i]) || gotOff != wantOff {
+			t.Errorf("%d. got %s, wanted %s", i, got, times[i])
+		}
+	}
+	if err := rset.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestMultiDefine_date_session(t *testing.T) {
 	for _, ctName := range []string{"date"} {
 		t.Run(ctName, func(t *testing.T) {
@@ -78,3 +157,117 @@ func TestWorkload_date_session(t *testing.T) {
 		})
 	}
 }
+
+func TestEpoch_session(t *testing.T) {
+	t.Parallel()
+	const secs, millis = 1500000000, 1500000000123
+	stmt, err := testSes.Prep(
+		"SELECT CAST(:1 AS TIMESTAMP(3) WITH TIME ZONE), CAST(:2 AS TIMESTAMP(3) WITH TIME ZONE), CAST(NULL AS DATE) FROM DUAL",
+		ora.TUnix, ora.TUnixMs, ora.TUnix,
+	)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry(ora.EpochSeconds(secs), ora.EpochMillis(millis))
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	if got, ok := rset.Row[0].(int64); !ok || got != secs {
+		t.Errorf("TUnix got %#v, wanted %d", rset.Row[0], secs)
+	}
+	if got, ok := rset.Row[1].(int64); !ok || got != millis {
+		t.Errorf("TUnixMs got %#v, wanted %d", rset.Row[1], millis)
+	}
+	if rset.Row[2] != nil {
+		t.Errorf("NULL got %#v, wanted nil", rset.Row[2])
+	}
+}
+
+func TestDateSliceNulls_session(t *testing.T) {
+	t.Parallel()
+	tableName := tableName()
+	if _, err := testSes.PrepAndExe("CREATE TABLE " + tableName + " (id NUMBER(3), d DATE)"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropTable(tableName, testSes, t)
+
+	d1 := time.Date(2017, 7, 14, 15, 16, 17, 0, time.Local)
+	d3 := time.Date(1999, 12, 31, 23, 59, 59, 0, time.Local)
+	ids := []int64{1, 2, 3, 4}
+	times := []*time.Time{&d1, nil, &d3, nil}
+	if _, err := testSes.PrepAndExe("INSERT INTO "+tableName+" (id, d) VALUES (:1, :2)", ids, times); err != nil {
+		t.Fatal(err)
+	}
+
+	rset, err := testSes.PrepAndQry("SELECT id, TO_CHAR(d, 'YYYY-MM-DD HH24:MI:SS') FROM "+tableName+" ORDER BY id", ora.I64, ora.OraS)
+	testErr(err, t)
+	want := []string{"2017-07-14 15:16:17", "", "1999-12-31 23:59:59", ""}
+	var i int
+	for ; rset.Next(); i++ {
+		got := rset.Row[1].(ora.String)
+		if got.IsNull != (want[i] == "") || got.Value != want[i] {
+			t.Errorf("%d. got %#v, wanted %q", i, got, want[i])
+		}
+	}
+	testErr(rset.Err(), t)
+	if i != len(want) {
+		t.Errorf("got %d rows, wanted %d", i, len(want))
+	}
+}
+
+func TestTimeAs_session(t *testing.T) {
+	t.Parallel()
+	tm := time.Date(2017, 7, 14, 15, 16, 17, 123456000, time.FixedZone("", 5*3600))
+	stmt, err := testSes.Prep(`SELECT DUMP(:1), TO_CHAR(:2, 'YYYY-MM-DD HH24:MI:SS'),
+    DUMP(:3), TO_CHAR(:4, 'YYYY-MM-DD HH24:MI:SS.FF3'),
+    DUMP(:5), TO_CHAR(:6, 'YYYY-MM-DD HH24:MI:SS.FF3 TZH:TZM')
+  FROM DUAL`, ora.S, ora.S, ora.S, ora.S, ora.S, ora.S)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry(
+		ora.AsDate(tm), ora.AsDate(tm),
+		ora.AsTimestamp(tm), ora.AsTimestamp(tm),
+		ora.AsTimestampTZ(tm), ora.AsTimestampTZ(tm),
+	)
+	testErr(err, t)
+	if !rset.Next() {
+		t.Fatal(rset.Err())
+	}
+	for i, want := range []struct{ typ, value string }{
+		{"Typ=12 ", "2017-07-14 15:16:17"},
+		{"Typ=180 ", "2017-07-14 15:16:17.123"},
+		{"Typ=181 ", "2017-07-14 15:16:17.123 +05:00"},
+	} {
+		if typ := rset.Row[2*i].(string); !strings.HasPrefix(typ, want.typ) {
+			t.Errorf("%d. got %q, wanted %q", i, typ, want.typ)
+		}
+		if value := rset.Row[2*i+1].(string); value != want.value {
+			t.Errorf("%d. got %q, wanted %q", i, value, want.value)
+		}
+	}
+}
+
+func TestDateNls(t *testing.T) {
+	t.Parallel()
+	ses, err := testSesPool.Get()
+	testErr(err, t)
+	defer ses.Close()
+	_, err = ses.PrepAndExe("ALTER SESSION SET NLS_DATE_FORMAT = 'DD.MM.YYYY HH24:MI'")
+	testErr(err, t)
+	defer ses.PrepAndExe("ALTER SESSION SET NLS_DATE_FORMAT = 'YYYY-MM-DD\"T\"HH24:MI:SS'")
+
+	stmt, err := ses.Prep("SELECT TO_DATE('2017-03-04 05:06', 'YYYY-MM-DD HH24:MI'), CAST(NULL AS DATE) FROM DUAL", ora.TNls, ora.TNls)
+	testErr(err, t)
+	defer stmt.Close()
+	rset, err := stmt.Qry()
+	testErr(err, t)
+	for rset.Next() {
+		if got, want := rset.Row[0], "04.03.2017 05:06"; got != want {
+			t.Errorf("got %#v, wanted %q", got, want)
+		}
+		if got := rset.Row[1]; got != "" {
+			t.Errorf("got %#v for NULL, wanted empty string", got)
+		}
+	}
+	testErr(rset.Err(), t)
+}